@@ -15,3 +15,9 @@ output "secret" {
     revision_date   = resource.bitwarden-sm_secret.secret.revision_date
   }
 }
+
+# Secrets containing a JSON document can be accessed as structured value
+output "secret_client_email" {
+  value     = data.bitwarden-sm_secret.secret.value_json.client_email
+  sensitive = true
+}
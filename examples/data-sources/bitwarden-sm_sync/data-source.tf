@@ -0,0 +1,12 @@
+# Checks whether any secret changed since the last deployment of dependent infrastructure.
+data "bitwarden-sm_sync" "since_last_deployment" {
+  last_synced_date = var.last_deployment_date
+}
+
+output "redeploy_required" {
+  value = data.bitwarden-sm_sync.since_last_deployment.has_changes
+}
+
+output "next_last_deployment_date" {
+  value = data.bitwarden-sm_sync.since_last_deployment.synced_at
+}
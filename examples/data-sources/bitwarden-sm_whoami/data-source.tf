@@ -0,0 +1,16 @@
+data "bitwarden-sm_whoami" "current" {}
+
+# Fails the plan if the provider runs with a machine account that cannot write the secrets of the project.
+resource "bitwarden-sm_secret" "database_password" {
+  key        = "DATABASE_PASSWORD"
+  project_id = var.project_id
+
+  lifecycle {
+    precondition {
+      condition = anytrue([
+        for project in data.bitwarden-sm_whoami.current.projects : project.id == var.project_id && project.write
+      ])
+      error_message = "The machine account ${data.bitwarden-sm_whoami.current.machine_account_id} needs write access to the project."
+    }
+  }
+}
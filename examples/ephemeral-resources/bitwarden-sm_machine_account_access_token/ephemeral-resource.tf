@@ -0,0 +1,18 @@
+# Creates an access token for the machine account of the deployment, which is revoked at the end of the run.
+ephemeral "bitwarden-sm_machine_account_access_token" "deployment" {
+  machine_account_id = var.deployment_machine_account_id
+  name               = "terraform-run"
+}
+
+# Reads the secrets of the deployment as its machine account, without storing the access token in the state.
+provider "bitwarden-sm" {
+  alias           = "deployment"
+  api_url         = "https://api.bitwarden.com"
+  identity_url    = "https://identity.bitwarden.com"
+  access_token    = ephemeral.bitwarden-sm_machine_account_access_token.deployment.access_token
+  organization_id = var.organization_id
+}
+
+data "bitwarden-sm_list_secrets" "deployment" {
+  provider = bitwarden-sm.deployment
+}
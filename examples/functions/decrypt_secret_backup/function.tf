@@ -0,0 +1,15 @@
+# Restores a secret deleted by the provider from the copy written to its backup_dir. The result is sensitive, since
+# the backup key is passed as a sensitive variable.
+locals {
+  deleted_secret = provider::bitwarden-sm::decrypt_secret_backup(
+    file("${path.root}/.secret-backups/${var.deleted_secret_file}"),
+    var.backup_key,
+  )
+}
+
+resource "bitwarden-sm_secret" "restored" {
+  key        = local.deleted_secret.key
+  value      = local.deleted_secret.value
+  note       = local.deleted_secret.note
+  project_id = local.deleted_secret.project_id
+}
@@ -0,0 +1,14 @@
+# Generates a password with the same policy as the secrets of the project and stores it in the state of a
+# resource which does not support generated values itself.
+resource "terraform_data" "database_password" {
+  input = provider::bitwarden-sm::generate_password({
+    length             = 32
+    special            = true
+    special_characters = "-_"
+    avoid_ambiguous    = true
+  })
+
+  lifecycle {
+    ignore_changes = [input]
+  }
+}
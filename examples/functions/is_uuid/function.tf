@@ -0,0 +1,8 @@
+variable "project_id" {
+  type = string
+
+  validation {
+    condition     = provider::bitwarden-sm::is_uuid(var.project_id)
+    error_message = "The project_id must be a valid UUID."
+  }
+}
@@ -0,0 +1,8 @@
+locals {
+  database_password = provider::bitwarden-sm::parse_reference("bws://${var.project_id}/DATABASE_PASSWORD")
+}
+
+resource "bitwarden-sm_secret" "database_password" {
+  key        = local.database_password.key
+  project_id = local.database_password.project
+}
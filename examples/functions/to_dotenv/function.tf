@@ -0,0 +1,11 @@
+data "bitwarden-sm_secret" "app" {
+  for_each = toset(var.app_secret_ids)
+  id       = each.value
+}
+
+resource "local_sensitive_file" "dotenv" {
+  filename = "${path.module}/.env"
+  content = provider::bitwarden-sm::to_dotenv({
+    for secret in data.bitwarden-sm_secret.app : secret.key => secret.value
+  })
+}
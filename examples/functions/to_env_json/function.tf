@@ -0,0 +1,11 @@
+data "bitwarden-sm_secret" "app" {
+  for_each = toset(var.app_secret_ids)
+  id       = each.value
+}
+
+resource "local_sensitive_file" "env_json" {
+  filename = "${path.module}/env.json"
+  content = provider::bitwarden-sm::to_env_json({
+    for secret in data.bitwarden-sm_secret.app : secret.key => secret.value
+  })
+}
@@ -0,0 +1,11 @@
+data "bitwarden-sm_secret" "app" {
+  for_each = toset(var.app_secret_ids)
+  id       = each.value
+}
+
+resource "local_sensitive_file" "k8s_secret" {
+  filename = "${path.module}/app-secrets.yaml"
+  content = provider::bitwarden-sm::to_k8s_secret_yaml("app-secrets", {
+    for secret in data.bitwarden-sm_secret.app : secret.key => secret.value
+  })
+}
@@ -0,0 +1,9 @@
+# Creates a machine account for a workload and grants it read access to a project.
+resource "bitwarden-sm_machine_account" "payment_service" {
+  name = "payment-service"
+}
+
+resource "bitwarden-sm_project_machine_account_access" "payment_service" {
+  project_id         = var.project_id
+  machine_account_id = bitwarden-sm_machine_account.payment_service.id
+}
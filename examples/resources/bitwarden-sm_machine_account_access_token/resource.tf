@@ -0,0 +1,17 @@
+# Creates an access token for the machine account of a workload, which expires at the end of the year.
+resource "bitwarden-sm_machine_account_access_token" "payment_service" {
+  machine_account_id = bitwarden-sm_machine_account.payment_service.id
+  name               = "payment-service-production"
+  expires_at         = "2030-12-31T23:59:59Z"
+}
+
+# Hands the access token over to the workload.
+resource "kubernetes_secret" "payment_service" {
+  metadata {
+    name = "bitwarden-access-token"
+  }
+
+  data = {
+    BWS_ACCESS_TOKEN = bitwarden-sm_machine_account_access_token.payment_service.access_token
+  }
+}
@@ -0,0 +1,7 @@
+# Grants the platform team read and write access to the secrets of the project.
+resource "bitwarden-sm_project_group_access" "platform_team" {
+  project_id = var.project_id
+  group_id   = var.platform_team_group_id
+  read       = true
+  write      = true
+}
@@ -0,0 +1,7 @@
+# Grants the machine account of a workload read access to the secrets of the project.
+resource "bitwarden-sm_project_machine_account_access" "payment_service" {
+  project_id         = var.project_id
+  machine_account_id = var.payment_service_machine_account_id
+  read               = true
+  write              = false
+}
@@ -75,3 +75,32 @@ resource "local_file" "service_account_token" {
 EOF
   filename = "${path.module}/service_account_token.json"
 }
+
+# Custom special characters and exclusions allow generating values for targets
+# with character restrictions, e.g. connection strings which must not contain "@" or "#".
+resource "bitwarden-sm_secret" "connection_string_password" {
+  key                = "db_connection_string_password"
+  project_id         = var.project_id
+  length             = 48
+  special            = true
+  min_special        = 12
+  special_characters = "-_.~"
+  exclude_characters = "@#"
+}
+
+# Structured values are stored as normalised JSON document.
+resource "bitwarden-sm_secret" "service_account_key" {
+  key        = "service_account_key"
+  project_id = var.project_id
+  value_json = {
+    client_email = "deployer@example.com"
+    private_key  = var.private_key
+  }
+}
+
+# Secrets which must only be changed via terraform revert changes made outside of terraform.
+resource "bitwarden-sm_secret" "api_signing_key" {
+  key          = "api_signing_key"
+  project_id   = var.project_id
+  drift_policy = "revert"
+}
@@ -0,0 +1,28 @@
+# All environment variables of a service are managed by a single resource.
+# Secrets added to the map are created, changed values are updated and removed keys are deleted.
+resource "bitwarden-sm_secrets" "service_environment" {
+  project_id = var.project_id
+  secrets = {
+    DATABASE_URL = var.database_url
+    API_TOKEN    = var.api_token
+    LOG_LEVEL    = "info"
+  }
+  notes = {
+    API_TOKEN = "Rotated quarterly"
+  }
+}
+
+output "api_token_secret_id" {
+  value = resource.bitwarden-sm_secrets.service_environment.secret_ids["API_TOKEN"]
+}
+
+# An exclusive resource owns the whole project. Secrets added to the project
+# outside of terraform are deleted with the next apply.
+resource "bitwarden-sm_secrets" "payment_service_environment" {
+  project_id       = var.payment_project_id
+  exclusive        = true
+  unmanaged_action = "delete"
+  secrets = {
+    STRIPE_API_KEY = var.stripe_api_key
+  }
+}
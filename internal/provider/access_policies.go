@@ -0,0 +1,266 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// accessPolicy grants a machine account, group or user access to a project.
+type accessPolicy struct {
+	GranteeID string
+	Read      bool
+	Write     bool
+}
+
+// projectAccessPoliciesInterface manages the access policies of projects. Get returns nil if the grantee has no
+// access to the project.
+type projectAccessPoliciesInterface interface {
+	GetMachineAccountAccess(ctx context.Context, projectID string, machineAccountID string) (*accessPolicy, error)
+	SetMachineAccountAccess(ctx context.Context, projectID string, policy accessPolicy) (*accessPolicy, error)
+	DeleteMachineAccountAccess(ctx context.Context, projectID string, machineAccountID string) error
+	GetGroupAccess(ctx context.Context, projectID string, groupID string) (*accessPolicy, error)
+	SetGroupAccess(ctx context.Context, projectID string, policy accessPolicy) (*accessPolicy, error)
+	DeleteGroupAccess(ctx context.Context, projectID string, groupID string) error
+}
+
+type accessPolicyRequest struct {
+	GranteeID string `json:"granteeId"`
+	Read      bool   `json:"read"`
+	Write     bool   `json:"write"`
+}
+
+type machineAccountAccessPoliciesResponse struct {
+	ServiceAccountAccessPolicies []struct {
+		ServiceAccountID string `json:"serviceAccountId"`
+		Read             bool   `json:"read"`
+		Write            bool   `json:"write"`
+	} `json:"serviceAccountAccessPolicies"`
+}
+
+type machineAccountAccessPoliciesRequest struct {
+	ServiceAccountAccessPolicyRequests []accessPolicyRequest `json:"serviceAccountAccessPolicyRequests"`
+}
+
+type peopleAccessPoliciesResponse struct {
+	UserAccessPolicies []struct {
+		OrganizationUserID string `json:"organizationUserId"`
+		Read               bool   `json:"read"`
+		Write              bool   `json:"write"`
+	} `json:"userAccessPolicies"`
+	GroupAccessPolicies []struct {
+		GroupID string `json:"groupId"`
+		Read    bool   `json:"read"`
+		Write   bool   `json:"write"`
+	} `json:"groupAccessPolicies"`
+}
+
+type peopleAccessPoliciesRequest struct {
+	UserAccessPolicyRequests  []accessPolicyRequest `json:"userAccessPolicyRequests"`
+	GroupAccessPolicyRequests []accessPolicyRequest `json:"groupAccessPolicyRequests"`
+}
+
+// projectAccessPolicies implements projectAccessPoliciesInterface. The API only supports replacing all machine
+// account or all people access policies of a project, so every change is a read-modify-write cycle.
+type projectAccessPolicies struct {
+	client *httpAPIClient
+}
+
+func machineAccountAccessPoliciesPath(projectID string) string {
+	return fmt.Sprintf("/projects/%s/access-policies/service-accounts", url.PathEscape(projectID))
+}
+
+func peopleAccessPoliciesPath(projectID string) string {
+	return fmt.Sprintf("/projects/%s/access-policies/people", url.PathEscape(projectID))
+}
+
+func (p *projectAccessPolicies) machineAccountPolicies(ctx context.Context, projectID string) ([]accessPolicy, error) {
+	var response machineAccountAccessPoliciesResponse
+	if err := p.client.do(ctx, http.MethodGet, machineAccountAccessPoliciesPath(projectID), nil, &response); err != nil {
+		return nil, err
+	}
+
+	policies := make([]accessPolicy, 0, len(response.ServiceAccountAccessPolicies))
+	for _, policy := range response.ServiceAccountAccessPolicies {
+		policies = append(policies, accessPolicy{GranteeID: policy.ServiceAccountID, Read: policy.Read, Write: policy.Write})
+	}
+	return policies, nil
+}
+
+func (p *projectAccessPolicies) putMachineAccountPolicies(ctx context.Context, projectID string, policies []accessPolicy) ([]accessPolicy, error) {
+	request := machineAccountAccessPoliciesRequest{ServiceAccountAccessPolicyRequests: accessPolicyRequests(policies)}
+
+	var response machineAccountAccessPoliciesResponse
+	if err := p.client.do(ctx, http.MethodPut, machineAccountAccessPoliciesPath(projectID), request, &response); err != nil {
+		return nil, err
+	}
+
+	updated := make([]accessPolicy, 0, len(response.ServiceAccountAccessPolicies))
+	for _, policy := range response.ServiceAccountAccessPolicies {
+		updated = append(updated, accessPolicy{GranteeID: policy.ServiceAccountID, Read: policy.Read, Write: policy.Write})
+	}
+	return updated, nil
+}
+
+func (p *projectAccessPolicies) peoplePolicies(ctx context.Context, projectID string) (users []accessPolicy, groups []accessPolicy, err error) {
+	var response peopleAccessPoliciesResponse
+	if err := p.client.do(ctx, http.MethodGet, peopleAccessPoliciesPath(projectID), nil, &response); err != nil {
+		return nil, nil, err
+	}
+	users, groups = response.policies()
+	return users, groups, nil
+}
+
+func (p *projectAccessPolicies) putPeoplePolicies(ctx context.Context, projectID string, users []accessPolicy, groups []accessPolicy) ([]accessPolicy, error) {
+	request := peopleAccessPoliciesRequest{
+		UserAccessPolicyRequests:  accessPolicyRequests(users),
+		GroupAccessPolicyRequests: accessPolicyRequests(groups),
+	}
+
+	var response peopleAccessPoliciesResponse
+	if err := p.client.do(ctx, http.MethodPut, peopleAccessPoliciesPath(projectID), request, &response); err != nil {
+		return nil, err
+	}
+	_, updatedGroups := response.policies()
+	return updatedGroups, nil
+}
+
+func (r peopleAccessPoliciesResponse) policies() (users []accessPolicy, groups []accessPolicy) {
+	users = make([]accessPolicy, 0, len(r.UserAccessPolicies))
+	for _, policy := range r.UserAccessPolicies {
+		users = append(users, accessPolicy{GranteeID: policy.OrganizationUserID, Read: policy.Read, Write: policy.Write})
+	}
+	groups = make([]accessPolicy, 0, len(r.GroupAccessPolicies))
+	for _, policy := range r.GroupAccessPolicies {
+		groups = append(groups, accessPolicy{GranteeID: policy.GroupID, Read: policy.Read, Write: policy.Write})
+	}
+	return users, groups
+}
+
+func (p *projectAccessPolicies) GetMachineAccountAccess(ctx context.Context, projectID string, machineAccountID string) (*accessPolicy, error) {
+	policies, err := p.machineAccountPolicies(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	return findAccessPolicy(policies, machineAccountID), nil
+}
+
+func (p *projectAccessPolicies) SetMachineAccountAccess(ctx context.Context, projectID string, policy accessPolicy) (*accessPolicy, error) {
+	unlock := p.client.lockProject(projectID)
+	defer unlock()
+
+	policies, err := p.machineAccountPolicies(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := p.putMachineAccountPolicies(ctx, projectID, replaceAccessPolicy(policies, policy.GranteeID, &policy))
+	if err != nil {
+		return nil, err
+	}
+	return requireAccessPolicy(updated, policy.GranteeID)
+}
+
+func (p *projectAccessPolicies) DeleteMachineAccountAccess(ctx context.Context, projectID string, machineAccountID string) error {
+	unlock := p.client.lockProject(projectID)
+	defer unlock()
+
+	policies, err := p.machineAccountPolicies(ctx, projectID)
+	if err != nil {
+		return err
+	}
+	if findAccessPolicy(policies, machineAccountID) == nil {
+		return nil
+	}
+
+	_, err = p.putMachineAccountPolicies(ctx, projectID, replaceAccessPolicy(policies, machineAccountID, nil))
+	return err
+}
+
+func (p *projectAccessPolicies) GetGroupAccess(ctx context.Context, projectID string, groupID string) (*accessPolicy, error) {
+	_, groups, err := p.peoplePolicies(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	return findAccessPolicy(groups, groupID), nil
+}
+
+func (p *projectAccessPolicies) SetGroupAccess(ctx context.Context, projectID string, policy accessPolicy) (*accessPolicy, error) {
+	unlock := p.client.lockProject(projectID)
+	defer unlock()
+
+	users, groups, err := p.peoplePolicies(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := p.putPeoplePolicies(ctx, projectID, users, replaceAccessPolicy(groups, policy.GranteeID, &policy))
+	if err != nil {
+		return nil, err
+	}
+	return requireAccessPolicy(updated, policy.GranteeID)
+}
+
+func (p *projectAccessPolicies) DeleteGroupAccess(ctx context.Context, projectID string, groupID string) error {
+	unlock := p.client.lockProject(projectID)
+	defer unlock()
+
+	users, groups, err := p.peoplePolicies(ctx, projectID)
+	if err != nil {
+		return err
+	}
+	if findAccessPolicy(groups, groupID) == nil {
+		return nil
+	}
+
+	_, err = p.putPeoplePolicies(ctx, projectID, users, replaceAccessPolicy(groups, groupID, nil))
+	return err
+}
+
+func accessPolicyRequests(policies []accessPolicy) []accessPolicyRequest {
+	requests := make([]accessPolicyRequest, 0, len(policies))
+	for _, policy := range policies {
+		requests = append(requests, accessPolicyRequest(policy))
+	}
+	return requests
+}
+
+func findAccessPolicy(policies []accessPolicy, granteeID string) *accessPolicy {
+	for _, policy := range policies {
+		if policy.GranteeID == granteeID {
+			return &policy
+		}
+	}
+	return nil
+}
+
+// requireAccessPolicy returns the access policy of the grantee from an API response which must contain it.
+func requireAccessPolicy(policies []accessPolicy, granteeID string) (*accessPolicy, error) {
+	policy := findAccessPolicy(policies, granteeID)
+	if policy == nil {
+		return nil, fmt.Errorf("access policy for grantee %s is missing in the API response", granteeID)
+	}
+	return policy, nil
+}
+
+// replaceAccessPolicy returns policies with the access policy of the grantee replaced by policy. A nil policy
+// removes the grantee, a grantee without access policy is appended.
+func replaceAccessPolicy(policies []accessPolicy, granteeID string, policy *accessPolicy) []accessPolicy {
+	replaced := make([]accessPolicy, 0, len(policies)+1)
+	found := false
+	for _, current := range policies {
+		if current.GranteeID != granteeID {
+			replaced = append(replaced, current)
+			continue
+		}
+		found = true
+		if policy != nil {
+			replaced = append(replaced, *policy)
+		}
+	}
+	if !found && policy != nil {
+		replaced = append(replaced, *policy)
+	}
+	return replaced
+}
@@ -0,0 +1,251 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+// accessPolicyStandIn stores the access policies of projects in memory and serves the access policy endpoints.
+type accessPolicyStandIn struct {
+	*apiStandIn
+
+	mu              sync.Mutex
+	machineAccounts map[string][]accessPolicyRequest
+	users           map[string][]accessPolicyRequest
+	groups          map[string][]accessPolicyRequest
+}
+
+func newAccessPolicyStandIn(t *testing.T) *accessPolicyStandIn {
+	standIn := &accessPolicyStandIn{
+		apiStandIn:      newAPIStandIn(t),
+		machineAccounts: map[string][]accessPolicyRequest{},
+		users:           map[string][]accessPolicyRequest{},
+		groups:          map[string][]accessPolicyRequest{},
+	}
+
+	standIn.handle("GET /projects/{id}/access-policies/service-accounts", func(w http.ResponseWriter, r *http.Request) {
+		standIn.mu.Lock()
+		defer standIn.mu.Unlock()
+		writeJSON(w, http.StatusOK, standIn.machineAccountResponse(r.PathValue("id")))
+	})
+	standIn.handle("PUT /projects/{id}/access-policies/service-accounts", func(w http.ResponseWriter, r *http.Request) {
+		var request machineAccountAccessPoliciesRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil || !validAccessPolicyRequests(request.ServiceAccountAccessPolicyRequests) {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"message": "Invalid access policy."})
+			return
+		}
+		standIn.mu.Lock()
+		defer standIn.mu.Unlock()
+		standIn.machineAccounts[r.PathValue("id")] = request.ServiceAccountAccessPolicyRequests
+		writeJSON(w, http.StatusOK, standIn.machineAccountResponse(r.PathValue("id")))
+	})
+	standIn.handle("GET /projects/{id}/access-policies/people", func(w http.ResponseWriter, r *http.Request) {
+		standIn.mu.Lock()
+		defer standIn.mu.Unlock()
+		writeJSON(w, http.StatusOK, standIn.peopleResponse(r.PathValue("id")))
+	})
+	standIn.handle("PUT /projects/{id}/access-policies/people", func(w http.ResponseWriter, r *http.Request) {
+		var request peopleAccessPoliciesRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil ||
+			!validAccessPolicyRequests(request.UserAccessPolicyRequests) || !validAccessPolicyRequests(request.GroupAccessPolicyRequests) {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"message": "Invalid access policy."})
+			return
+		}
+		standIn.mu.Lock()
+		defer standIn.mu.Unlock()
+		standIn.users[r.PathValue("id")] = request.UserAccessPolicyRequests
+		standIn.groups[r.PathValue("id")] = request.GroupAccessPolicyRequests
+		writeJSON(w, http.StatusOK, standIn.peopleResponse(r.PathValue("id")))
+	})
+
+	return standIn
+}
+
+// validAccessPolicyRequests mirrors the validation of the API, which rejects write access without read access.
+func validAccessPolicyRequests(requests []accessPolicyRequest) bool {
+	for _, request := range requests {
+		if request.Write && !request.Read {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *accessPolicyStandIn) machineAccountResponse(projectID string) map[string]any {
+	policies := []map[string]any{}
+	for _, policy := range s.machineAccounts[projectID] {
+		policies = append(policies, map[string]any{"serviceAccountId": policy.GranteeID, "read": policy.Read, "write": policy.Write})
+	}
+	return map[string]any{"serviceAccountAccessPolicies": policies}
+}
+
+func (s *accessPolicyStandIn) peopleResponse(projectID string) map[string]any {
+	users := []map[string]any{}
+	for _, policy := range s.users[projectID] {
+		users = append(users, map[string]any{"organizationUserId": policy.GranteeID, "read": policy.Read, "write": policy.Write})
+	}
+	groups := []map[string]any{}
+	for _, policy := range s.groups[projectID] {
+		groups = append(groups, map[string]any{"groupId": policy.GranteeID, "read": policy.Read, "write": policy.Write})
+	}
+	return map[string]any{"userAccessPolicies": users, "groupAccessPolicies": groups}
+}
+
+func TestMachineAccountAccessLifecycle(t *testing.T) {
+	ctx := context.Background()
+	standIn := newAccessPolicyStandIn(t)
+	policies := standIn.client().ProjectAccessPolicies()
+
+	// An existing policy of another machine account must be kept.
+	standIn.machineAccounts[validProjectUUID] = []accessPolicyRequest{{GranteeID: "other", Read: true}}
+
+	policy, err := policies.SetMachineAccountAccess(ctx, validProjectUUID, accessPolicy{GranteeID: "machine-account", Read: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if *policy != (accessPolicy{GranteeID: "machine-account", Read: true}) {
+		t.Fatalf("unexpected access policy: %+v", policy)
+	}
+
+	policy, err = policies.SetMachineAccountAccess(ctx, validProjectUUID, accessPolicy{GranteeID: "machine-account", Read: true, Write: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !policy.Write {
+		t.Fatal("expected write access to be granted")
+	}
+
+	policy, err = policies.GetMachineAccountAccess(ctx, validProjectUUID, "machine-account")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if policy == nil || !policy.Read || !policy.Write {
+		t.Fatalf("unexpected access policy: %+v", policy)
+	}
+
+	if err := policies.DeleteMachineAccountAccess(ctx, validProjectUUID, "machine-account"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	policy, err = policies.GetMachineAccountAccess(ctx, validProjectUUID, "machine-account")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if policy != nil {
+		t.Fatalf("expected access to be revoked, got %+v", policy)
+	}
+
+	expected := []accessPolicyRequest{{GranteeID: "other", Read: true}}
+	if !reflect.DeepEqual(standIn.machineAccounts[validProjectUUID], expected) {
+		t.Fatalf("expected other access policies to be kept, got %+v", standIn.machineAccounts[validProjectUUID])
+	}
+
+	// Deleting a missing access policy is a no-op.
+	if err := policies.DeleteMachineAccountAccess(ctx, validProjectUUID, "machine-account"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+}
+
+func TestGroupAccessKeepsUserAccess(t *testing.T) {
+	ctx := context.Background()
+	standIn := newAccessPolicyStandIn(t)
+	policies := standIn.client().ProjectAccessPolicies()
+
+	standIn.users[validProjectUUID] = []accessPolicyRequest{{GranteeID: "user", Read: true, Write: true}}
+
+	if _, err := policies.SetGroupAccess(ctx, validProjectUUID, accessPolicy{GranteeID: "group", Read: true}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	policy, err := policies.GetGroupAccess(ctx, validProjectUUID, "group")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if policy == nil || !policy.Read || policy.Write {
+		t.Fatalf("unexpected access policy: %+v", policy)
+	}
+
+	if err := policies.DeleteGroupAccess(ctx, validProjectUUID, "group"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(standIn.groups[validProjectUUID]) != 0 {
+		t.Fatalf("expected group access to be revoked, got %+v", standIn.groups[validProjectUUID])
+	}
+	if len(standIn.users[validProjectUUID]) != 1 {
+		t.Fatalf("expected user access to be kept, got %+v", standIn.users[validProjectUUID])
+	}
+}
+
+func TestAccessPolicyAPIError(t *testing.T) {
+	standIn := newAccessPolicyStandIn(t)
+	policies := standIn.client().ProjectAccessPolicies()
+
+	_, err := policies.SetMachineAccountAccess(context.Background(), validProjectUUID, accessPolicy{GranteeID: "machine-account", Write: true})
+	if err == nil || err.Error() != "API error: 400 Invalid access policy." {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+// Terraform applies resources in parallel, concurrent changes of the same project must not overwrite each other.
+func TestConcurrentMachineAccountAccess(t *testing.T) {
+	ctx := context.Background()
+	standIn := newAccessPolicyStandIn(t)
+	policies := standIn.client().ProjectAccessPolicies()
+
+	grantees := []string{"a", "b", "c", "d", "e", "f", "g", "h"}
+	var wg sync.WaitGroup
+	for _, grantee := range grantees {
+		wg.Add(1)
+		go func(grantee string) {
+			defer wg.Done()
+			if _, err := policies.SetMachineAccountAccess(ctx, validProjectUUID, accessPolicy{GranteeID: grantee, Read: true}); err != nil {
+				t.Errorf("unexpected error: %s", err)
+			}
+		}(grantee)
+	}
+	wg.Wait()
+
+	if len(standIn.machineAccounts[validProjectUUID]) != len(grantees) {
+		t.Fatalf("expected %d access policies, got %+v", len(grantees), standIn.machineAccounts[validProjectUUID])
+	}
+}
+
+func TestReplaceAccessPolicy(t *testing.T) {
+	policies := []accessPolicy{{GranteeID: "a", Read: true}, {GranteeID: "b", Read: true}}
+
+	replaced := replaceAccessPolicy(policies, "b", &accessPolicy{GranteeID: "b", Read: true, Write: true})
+	if !reflect.DeepEqual(replaced, []accessPolicy{{GranteeID: "a", Read: true}, {GranteeID: "b", Read: true, Write: true}}) {
+		t.Fatalf("unexpected access policies: %+v", replaced)
+	}
+
+	added := replaceAccessPolicy(policies, "c", &accessPolicy{GranteeID: "c", Read: true})
+	if len(added) != 3 || added[2].GranteeID != "c" {
+		t.Fatalf("unexpected access policies: %+v", added)
+	}
+
+	removed := replaceAccessPolicy(policies, "a", nil)
+	if !reflect.DeepEqual(removed, []accessPolicy{{GranteeID: "b", Read: true}}) {
+		t.Fatalf("unexpected access policies: %+v", removed)
+	}
+}
+
+func TestParseProjectAccessID(t *testing.T) {
+	const machineAccountUUID = "5d5a8a06-2d3b-4f0e-9a39-b2a600f0c1f4"
+
+	projectID, granteeID, err := parseProjectAccessID(validProjectUUID + "/" + machineAccountUUID)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if projectID != validProjectUUID || granteeID != machineAccountUUID {
+		t.Fatalf("unexpected parts: %s %s", projectID, granteeID)
+	}
+
+	for _, invalid := range []string{"", validProjectUUID, validProjectUUID + "/", "/" + machineAccountUUID} {
+		if _, _, err := parseProjectAccessID(invalid); err == nil {
+			t.Fatalf("expected an error for %q", invalid)
+		}
+	}
+}
@@ -0,0 +1,326 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// bitwardenAPIClient provides the parts of the Bitwarden Secrets Manager API which are not exposed by the SDK.
+// It authenticates with the access token of the provider configuration, just like the SDK does.
+type bitwardenAPIClient interface {
+	ProjectAccessPolicies() projectAccessPoliciesInterface
+	MachineAccounts() machineAccountsInterface
+	Identity(ctx context.Context) (*apiIdentity, error)
+	ProjectPermission(ctx context.Context, projectID string) (*projectPermission, error)
+}
+
+// apiError is returned for all unsuccessful responses of the Bitwarden Secrets Manager API.
+type apiError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("API error: %d %s", e.StatusCode, e.Message)
+}
+
+// accessTokenCredentials are the parts of a machine account access token. The format of an access token is
+// "0.<access token id>.<client secret>:<encryption key>".
+type accessTokenCredentials struct {
+	AccessTokenID string
+	ClientSecret  string
+	EncryptionKey string
+}
+
+func parseAccessToken(accessToken string) (accessTokenCredentials, error) {
+	credentials, encryptionKey, found := strings.Cut(accessToken, ":")
+	if !found || encryptionKey == "" {
+		return accessTokenCredentials{}, errors.New("access token is missing the encryption key")
+	}
+
+	parts := strings.Split(credentials, ".")
+	if len(parts) != 3 || parts[0] != "0" || parts[1] == "" || parts[2] == "" {
+		return accessTokenCredentials{}, errors.New("access token has an unsupported format")
+	}
+
+	return accessTokenCredentials{
+		AccessTokenID: parts[1],
+		ClientSecret:  parts[2],
+		EncryptionKey: encryptionKey,
+	}, nil
+}
+
+// identityTokenResponse is the response of the identity endpoint for the client credentials grant.
+type identityTokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	ExpiresIn        int64  `json:"expires_in"`
+	TokenType        string `json:"token_type"`
+	EncryptedPayload string `json:"encrypted_payload"`
+}
+
+// httpAPIClient implements bitwardenAPIClient using the REST API of Bitwarden Secrets Manager.
+type httpAPIClient struct {
+	apiUrl      string
+	identityUrl string
+	credentials accessTokenCredentials
+	httpClient  *http.Client
+
+	mu       sync.Mutex
+	token    *identityTokenResponse
+	tokenExp time.Time
+	orgKey   *symmetricKey
+
+	// projectLocks serializes read-modify-write cycles of the access policies of a project, since terraform
+	// applies resources in parallel and the API replaces all access policies of a project at once.
+	projectLocks sync.Map
+}
+
+// newHTTPAPIClient creates the API client. The access token is only parsed here, the client logs in on its first
+// request.
+func newHTTPAPIClient(apiUrl string, identityUrl string, accessToken string) (*httpAPIClient, error) {
+	credentials, err := parseAccessToken(accessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	return &httpAPIClient{
+		apiUrl:      strings.TrimRight(apiUrl, "/"),
+		identityUrl: strings.TrimRight(identityUrl, "/"),
+		credentials: credentials,
+		httpClient:  &http.Client{Timeout: 60 * time.Second},
+	}, nil
+}
+
+// newBitwardenAPIClient is the default of BitwardenSecretsManagerProvider.newAPIClient.
+func newBitwardenAPIClient(apiUrl string, identityUrl string, accessToken string) (bitwardenAPIClient, error) {
+	client, err := newHTTPAPIClient(apiUrl, identityUrl, accessToken)
+	if err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+// lazyAPIClient returns a function which creates the API client on its first call and returns the same client or
+// error on all later calls. Most configurations only use resources backed by the SDK and never create it.
+func lazyAPIClient(newAPIClient func(apiUrl string, identityUrl string, accessToken string) (bitwardenAPIClient, error), apiUrl string, identityUrl string, accessToken string) func() (bitwardenAPIClient, error) {
+	return sync.OnceValues(func() (bitwardenAPIClient, error) {
+		return newAPIClient(apiUrl, identityUrl, accessToken)
+	})
+}
+
+// configuredAPIClient returns the API client of a resource or data source, see lazyAPIClient. Errors are added to
+// diags and nil is returned.
+func configuredAPIClient(apiClient func() (bitwardenAPIClient, error), diags *diag.Diagnostics) bitwardenAPIClient {
+	if apiClient == nil {
+		diags.AddError(
+			"Client Not Initialized",
+			"The Bitwarden client was not properly initialized.",
+		)
+		return nil
+	}
+
+	client, err := apiClient()
+	if err != nil {
+		diags.AddError(
+			"Unable to Create Bitwarden Secrets Manager API Client",
+			"The client of the Bitwarden Secrets Manager API could not be created from the configured access token: "+err.Error(),
+		)
+		return nil
+	}
+	return client
+}
+
+func (c *httpAPIClient) ProjectAccessPolicies() projectAccessPoliciesInterface {
+	return &projectAccessPolicies{client: c}
+}
+
+func (c *httpAPIClient) MachineAccounts() machineAccountsInterface {
+	return &machineAccounts{client: c}
+}
+
+// login returns a valid bearer token, requesting a new one from the identity endpoint if necessary.
+func (c *httpAPIClient) login(ctx context.Context) (*identityTokenResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Tokens are renewed one minute before they expire.
+	if c.token != nil && time.Now().Add(time.Minute).Before(c.tokenExp) {
+		return c.token, nil
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "client_credentials")
+	form.Set("scope", "api.secrets")
+	form.Set("client_id", c.credentials.AccessTokenID)
+	form.Set("client_secret", c.credentials.ClientSecret)
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, c.identityUrl+"/connect/token", strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Accept", "application/json")
+
+	var token identityTokenResponse
+	if err := c.send(request, &token); err != nil {
+		return nil, err
+	}
+	if token.AccessToken == "" {
+		return nil, errors.New("identity response does not contain an access token")
+	}
+
+	c.token = &token
+	c.tokenExp = time.Now().Add(time.Duration(token.ExpiresIn) * time.Second)
+	return c.token, nil
+}
+
+// organizationKey returns the symmetric key of the organization, which is required to encrypt and decrypt names
+// of machine accounts and access tokens. The identity endpoint returns it encrypted with a key derived from the
+// encryption key of the access token.
+func (c *httpAPIClient) organizationKey(ctx context.Context) (symmetricKey, error) {
+	token, err := c.login(ctx)
+	if err != nil {
+		return symmetricKey{}, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.orgKey != nil {
+		return *c.orgKey, nil
+	}
+
+	secret, err := base64.StdEncoding.DecodeString(c.credentials.EncryptionKey)
+	if err != nil || len(secret) != 16 {
+		return symmetricKey{}, errors.New("access token has an invalid encryption key")
+	}
+	if token.EncryptedPayload == "" {
+		return symmetricKey{}, errors.New("identity response does not contain the encrypted organization key")
+	}
+
+	payload, err := decryptString(deriveShareableKey(secret, "accesstoken", "sm-access-token"), token.EncryptedPayload)
+	if err != nil {
+		return symmetricKey{}, fmt.Errorf("unable to decrypt the organization key: %w", err)
+	}
+	var accessTokenPayload struct {
+		EncryptionKey string `json:"encryptionKey"`
+	}
+	if err := json.Unmarshal(payload, &accessTokenPayload); err != nil {
+		return symmetricKey{}, fmt.Errorf("unable to decode the organization key: %w", err)
+	}
+	keyBytes, err := base64.StdEncoding.DecodeString(accessTokenPayload.EncryptionKey)
+	if err != nil {
+		return symmetricKey{}, fmt.Errorf("unable to decode the organization key: %w", err)
+	}
+	key, err := newSymmetricKey(keyBytes)
+	if err != nil {
+		return symmetricKey{}, fmt.Errorf("invalid organization key: %w", err)
+	}
+
+	c.orgKey = &key
+	return key, nil
+}
+
+// do sends an authenticated JSON request to the API and decodes the response into out, if out is not nil.
+func (c *httpAPIClient) do(ctx context.Context, method string, path string, body any, out any) error {
+	token, err := c.login(ctx)
+	if err != nil {
+		return err
+	}
+
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, method, c.apiUrl+path, reader)
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Authorization", "Bearer "+token.AccessToken)
+	request.Header.Set("Accept", "application/json")
+	if body != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+
+	return c.send(request, out)
+}
+
+// send sends a request and decodes the response into out, if out is not nil. Requests are logged without their
+// bodies, see logRequest.
+func (c *httpAPIClient) send(request *http.Request, out any) error {
+	ctx := withRequestLogging(request.Context())
+	_, err := logRequest(ctx, request.Method+" "+request.URL.Path, nil, func() (httpStatus, error) {
+		return c.exchange(request, out)
+	})
+	return err
+}
+
+func (c *httpAPIClient) exchange(request *http.Request, out any) (httpStatus, error) {
+	response, err := c.httpClient.Do(request)
+	if err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+
+	status := httpStatus(response.StatusCode)
+	data, err := io.ReadAll(response.Body)
+	if err != nil {
+		return status, err
+	}
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return status, &apiError{StatusCode: response.StatusCode, Message: errorMessage(data, response.Status)}
+	}
+
+	if out == nil || len(data) == 0 {
+		return status, nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return status, fmt.Errorf("unable to decode response of %s %s: %w", request.Method, request.URL.Path, err)
+	}
+	return status, nil
+}
+
+// errorMessage extracts the message of an API error response and falls back to the HTTP status.
+func errorMessage(data []byte, status string) string {
+	var response struct {
+		Message          string `json:"message"`
+		ErrorDescription string `json:"error_description"`
+		Error            string `json:"error"`
+	}
+	if err := json.Unmarshal(data, &response); err == nil {
+		switch {
+		case response.Message != "":
+			return response.Message
+		case response.ErrorDescription != "":
+			return response.ErrorDescription
+		case response.Error != "":
+			return response.Error
+		}
+	}
+	return status
+}
+
+// lockProject serializes changes of the access policies of a project and returns the unlock function.
+func (c *httpAPIClient) lockProject(projectID string) func() {
+	lock, _ := c.projectLocks.LoadOrStore(projectID, &sync.Mutex{})
+	mutex := lock.(*sync.Mutex)
+	mutex.Lock()
+	return mutex.Unlock
+}
@@ -0,0 +1,342 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testBearerToken is the JWT issued by the identity endpoint of apiStandIn, the signature is not verified. The SDK
+// requires the scope claim.
+var testBearerToken = "eyJhbGciOiJSUzI1NiIsInR5cCI6ImF0K2p3dCJ9." + base64.RawURLEncoding.EncodeToString([]byte(
+	`{"sub":"5d5a8a06-2d3b-4f0e-9a39-b2a600f0c1f4","organization":"3b2c3d6e-9b3f-4a7e-8f3a-b2a600f0c1f5","exp":1893456000,"scope":["api.secrets"]}`,
+)) + ".c2lnbmF0dXJl"
+
+const testAPIAccessToken = "0.ec2c1d46-6a4b-4751-a310-af9601317f2d.C2IgxjjLF7qSshsbwe8JGcbM075YXw:X8vbvA0bduihIDe/qrzIQQ=="
+
+// apiStandIn is a local stand-in of the Bitwarden identity and API endpoints used by httpAPIClient. Handlers of
+// API endpoints are registered per test, all of them require the bearer token issued by the identity endpoint.
+type apiStandIn struct {
+	t      *testing.T
+	server *httptest.Server
+	mux    *http.ServeMux
+
+	// orgKey is the organization key shared with the access token in the encrypted payload of the identity response.
+	orgKey symmetricKey
+
+	mu     sync.Mutex
+	logins int
+	// faults holds the status codes to respond with instead of serving the next requests, see failNext.
+	faults map[string][]int
+	// latencies holds the delays of requests before they are served, see delay.
+	latencies map[string]time.Duration
+}
+
+func newAPIStandIn(t *testing.T) *apiStandIn {
+	t.Helper()
+
+	standIn := &apiStandIn{t: t, mux: http.NewServeMux(), orgKey: newTestSymmetricKey(t), faults: map[string][]int{}, latencies: map[string]time.Duration{}}
+	encryptedPayload := encryptedAccessTokenPayload(t, standIn.orgKey, testAPIAccessToken)
+
+	standIn.mux.HandleFunc("POST /identity/connect/token", func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if r.PostForm.Get("grant_type") != "client_credentials" ||
+			r.PostForm.Get("client_id") != "ec2c1d46-6a4b-4751-a310-af9601317f2d" ||
+			r.PostForm.Get("client_secret") != "C2IgxjjLF7qSshsbwe8JGcbM075YXw" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":"invalid_client"}`))
+			return
+		}
+
+		standIn.mu.Lock()
+		standIn.logins++
+		standIn.mu.Unlock()
+
+		writeJSON(w, http.StatusOK, map[string]any{
+			"access_token":      testBearerToken,
+			"expires_in":        3600,
+			"token_type":        "Bearer",
+			"scope":             "api.secrets",
+			"encrypted_payload": encryptedPayload,
+		})
+	})
+
+	standIn.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if latency := standIn.latency(r.Method + " " + r.URL.Path); latency > 0 {
+			select {
+			case <-time.After(latency):
+			case <-r.Context().Done():
+				return
+			}
+		}
+		if status, ok := standIn.nextFault(r.Method + " " + r.URL.Path); ok {
+			writeStandInError(w, status)
+			return
+		}
+		if !strings.HasPrefix(r.URL.Path, "/identity/") && r.Header.Get("Authorization") != "Bearer "+testBearerToken {
+			writeStandInError(w, http.StatusUnauthorized)
+			return
+		}
+		standIn.mux.ServeHTTP(w, r)
+	}))
+	t.Cleanup(standIn.server.Close)
+
+	return standIn
+}
+
+func (s *apiStandIn) client() *httpAPIClient {
+	return s.clientWithAccessToken(testAPIAccessToken)
+}
+
+func (s *apiStandIn) clientWithAccessToken(accessToken string) *httpAPIClient {
+	s.t.Helper()
+
+	client, err := newHTTPAPIClient(s.server.URL+"/api", s.server.URL+"/identity", accessToken)
+	if err != nil {
+		s.t.Fatalf("unexpected error: %s", err)
+	}
+	return client
+}
+
+func (s *apiStandIn) handle(pattern string, handler http.HandlerFunc) {
+	s.mux.HandleFunc(strings.Replace(pattern, " ", " /api", 1), handler)
+}
+
+// failNext makes the stand-in respond to the next requests of the given method and path with the given status codes
+// instead of serving them, one status code per request. Paths of API endpoints are relative to the API URL, like in
+// handle, e.g. "GET /secrets/<id>".
+func (s *apiStandIn) failNext(request string, statusCodes ...int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !strings.Contains(request, " /identity/") {
+		request = strings.Replace(request, " ", " /api", 1)
+	}
+	s.faults[request] = append(s.faults[request], statusCodes...)
+}
+
+// delay makes the stand-in wait for the given latency before it serves requests of the given method and path, until
+// the latency is set to zero. Paths are relative to the API URL, like in failNext.
+func (s *apiStandIn) delay(request string, latency time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.latencies[strings.Replace(request, " ", " /api", 1)] = latency
+}
+
+func (s *apiStandIn) latency(request string) time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.latencies[request]
+}
+
+func (s *apiStandIn) nextFault(request string) (int, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	statusCodes := s.faults[request]
+	if len(statusCodes) == 0 {
+		return 0, false
+	}
+	s.faults[request] = statusCodes[1:]
+	return statusCodes[0], true
+}
+
+// writeStandInError writes an error response in the format of the Bitwarden server for the given status code.
+func writeStandInError(w http.ResponseWriter, status int) {
+	switch status {
+	case http.StatusUnauthorized:
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		w.WriteHeader(status)
+	case http.StatusTooManyRequests:
+		w.Header().Set("Retry-After", "60")
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte("Slow down! Too many requests. Try again in 1m."))
+	case http.StatusNotFound:
+		writeJSON(w, status, map[string]any{"message": "Resource not found.", "validationErrors": nil, "object": "error"})
+	default:
+		writeJSON(w, status, map[string]any{"message": "An error has occurred.", "validationErrors": nil, "object": "error"})
+	}
+}
+
+func newTestSymmetricKey(t *testing.T) symmetricKey {
+	t.Helper()
+
+	keyBytes := make([]byte, 64)
+	if _, err := rand.Read(keyBytes); err != nil {
+		t.Fatalf("unable to generate key: %s", err)
+	}
+	key, _ := newSymmetricKey(keyBytes)
+	return key
+}
+
+// encryptedAccessTokenPayload encrypts the organization key for an access token, like the API does on login.
+func encryptedAccessTokenPayload(t *testing.T, orgKey symmetricKey, accessToken string) string {
+	t.Helper()
+
+	credentials, err := parseAccessToken(accessToken)
+	if err != nil {
+		t.Fatalf("invalid access token: %s", err)
+	}
+	secret, err := base64.StdEncoding.DecodeString(credentials.EncryptionKey)
+	if err != nil {
+		t.Fatalf("invalid access token encryption key: %s", err)
+	}
+
+	payload, _ := json.Marshal(map[string]string{"encryptionKey": base64.StdEncoding.EncodeToString(orgKey.bytes())})
+	encryptedPayload, err := encryptString(deriveShareableKey(secret, "accesstoken", "sm-access-token"), payload)
+	if err != nil {
+		t.Fatalf("unable to encrypt payload: %s", err)
+	}
+	return encryptedPayload
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func TestParseAccessToken(t *testing.T) {
+	credentials, err := parseAccessToken(testAPIAccessToken)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if credentials.AccessTokenID != "ec2c1d46-6a4b-4751-a310-af9601317f2d" ||
+		credentials.ClientSecret != "C2IgxjjLF7qSshsbwe8JGcbM075YXw" ||
+		credentials.EncryptionKey != "X8vbvA0bduihIDe/qrzIQQ==" {
+		t.Fatalf("unexpected credentials: %+v", credentials)
+	}
+
+	for _, invalid := range []string{"", "token", "0.id.secret", "1.id.secret:key", "0..secret:key", "0.id:key"} {
+		if _, err := parseAccessToken(invalid); err == nil {
+			t.Fatalf("expected an error for access token %q", invalid)
+		}
+	}
+}
+
+func TestHTTPAPIClientReusesToken(t *testing.T) {
+	standIn := newAPIStandIn(t)
+	standIn.handle("GET /ping", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	})
+	client := standIn.client()
+
+	for i := 0; i < 3; i++ {
+		var response map[string]string
+		if err := client.do(context.Background(), http.MethodGet, "/ping", nil, &response); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if response["status"] != "ok" {
+			t.Fatalf("unexpected response: %v", response)
+		}
+	}
+
+	if standIn.logins != 1 {
+		t.Fatalf("expected a single login, got %d", standIn.logins)
+	}
+}
+
+func TestHTTPAPIClientErrors(t *testing.T) {
+	standIn := newAPIStandIn(t)
+	standIn.handle("GET /forbidden", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusForbidden, map[string]string{"message": "You do not have permission."})
+	})
+	standIn.handle("GET /missing", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	client := standIn.client()
+
+	err := client.do(context.Background(), http.MethodGet, "/forbidden", nil, nil)
+	if err == nil || err.Error() != "API error: 403 You do not have permission." {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if isNotFound(err) {
+		t.Fatal("expected a forbidden error not to be reported as not found")
+	}
+
+	err = client.do(context.Background(), http.MethodGet, "/missing", nil, nil)
+	if !isNotFound(err) {
+		t.Fatalf("expected a not found error, got: %v", err)
+	}
+
+	invalidClient := standIn.clientWithAccessToken("0.id.secret:key")
+	err = invalidClient.do(context.Background(), http.MethodGet, "/forbidden", nil, nil)
+	if err == nil || err.Error() != "API error: 400 invalid_client" {
+		t.Fatalf("unexpected login error: %v", err)
+	}
+}
+
+func TestHTTPAPIClientOrganizationKey(t *testing.T) {
+	standIn := newAPIStandIn(t)
+
+	key, err := standIn.client().organizationKey(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !bytes.Equal(key.bytes(), standIn.orgKey.bytes()) {
+		t.Fatal("expected the organization key of the identity response")
+	}
+
+	// The payload must be decrypted with the encryption key of the access token which was used to log in.
+	client := standIn.clientWithAccessToken("0.ec2c1d46-6a4b-4751-a310-af9601317f2d.C2IgxjjLF7qSshsbwe8JGcbM075YXw:AAAAAAAAAAAAAAAAAAAAAA==")
+	if _, err := client.organizationKey(context.Background()); err == nil || !strings.Contains(err.Error(), "MAC verification failed") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestLazyAPIClient(t *testing.T) {
+	created := 0
+	apiClient := lazyAPIClient(func(apiUrl string, identityUrl string, accessToken string) (bitwardenAPIClient, error) {
+		created++
+		return newBitwardenAPIClient(apiUrl, identityUrl, accessToken)
+	}, "https://api.bitwarden.fake", "https://identity.bitwarden.fake", testAPIAccessToken)
+	if created != 0 {
+		t.Fatal("expected the API client not to be created before its first use")
+	}
+
+	first, err := apiClient()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	second, err := apiClient()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if created != 1 || first != second {
+		t.Fatalf("expected a single API client, created %d", created)
+	}
+
+	var diags diag.Diagnostics
+	invalid := lazyAPIClient(newBitwardenAPIClient, "https://api.bitwarden.fake", "https://identity.bitwarden.fake", "invalid")
+	if client := configuredAPIClient(invalid, &diags); client != nil || !diags.HasError() {
+		t.Fatalf("expected an error for an invalid access token, got %v", diags)
+	}
+}
+
+func TestSecretResourceDoesNotCreateAPIClient(t *testing.T) {
+	harness := newResourceHarness(t, "bitwarden-sm_secret", newFakeBitwardenStore(fakeOrganizationID).newClient())
+
+	state, diagnostics := harness.apply(secretConfig("value"), harness.nullState())
+	expectDiagnosticError(t, diagnostics, "")
+	_, diagnostics = harness.destroy(state)
+	expectDiagnosticError(t, diagnostics, "")
+
+	if *harness.apiClientsCreated != 0 {
+		t.Fatalf("expected the secret resource not to create the API client, created %d", *harness.apiClientsCreated)
+	}
+}
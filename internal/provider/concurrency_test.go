@@ -37,7 +37,7 @@ func TestAccConcurrency(t *testing.T) {
 		t.Fatal("Error creating test secret for provider validation.")
 	}
 
-	resource.Test(t, resource.TestCase{
+	runProviderTest(t, resource.TestCase{
 		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
@@ -0,0 +1,197 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/bitwarden/sdk-go"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"strings"
+	"time"
+)
+
+// contextBitwardenClient wraps a Bitwarden client, so its calls return as soon as the context is done. The calls of
+// the SDK do not accept a context, a call which does not return in time keeps running in the background and its
+// result is discarded.
+type contextBitwardenClient struct {
+	sdk.BitwardenClientInterface
+	ctx context.Context
+}
+
+// clientWithContext returns a Bitwarden client whose calls are bound to ctx and logged, see logRequest.
+func clientWithContext(ctx context.Context, bitwardenClient sdk.BitwardenClientInterface) sdk.BitwardenClientInterface {
+	return &contextBitwardenClient{BitwardenClientInterface: bitwardenClient, ctx: withRequestLogging(ctx)}
+}
+
+func (c *contextBitwardenClient) AccessTokenLogin(accessToken string, stateFile *string) error {
+	_, err := sendWithContext(c.ctx, "AccessTokenLogin", map[string]any{"access_token": accessToken}, func() (struct{}, error) {
+		return struct{}{}, c.BitwardenClientInterface.AccessTokenLogin(accessToken, stateFile)
+	})
+	return err
+}
+
+func (c *contextBitwardenClient) Projects() sdk.ProjectsInterface {
+	return &contextProjects{ctx: c.ctx, projects: c.BitwardenClientInterface.Projects()}
+}
+
+func (c *contextBitwardenClient) Secrets() sdk.SecretsInterface {
+	return &contextSecrets{ctx: c.ctx, secrets: c.BitwardenClientInterface.Secrets()}
+}
+
+func (c *contextBitwardenClient) Generators() sdk.GeneratorsInterface {
+	return &contextGenerators{ctx: c.ctx, generators: c.BitwardenClientInterface.Generators()}
+}
+
+// callWithContext runs call and returns its result, or the error of ctx if ctx is done before call returns.
+func callWithContext[T any](ctx context.Context, call func() (T, error)) (T, error) {
+	var zero T
+	if err := ctx.Err(); err != nil {
+		return zero, err
+	}
+
+	type result struct {
+		value T
+		err   error
+	}
+	done := make(chan result, 1)
+	go func() {
+		value, err := call()
+		done <- result{value: value, err: err}
+	}()
+
+	select {
+	case r := <-done:
+		return r.value, r.err
+	case <-ctx.Done():
+		return zero, ctx.Err()
+	}
+}
+
+// sendWithContext is callWithContext for a logged request of operation.
+func sendWithContext[T any](ctx context.Context, operation string, fields map[string]any, call func() (T, error)) (T, error) {
+	return logRequest(ctx, operation, fields, func() (T, error) {
+		return callWithContext(ctx, call)
+	})
+}
+
+// isContextError reports whether err was caused by a cancelled context or an exceeded deadline rather than by
+// Bitwarden Secrets Manager.
+func isContextError(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// addCancellationDiagnostic replaces the errors of an operation which was cancelled, e.g. because Terraform was
+// interrupted, with a single diagnostic naming the operation. It is meant to be deferred, see withOperationTimeout.
+// Operations which succeeded despite the cancellation are not changed.
+func addCancellationDiagnostic(ctx context.Context, diags *diag.Diagnostics, operation string) {
+	if !errors.Is(ctx.Err(), context.Canceled) || !diags.HasError() {
+		return
+	}
+
+	kept := diag.Diagnostics{}
+	for _, diagnostic := range *diags {
+		if diagnostic.Severity() == diag.SeverityError && strings.Contains(diagnostic.Detail(), context.Canceled.Error()) {
+			continue
+		}
+		kept = append(kept, diagnostic)
+	}
+	kept.AddError(
+		"Operation Cancelled",
+		fmt.Sprintf("The %s was cancelled because Terraform was interrupted. "+
+			"Bitwarden Secrets Manager may still complete the request, refresh the state before retrying.", operation),
+	)
+	*diags = kept
+}
+
+type contextProjects struct {
+	ctx      context.Context
+	projects sdk.ProjectsInterface
+}
+
+func (p *contextProjects) Create(organizationID string, name string) (*sdk.ProjectResponse, error) {
+	return sendWithContext(p.ctx, "Projects.Create", nil, func() (*sdk.ProjectResponse, error) {
+		return p.projects.Create(organizationID, name)
+	})
+}
+
+func (p *contextProjects) List(organizationID string) (*sdk.ProjectsResponse, error) {
+	return sendWithContext(p.ctx, "Projects.List", nil, func() (*sdk.ProjectsResponse, error) {
+		return p.projects.List(organizationID)
+	})
+}
+
+func (p *contextProjects) Get(projectID string) (*sdk.ProjectResponse, error) {
+	return sendWithContext(p.ctx, "Projects.Get", map[string]any{"project_id": projectID}, func() (*sdk.ProjectResponse, error) {
+		return p.projects.Get(projectID)
+	})
+}
+
+func (p *contextProjects) Update(projectID string, organizationID string, name string) (*sdk.ProjectResponse, error) {
+	return sendWithContext(p.ctx, "Projects.Update", map[string]any{"project_id": projectID}, func() (*sdk.ProjectResponse, error) {
+		return p.projects.Update(projectID, organizationID, name)
+	})
+}
+
+func (p *contextProjects) Delete(projectIDs []string) (*sdk.ProjectsDeleteResponse, error) {
+	return sendWithContext(p.ctx, "Projects.Delete", map[string]any{"project_ids": projectIDs}, func() (*sdk.ProjectsDeleteResponse, error) {
+		return p.projects.Delete(projectIDs)
+	})
+}
+
+type contextSecrets struct {
+	ctx     context.Context
+	secrets sdk.SecretsInterface
+}
+
+func (s *contextSecrets) Create(key string, value string, note string, organizationID string, projectIDs []string) (*sdk.SecretResponse, error) {
+	return sendWithContext(s.ctx, "Secrets.Create", map[string]any{"key": key, "value": value, "note": note, "project_ids": projectIDs}, func() (*sdk.SecretResponse, error) {
+		return s.secrets.Create(key, value, note, organizationID, projectIDs)
+	})
+}
+
+func (s *contextSecrets) List(organizationID string) (*sdk.SecretIdentifiersResponse, error) {
+	return sendWithContext(s.ctx, "Secrets.List", nil, func() (*sdk.SecretIdentifiersResponse, error) {
+		return s.secrets.List(organizationID)
+	})
+}
+
+func (s *contextSecrets) Get(secretID string) (*sdk.SecretResponse, error) {
+	return sendWithContext(s.ctx, "Secrets.Get", map[string]any{"secret_id": secretID}, func() (*sdk.SecretResponse, error) {
+		return s.secrets.Get(secretID)
+	})
+}
+
+func (s *contextSecrets) GetByIDS(secretIDs []string) (*sdk.SecretsResponse, error) {
+	return sendWithContext(s.ctx, "Secrets.GetByIDS", map[string]any{"secret_ids": secretIDs}, func() (*sdk.SecretsResponse, error) {
+		return s.secrets.GetByIDS(secretIDs)
+	})
+}
+
+func (s *contextSecrets) Update(secretID string, key string, value string, note string, organizationID string, projectIDs []string) (*sdk.SecretResponse, error) {
+	return sendWithContext(s.ctx, "Secrets.Update", map[string]any{"secret_id": secretID, "key": key, "value": value, "note": note, "project_ids": projectIDs}, func() (*sdk.SecretResponse, error) {
+		return s.secrets.Update(secretID, key, value, note, organizationID, projectIDs)
+	})
+}
+
+func (s *contextSecrets) Delete(secretIDs []string) (*sdk.SecretsDeleteResponse, error) {
+	return sendWithContext(s.ctx, "Secrets.Delete", map[string]any{"secret_ids": secretIDs}, func() (*sdk.SecretsDeleteResponse, error) {
+		return s.secrets.Delete(secretIDs)
+	})
+}
+
+func (s *contextSecrets) Sync(organizationID string, lastSyncedDate *time.Time) (*sdk.SecretsSyncResponse, error) {
+	return sendWithContext(s.ctx, "Secrets.Sync", nil, func() (*sdk.SecretsSyncResponse, error) {
+		return s.secrets.Sync(organizationID, lastSyncedDate)
+	})
+}
+
+type contextGenerators struct {
+	ctx        context.Context
+	generators sdk.GeneratorsInterface
+}
+
+func (g *contextGenerators) GeneratePassword(request sdk.PasswordGeneratorRequest) (*string, error) {
+	return sendWithContext(g.ctx, "Generators.GeneratePassword", nil, func() (*string, error) {
+		return g.generators.GeneratePassword(request)
+	})
+}
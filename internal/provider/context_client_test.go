@@ -0,0 +1,285 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCallWithContext(t *testing.T) {
+	value, err := callWithContext(context.Background(), func() (string, error) {
+		return "value", nil
+	})
+	if err != nil || value != "value" {
+		t.Fatalf("expected the result of the call, got %q, %v", value, err)
+	}
+
+	callErr := errors.New("call failed")
+	if _, err := callWithContext(context.Background(), func() (string, error) {
+		return "", callErr
+	}); !errors.Is(err, callErr) {
+		t.Fatalf("expected the error of the call, got %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	release := make(chan struct{})
+	defer close(release)
+	if _, err := callWithContext(ctx, func() (string, error) {
+		<-release
+		return "value", nil
+	}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the deadline to be exceeded, got %v", err)
+	}
+
+	called := false
+	if _, err := callWithContext(ctx, func() (string, error) {
+		called = true
+		return "value", nil
+	}); !errors.Is(err, context.DeadlineExceeded) || called {
+		t.Fatalf("expected no call after the deadline, got %v", err)
+	}
+}
+
+func TestClientWithContext(t *testing.T) {
+	store := newFakeBitwardenStore(fakeOrganizationID)
+	client := newFaultInjectingClient(store.newClient())
+	if err := clientWithContext(context.Background(), client).AccessTokenLogin(fakeAccessToken, nil); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	release := make(chan struct{})
+	defer close(release)
+	client.inject("Secrets.Get", fault{block: release})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+	if _, err := clientWithContext(ctx, client).Secrets().Get("secret"); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected the blocked call to be abandoned, got %v", err)
+	}
+
+	// Calls of a cancelled context are not sent at all.
+	if _, err := clientWithContext(ctx, client).Secrets().List(fakeOrganizationID); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected the call to be cancelled, got %v", err)
+	}
+	if err := clientWithContext(ctx, client).AccessTokenLogin(fakeAccessToken, nil); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected the login to be cancelled, got %v", err)
+	}
+	if count := client.callCount("Secrets.List"); count != 0 {
+		t.Fatalf("expected no list call, got %d", count)
+	}
+}
+
+func TestAddCancellationDiagnostic(t *testing.T) {
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var diags diag.Diagnostics
+	diags.AddWarning("Warning", "kept")
+	diags.AddError("Unable to Read Secret", "context canceled")
+	diags.AddError("Other Error", "kept")
+	addCancellationDiagnostic(cancelled, &diags, "read of the secret")
+
+	if len(diags) != 3 || diags.ErrorsCount() != 2 {
+		t.Fatalf("expected the cancellation error to be replaced, got %v", diags)
+	}
+	last := diags[len(diags)-1]
+	if last.Summary() != "Operation Cancelled" || !strings.Contains(last.Detail(), "read of the secret was cancelled") {
+		t.Fatalf("unexpected cancellation diagnostic: %s: %s", last.Summary(), last.Detail())
+	}
+
+	// Successful operations, operations which were not cancelled and timeouts are not changed.
+	var succeeded diag.Diagnostics
+	addCancellationDiagnostic(cancelled, &succeeded, "read of the secret")
+	if len(succeeded) != 0 {
+		t.Fatalf("expected no diagnostics, got %v", succeeded)
+	}
+	expired, cancelExpired := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancelExpired()
+	for _, ctx := range []context.Context{context.Background(), expired} {
+		var failed diag.Diagnostics
+		failed.AddError("Unable to Read Secret", "not found")
+		addCancellationDiagnostic(ctx, &failed, "read of the secret")
+		if len(failed) != 1 || failed[0].Summary() != "Unable to Read Secret" {
+			t.Fatalf("expected the error to be kept, got %v", failed)
+		}
+	}
+}
+
+// interrupted runs fn with the RPCs of the harness being cancelled after delay, like terraform does when it is
+// interrupted.
+func (h *resourceHarness) interrupted(delay time.Duration, fn func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	time.AfterFunc(delay, cancel)
+
+	h.ctx = ctx
+	defer func() {
+		h.ctx = context.Background()
+	}()
+	fn()
+}
+
+// expectCancelled fails the test if the diagnostics are not the cancellation diagnostic of operation.
+func expectCancelled(t *testing.T, diagnostics []*tfprotov6.Diagnostic, operation string) {
+	t.Helper()
+
+	expectDiagnosticError(t, diagnostics, "The "+operation+" was cancelled because Terraform was interrupted")
+	if strings.Contains(diagnosticsString(diagnostics), context.Canceled.Error()) {
+		t.Fatalf("expected no opaque cancellation error, got: %s", diagnosticsString(diagnostics))
+	}
+}
+
+func TestSecretResourceCancellation(t *testing.T) {
+	harness, client, store := newFaultInjectionHarness(t)
+	release := make(chan struct{})
+	defer close(release)
+	blocked := fault{block: release}
+
+	state, diagnostics := harness.apply(secretConfig("value"), harness.nullState())
+	expectDiagnosticError(t, diagnostics, "")
+
+	client.inject("Secrets.Get", blocked)
+	harness.interrupted(20*time.Millisecond, func() {
+		_, diagnostics = harness.read(state)
+	})
+	expectCancelled(t, diagnostics, "read of the secret")
+
+	client.inject("Secrets.Update", blocked)
+	harness.interrupted(20*time.Millisecond, func() {
+		_, diagnostics = harness.apply(secretConfig("changed"), state)
+	})
+	expectCancelled(t, diagnostics, "update of the secret")
+
+	client.inject("Secrets.Delete", blocked)
+	harness.interrupted(20*time.Millisecond, func() {
+		_, diagnostics = harness.destroy(state)
+	})
+	expectCancelled(t, diagnostics, "delete of the secret")
+	if len(storedValues(store)) != 1 {
+		t.Fatal("expected the secret to be kept")
+	}
+}
+
+func TestSecretResourceCancellationAbortsCreateRetry(t *testing.T) {
+	harness, client, store := newFaultInjectionHarness(t)
+	delay := createRetryDelay
+	createRetryDelay = time.Minute
+	t.Cleanup(func() {
+		createRetryDelay = delay
+	})
+
+	// The response of the create request is lost, the retry waits far longer than the interruption takes.
+	client.inject("Secrets.Create", fault{err: faultStatus(http.StatusBadGateway)})
+	var diagnostics []*tfprotov6.Diagnostic
+	start := time.Now()
+	harness.interrupted(20*time.Millisecond, func() {
+		_, diagnostics = harness.apply(secretConfig("value"), harness.nullState())
+	})
+	expectCancelled(t, diagnostics, "create of the secret")
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Fatalf("expected the retry to be aborted, took %s", elapsed)
+	}
+	if count := client.callCount("Secrets.Create"); count != 1 {
+		t.Fatalf("expected no retry, got %d create calls", count)
+	}
+	if len(storedValues(store)) != 0 {
+		t.Fatal("expected no secret to be created")
+	}
+}
+
+func TestSecretResourceCancellationRecoversCreatedSecret(t *testing.T) {
+	harness, client, store := newFaultInjectionHarness(t)
+
+	// The secret is created, but the response arrives after the interruption.
+	client.inject("Secrets.Create", fault{hang: 200 * time.Millisecond})
+	var state *resourceState
+	var diagnostics []*tfprotov6.Diagnostic
+	harness.interrupted(20*time.Millisecond, func() {
+		state, diagnostics = harness.apply(secretConfig("value"), harness.nullState())
+	})
+	expectDiagnosticError(t, diagnostics, "")
+
+	stored := storedValues(store)
+	if len(stored) != 1 {
+		t.Fatalf("expected 1 stored secret, got %d", len(stored))
+	}
+	id := harness.stringAttribute(state, "id")
+	if _, ok := stored[id]; !ok {
+		t.Fatalf("expected the created secret %s in the state", id)
+	}
+	if note := store.secrets[id].Note; note != "" {
+		t.Fatalf("expected the marker to be removed from the note, got %q", note)
+	}
+}
+
+func TestDeleteSecretsCancellation(t *testing.T) {
+	store := newFakeBitwardenStore(fakeOrganizationID)
+	client := newFaultInjectingClient(store.newClient())
+	if err := client.AccessTokenLogin(fakeAccessToken, nil); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	release := make(chan struct{})
+	defer close(release)
+	// The batch is rejected, so the secrets are deleted one by one, and the first of them blocks.
+	client.inject("Secrets.Delete", fault{err: faultStatus(http.StatusNotFound)}, fault{block: release})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	time.AfterFunc(20*time.Millisecond, cancel)
+	err := deleteSecrets(clientWithContext(ctx, client), nil, []string{"a", "b", "c"})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected the deletion to be cancelled, got %v", err)
+	}
+	if _, known := failedSecretIDs(err); known {
+		t.Fatal("expected the deleted secrets to be unknown")
+	}
+	if count := client.callCount("Secrets.Delete"); count != 2 {
+		t.Fatalf("expected the remaining secrets to be skipped, got %d delete calls", count)
+	}
+}
+
+func TestSecretsResourceCancellation(t *testing.T) {
+	store := newFakeBitwardenStore(fakeOrganizationID)
+	client := newFaultInjectingClient(store.newClient())
+	if err := client.AccessTokenLogin(fakeAccessToken, nil); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	project, err := client.Projects().Create(fakeOrganizationID, "project")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	harness := newResourceHarness(t, "bitwarden-sm_secrets", client)
+	release := make(chan struct{})
+	defer close(release)
+
+	config := func(value string) map[string]tftypes.Value {
+		return map[string]tftypes.Value{
+			"project_id": tftypes.NewValue(tftypes.String, project.ID),
+			"secrets": tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, map[string]tftypes.Value{
+				"API_KEY": tftypes.NewValue(tftypes.String, value),
+			}),
+		}
+	}
+	state, diagnostics := harness.apply(config("value"), harness.nullState())
+	expectDiagnosticError(t, diagnostics, "")
+
+	client.inject("Secrets.GetByIDS", fault{block: release})
+	harness.interrupted(20*time.Millisecond, func() {
+		_, diagnostics = harness.read(state)
+	})
+	expectCancelled(t, diagnostics, "read of the secrets")
+
+	client.inject("Secrets.Update", fault{block: release})
+	harness.interrupted(20*time.Millisecond, func() {
+		_, diagnostics = harness.apply(config("changed"), state)
+	})
+	expectCancelled(t, diagnostics, "update of the secrets")
+}
@@ -0,0 +1,93 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ function.Function = &decryptSecretBackupFunction{}
+
+var secretBackupAttributeTypes = map[string]attr.Type{
+	"id":              types.StringType,
+	"organization_id": types.StringType,
+	"project_id":      types.StringType,
+	"deleted_at":      types.StringType,
+	"key":             types.StringType,
+	"value":           types.StringType,
+	"note":            types.StringType,
+}
+
+// NewDecryptSecretBackupFunction is a helper function to simplify the provider implementation.
+func NewDecryptSecretBackupFunction() function.Function {
+	return &decryptSecretBackupFunction{}
+}
+
+// decryptSecretBackupFunction defines the function implementation.
+type decryptSecretBackupFunction struct{}
+
+func (f *decryptSecretBackupFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "decrypt_secret_backup"
+}
+
+func (f *decryptSecretBackupFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:             "Decrypts the backup of a deleted secret",
+		Description:         "Decrypts a backup file written to the backup_dir of the provider before a secret was deleted and returns an object with the id, organization_id, project_id, deleted_at, key, value and note of the secret. The result is sensitive if the backup key is passed as a sensitive value.",
+		MarkdownDescription: "Decrypts a backup file written to the `backup_dir` of the provider before a secret was deleted and returns an object with the `id`, `organization_id`, `project_id`, `deleted_at`, `key`, `value` and `note` of the secret. The result is sensitive if the backup key is passed as a sensitive value.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "backup",
+				Description:         "The content of the backup file, e.g. read with file().",
+				MarkdownDescription: "The content of the backup file, e.g. read with `file()`.",
+			},
+			function.StringParameter{
+				Name:                "backup_key",
+				Description:         "The backup_key of the provider which wrote the backup file.",
+				MarkdownDescription: "The `backup_key` of the provider which wrote the backup file.",
+			},
+		},
+		Return: function.ObjectReturn{
+			AttributeTypes: secretBackupAttributeTypes,
+		},
+	}
+}
+
+func (f *decryptSecretBackupFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var backup, backupKey string
+	resp.Error = req.Arguments.Get(ctx, &backup, &backupKey)
+	if resp.Error != nil {
+		return
+	}
+
+	var backupFile secretBackupFile
+	if err := json.Unmarshal([]byte(backup), &backupFile); err != nil {
+		resp.Error = function.NewArgumentFuncError(0, "the backup is not a backup file of a secret: "+err.Error())
+		return
+	}
+
+	payload, err := decryptSecretBackup(backupKey, backupFile)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(1, err.Error())
+		return
+	}
+
+	result, diags := types.ObjectValue(secretBackupAttributeTypes, map[string]attr.Value{
+		"id":              types.StringValue(backupFile.ID),
+		"organization_id": types.StringValue(backupFile.OrganizationID),
+		"project_id":      types.StringPointerValue(backupFile.ProjectID),
+		"deleted_at":      types.StringValue(backupFile.DeletedAt),
+		"key":             types.StringValue(payload.Key),
+		"value":           types.StringValue(payload.Value),
+		"note":            types.StringValue(payload.Note),
+	})
+	resp.Error = function.FuncErrorFromDiags(ctx, diags)
+	if resp.Error != nil {
+		return
+	}
+
+	resp.Error = resp.Result.Set(ctx, result)
+}
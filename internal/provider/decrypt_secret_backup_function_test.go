@@ -0,0 +1,117 @@
+package provider
+
+import (
+	"context"
+	"github.com/bitwarden/sdk-go"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// runDecryptSecretBackupFunction runs the decrypt_secret_backup function like terraform does.
+func runDecryptSecretBackupFunction(backup string, backupKey string) (attr.Value, *function.FuncError) {
+	req := function.RunRequest{
+		Arguments: function.NewArgumentsData([]attr.Value{types.StringValue(backup), types.StringValue(backupKey)}),
+	}
+	resp := function.RunResponse{
+		Result: function.NewResultData(types.ObjectUnknown(secretBackupAttributeTypes)),
+	}
+	NewDecryptSecretBackupFunction().Run(context.Background(), req, &resp)
+	return resp.Result.Value(), resp.Error
+}
+
+func TestDecryptSecretBackupFunction(t *testing.T) {
+	dir := t.TempDir()
+	backup, err := newSecretBackup(dir, testBackupKey)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	secret := sdk.SecretResponse{ID: "secret-id", OrganizationID: fakeOrganizationID, Key: "API_KEY", Value: "super-secret-value", Note: "note"}
+	if err := backup.write([]sdk.SecretResponse{secret}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	content, err := os.ReadFile(filepath.Join(dir, entries[0].Name()))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	result, funcErr := runDecryptSecretBackupFunction(string(content), testBackupKey)
+	if funcErr != nil {
+		t.Fatalf("unexpected error: %s", funcErr)
+	}
+	attributes := result.(types.Object).Attributes()
+	expected := map[string]attr.Value{
+		"id":              types.StringValue(secret.ID),
+		"organization_id": types.StringValue(fakeOrganizationID),
+		"project_id":      types.StringNull(),
+		"key":             types.StringValue(secret.Key),
+		"value":           types.StringValue(secret.Value),
+		"note":            types.StringValue(secret.Note),
+	}
+	for name, value := range expected {
+		if !attributes[name].Equal(value) {
+			t.Errorf("expected %s to be %s, got %s", name, value, attributes[name])
+		}
+	}
+	if attributes["deleted_at"].(types.String).ValueString() == "" {
+		t.Error("expected the time of the deletion")
+	}
+}
+
+func TestDecryptSecretBackupFunctionErrors(t *testing.T) {
+	dir := t.TempDir()
+	backup, err := newSecretBackup(dir, testBackupKey)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := backup.write([]sdk.SecretResponse{{ID: "secret-id", Key: "API_KEY", Value: "value"}}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	content, err := os.ReadFile(filepath.Join(dir, entries[0].Name()))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	tests := map[string]struct {
+		backup    string
+		backupKey string
+		expected  string
+	}{
+		"no backup file": {
+			backup:    "API_KEY=value",
+			backupKey: testBackupKey,
+			expected:  "the backup is not a backup file of a secret",
+		},
+		"other backup key": {
+			backup:    string(content),
+			backupKey: "Hx4dHBsaGRgXFhUUExIREA8ODQwLCgkIBwYFBAMCAQA=",
+			expected:  "unable to decrypt the backup of secret secret-id",
+		},
+		"invalid backup key": {
+			backup:    string(content),
+			backupKey: "AAECAwQFBgcICQoLDA0ODw==",
+			expected:  "the backup key must be at least 32 bytes long",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, funcErr := runDecryptSecretBackupFunction(test.backup, test.backupKey)
+			if funcErr == nil || !strings.Contains(funcErr.Error(), test.expected) {
+				t.Fatalf("expected an error containing %q, got: %v", test.expected, funcErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+const (
+	// driftPolicyAdopt imports secret values changed outside of terraform into the state (Dynamic Secrets).
+	driftPolicyAdopt = "adopt"
+	// driftPolicyRevert plans an update which writes the configured or generated value back.
+	driftPolicyRevert = "revert"
+	// driftPolicyError fails the plan if the secret value was changed outside of terraform.
+	driftPolicyError = "error"
+
+	// valueDriftPrivateStateKey is the private state key used to pass a detected drift from Read to ModifyPlan.
+	valueDriftPrivateStateKey = "value_drift"
+)
+
+// valueDrift describes a secret value change outside of terraform. It never contains the value itself.
+type valueDrift struct {
+	RevisionDate string `json:"revision_date"`
+}
+
+// privateStateReader is implemented by the private state data of all resource requests.
+type privateStateReader interface {
+	GetKey(ctx context.Context, key string) ([]byte, diag.Diagnostics)
+}
+
+// getValueDrift returns the value drift recorded during the last Read or nil if no drift was detected.
+func getValueDrift(ctx context.Context, private privateStateReader) (*valueDrift, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	if private == nil {
+		return nil, diags
+	}
+
+	data, getDiags := private.GetKey(ctx, valueDriftPrivateStateKey)
+	diags.Append(getDiags...)
+	if diags.HasError() || len(data) == 0 {
+		return nil, diags
+	}
+
+	var drift valueDrift
+	if err := json.Unmarshal(data, &drift); err != nil {
+		diags.AddError(
+			"Unable to Read Private State",
+			"The recorded secret value drift could not be decoded: "+err.Error(),
+		)
+		return nil, diags
+	}
+
+	return &drift, diags
+}
+
+// encodeValueDrift returns the private state representation of a value drift.
+func encodeValueDrift(drift valueDrift) []byte {
+	data, _ := json.Marshal(drift)
+	return data
+}
+
+// secretValueDrifted reports whether the value read from Bitwarden Secrets Manager differs from the value in the state.
+// Secrets managed via value_json are compared as JSON documents, all other secrets using the secret string normalisation.
+func secretValueDrifted(state *secretResourceModel, remote string) bool {
+	if state.Value.IsNull() || state.Value.IsUnknown() {
+		return false
+	}
+
+	if !state.ValueJSON.IsNull() {
+		normalisedRemote, err := normaliseJSON(remote)
+		if err != nil {
+			return true
+		}
+		normalisedState, err := normaliseJSON(state.Value.ValueString())
+		if err != nil {
+			return true
+		}
+		return normalisedRemote != normalisedState
+	}
+
+	normalization := secretNormalization(state.TrimTrailingNewline, state.NormalizeLineEndings)
+	return normalization.apply(state.Value.ValueString()) != normalization.apply(remote)
+}
+
+// driftPolicy returns the effective drift policy, imported resources without a policy adopt changes.
+func driftPolicy(state *secretResourceModel) string {
+	if state.DriftPolicy.IsNull() || state.DriftPolicy.IsUnknown() || state.DriftPolicy.ValueString() == "" {
+		return driftPolicyAdopt
+	}
+	return state.DriftPolicy.ValueString()
+}
@@ -0,0 +1,74 @@
+package provider
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"testing"
+)
+
+func TestSecretValueDrifted(t *testing.T) {
+	testCases := map[string]struct {
+		state    secretResourceModel
+		remote   string
+		expected bool
+	}{
+		"unchanged value": {
+			state:    secretResourceModel{Value: normalizedString(types.StringValue("secret")), ValueJSON: types.DynamicNull()},
+			remote:   "secret",
+			expected: false,
+		},
+		"trailing newline is no drift": {
+			state:    secretResourceModel{Value: normalizedString(types.StringValue("secret\n")), ValueJSON: types.DynamicNull()},
+			remote:   "secret",
+			expected: false,
+		},
+		"changed value": {
+			state:    secretResourceModel{Value: normalizedString(types.StringValue("secret")), ValueJSON: types.DynamicNull()},
+			remote:   "changed",
+			expected: true,
+		},
+		"imported secret without value": {
+			state:    secretResourceModel{Value: normalizedString(types.StringNull()), ValueJSON: types.DynamicNull()},
+			remote:   "secret",
+			expected: false,
+		},
+		"reformatted JSON document is no drift": {
+			state:    secretResourceModel{Value: normalizedString(types.StringValue(`{"a":1,"b":2}`)), ValueJSON: types.DynamicValue(types.StringValue(`{"a":1,"b":2}`))},
+			remote:   "{\n  \"b\": 2,\n  \"a\": 1\n}",
+			expected: false,
+		},
+		"changed JSON document": {
+			state:    secretResourceModel{Value: normalizedString(types.StringValue(`{"a":1}`)), ValueJSON: types.DynamicValue(types.StringValue(`{"a":1}`))},
+			remote:   `{"a":2}`,
+			expected: true,
+		},
+		"invalid JSON document": {
+			state:    secretResourceModel{Value: normalizedString(types.StringValue(`{"a":1}`)), ValueJSON: types.DynamicValue(types.StringValue(`{"a":1}`))},
+			remote:   "not json",
+			expected: true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if drifted := secretValueDrifted(&testCase.state, testCase.remote); drifted != testCase.expected {
+				t.Fatalf("expected drift %t, got %t", testCase.expected, drifted)
+			}
+		})
+	}
+}
+
+func TestDriftPolicy(t *testing.T) {
+	if policy := driftPolicy(&secretResourceModel{DriftPolicy: types.StringNull()}); policy != driftPolicyAdopt {
+		t.Fatalf("expected imported secrets to adopt changes, got %s", policy)
+	}
+	if policy := driftPolicy(&secretResourceModel{DriftPolicy: types.StringValue(driftPolicyRevert)}); policy != driftPolicyRevert {
+		t.Fatalf("expected %s, got %s", driftPolicyRevert, policy)
+	}
+}
+
+func TestEncodeValueDrift(t *testing.T) {
+	data := encodeValueDrift(valueDrift{RevisionDate: "2024-01-01 00:00:00 +0000 UTC"})
+	if string(data) != `{"revision_date":"2024-01-01 00:00:00 +0000 UTC"}` {
+		t.Fatalf("unexpected private state representation: %s", data)
+	}
+}
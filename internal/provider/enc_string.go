@@ -0,0 +1,121 @@
+package provider
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"golang.org/x/crypto/hkdf"
+	"io"
+	"strings"
+)
+
+// symmetricKey is a Bitwarden AES-256-CBC key with an HMAC-SHA256 key, used for EncStrings of type 2.
+type symmetricKey struct {
+	EncKey []byte
+	MacKey []byte
+}
+
+// newSymmetricKey splits a 64 byte key into its encryption and MAC keys.
+func newSymmetricKey(key []byte) (symmetricKey, error) {
+	if len(key) != 64 {
+		return symmetricKey{}, fmt.Errorf("expected a key of 64 bytes, got %d bytes", len(key))
+	}
+	return symmetricKey{EncKey: key[:32], MacKey: key[32:]}, nil
+}
+
+// bytes returns the 64 byte representation of the key.
+func (k symmetricKey) bytes() []byte {
+	return append(append([]byte{}, k.EncKey...), k.MacKey...)
+}
+
+// deriveShareableKey derives a symmetric key from a secret the same way the Bitwarden SDK does for access tokens:
+// HMAC-SHA256 keyed with "bitwarden-<name>" as pseudorandom key followed by an HKDF-SHA256 expansion to 64 bytes.
+func deriveShareableKey(secret []byte, name string, info string) symmetricKey {
+	prkHmac := hmac.New(sha256.New, []byte("bitwarden-"+name))
+	prkHmac.Write(secret)
+	prk := prkHmac.Sum(nil)
+
+	// The expansion only fails for more than 255 blocks of output.
+	keyBytes := make([]byte, 64)
+	_, _ = io.ReadFull(hkdf.Expand(sha256.New, prk, []byte(info)), keyBytes)
+	key, _ := newSymmetricKey(keyBytes)
+	return key
+}
+
+// encryptString encrypts plaintext into an EncString of type 2: "2.<iv>|<ciphertext>|<mac>".
+func encryptString(key symmetricKey, plaintext []byte) (string, error) {
+	block, err := aes.NewCipher(key.EncKey)
+	if err != nil {
+		return "", err
+	}
+
+	iv := make([]byte, aes.BlockSize)
+	if _, err := rand.Read(iv); err != nil {
+		return "", err
+	}
+
+	padding := aes.BlockSize - len(plaintext)%aes.BlockSize
+	padded := append(append([]byte{}, plaintext...), bytes.Repeat([]byte{byte(padding)}, padding)...)
+	ciphertext := make([]byte, len(padded))
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(ciphertext, padded)
+
+	return "2." + base64.StdEncoding.EncodeToString(iv) +
+		"|" + base64.StdEncoding.EncodeToString(ciphertext) +
+		"|" + base64.StdEncoding.EncodeToString(encStringMac(key, iv, ciphertext)), nil
+}
+
+// decryptString decrypts an EncString of type 2 after verifying its MAC.
+func decryptString(key symmetricKey, encString string) ([]byte, error) {
+	encType, data, found := strings.Cut(encString, ".")
+	if !found || encType != "2" {
+		return nil, errors.New("unsupported EncString type, only type 2 is supported")
+	}
+
+	parts := strings.Split(data, "|")
+	if len(parts) != 3 {
+		return nil, errors.New("invalid EncString, expected iv, data and mac")
+	}
+
+	var decoded [3][]byte
+	for i, part := range parts {
+		value, err := base64.StdEncoding.DecodeString(part)
+		if err != nil {
+			return nil, fmt.Errorf("invalid EncString: %w", err)
+		}
+		decoded[i] = value
+	}
+	iv, ciphertext, mac := decoded[0], decoded[1], decoded[2]
+
+	if !hmac.Equal(mac, encStringMac(key, iv, ciphertext)) {
+		return nil, errors.New("invalid EncString, MAC verification failed")
+	}
+	if len(iv) != aes.BlockSize || len(ciphertext) == 0 || len(ciphertext)%aes.BlockSize != 0 {
+		return nil, errors.New("invalid EncString, unexpected length of iv or data")
+	}
+
+	block, err := aes.NewCipher(key.EncKey)
+	if err != nil {
+		return nil, err
+	}
+	plaintext := make([]byte, len(ciphertext))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(plaintext, ciphertext)
+
+	padding := int(plaintext[len(plaintext)-1])
+	if padding == 0 || padding > aes.BlockSize || !bytes.Equal(plaintext[len(plaintext)-padding:], bytes.Repeat([]byte{byte(padding)}, padding)) {
+		return nil, errors.New("invalid EncString, invalid padding")
+	}
+	return plaintext[:len(plaintext)-padding], nil
+}
+
+func encStringMac(key symmetricKey, iv []byte, ciphertext []byte) []byte {
+	mac := hmac.New(sha256.New, key.MacKey)
+	mac.Write(iv)
+	mac.Write(ciphertext)
+	return mac.Sum(nil)
+}
@@ -0,0 +1,153 @@
+package provider
+
+import (
+	"bytes"
+	"encoding/base64"
+	"strings"
+	"testing"
+)
+
+// The expected keys are the test vectors of the Bitwarden SDK for shareable keys (bitwarden-crypto, shareable_key.rs)
+// and for the encryption key of an access token (bitwarden-core, access_token.rs).
+func TestDeriveShareableKeySDKVectors(t *testing.T) {
+	accessTokenSecret, _ := base64.StdEncoding.DecodeString("X8vbvA0bduihIDe/qrzIQQ==")
+
+	tests := map[string]struct {
+		secret   []byte
+		name     string
+		info     string
+		expected string
+	}{
+		"shareable key": {
+			secret:   []byte("&/$%F1a895g67HlX"),
+			name:     "test_key",
+			expected: "4PV6+PcmF2w7YHRatvyMcVQtI7zvCyssv/wFWmzjiH6Iv9altjmDkuBD1aagLVaLezbthbSe+ktR+U6qswxNnQ==",
+		},
+		"access token": {
+			secret:   accessTokenSecret,
+			name:     "accesstoken",
+			info:     "sm-access-token",
+			expected: "H9/oIRLtL9nGCQOVDjSMoEbJsjWXSOCb3qeyDt6ckzS3FhyboEDWyTP/CQfbIszNmAVg2ExFganG1FVFGXO/Jg==",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			key := deriveShareableKey(test.secret, test.name, test.info)
+			if encoded := base64.StdEncoding.EncodeToString(key.bytes()); encoded != test.expected {
+				t.Fatalf("expected the key %s, got %s", test.expected, encoded)
+			}
+		})
+	}
+}
+
+// The EncStrings were encrypted by the Bitwarden SDK with the organization key when it created a project and a secret
+// at secretsManagerStandIn.
+func TestDecryptStringSDKVectors(t *testing.T) {
+	keyBytes, _ := base64.StdEncoding.DecodeString("GqZA3FUbs6nqxjAfk1m8M3VJ0pEqnVM3uCyY4qA6s/HoeVX7o3OKqzzBwtK/qRR5A6yax2j0Iodhp0AZSOLQtw==")
+	key, err := newSymmetricKey(keyBytes)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	tests := map[string]string{
+		"2.G/9TsmDI31Ez2c/FKH9hiA==|Vv7AolJuVftAoo1lidJyNw==|xZoLTj9RLsQKkHfHF0KpNB27x1iuwUWi0KhG/fIDbl4=":                     "project",
+		"2.CS8HXlm1aMY1r2yHjdaEzw==|EtwoenTFW3X5gg3tsYObPvdAApv83MzHa1WHKBBmMng=|6ftJZnRQ4GY5R94pE0NlGLalhDk0cZjEAcX0xwyToWI=": "multi\nline value ✓",
+	}
+	for encString, expected := range tests {
+		plaintext, err := decryptString(key, encString)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if string(plaintext) != expected {
+			t.Fatalf("expected %q, got %q", expected, plaintext)
+		}
+	}
+}
+
+func TestDeriveShareableKey(t *testing.T) {
+	secret := []byte("0123456789abcdef")
+
+	key := deriveShareableKey(secret, "accesstoken", "sm-access-token")
+	if len(key.EncKey) != 32 || len(key.MacKey) != 32 {
+		t.Fatalf("unexpected key lengths: %d %d", len(key.EncKey), len(key.MacKey))
+	}
+	if !bytes.Equal(key.bytes(), deriveShareableKey(secret, "accesstoken", "sm-access-token").bytes()) {
+		t.Fatal("expected the derivation to be deterministic")
+	}
+	if bytes.Equal(key.bytes(), deriveShareableKey(secret, "accesstoken", "other").bytes()) {
+		t.Fatal("expected different keys for different info")
+	}
+}
+
+func TestEncString(t *testing.T) {
+	key := newTestSymmetricKey(t)
+
+	for _, plaintext := range []string{"", "a", "exactly 16 bytes", "a name which is longer than a single block"} {
+		encString, err := encryptString(key, []byte(plaintext))
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if !strings.HasPrefix(encString, "2.") || strings.Count(encString, "|") != 2 {
+			t.Fatalf("unexpected EncString format: %s", encString)
+		}
+
+		decrypted, err := decryptString(key, encString)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if string(decrypted) != plaintext {
+			t.Fatalf("expected %q, got %q", plaintext, decrypted)
+		}
+	}
+}
+
+func TestDecryptStringErrors(t *testing.T) {
+	key := newTestSymmetricKey(t)
+	encString, err := encryptString(key, []byte("secret"))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	parts := strings.Split(strings.TrimPrefix(encString, "2."), "|")
+
+	tests := map[string]struct {
+		key       symmetricKey
+		encString string
+		expected  string
+	}{
+		"wrong key": {
+			key:       newTestSymmetricKey(t),
+			encString: encString,
+			expected:  "MAC verification failed",
+		},
+		"tampered data": {
+			key:       key,
+			encString: "2." + parts[0] + "|" + parts[0] + "|" + parts[2],
+			expected:  "MAC verification failed",
+		},
+		"unsupported type": {
+			key:       key,
+			encString: "0." + parts[0] + "|" + parts[1],
+			expected:  "unsupported EncString type",
+		},
+		"missing mac": {
+			key:       key,
+			encString: "2." + parts[0] + "|" + parts[1],
+			expected:  "expected iv, data and mac",
+		},
+		"invalid base64": {
+			key:       key,
+			encString: "2.iv|data|mac",
+			expected:  "invalid EncString",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := decryptString(test.key, test.encString)
+			if err == nil || !strings.Contains(err.Error(), test.expected) {
+				t.Fatalf("expected an error containing %q, got: %v", test.expected, err)
+			}
+		})
+	}
+}
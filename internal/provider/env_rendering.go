@@ -0,0 +1,108 @@
+package provider
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// envSecret is a secret of a key→value map with its key sanitised to an environment variable name.
+type envSecret struct {
+	Key   string
+	Value string
+}
+
+// sanitizeEnvKey turns a secret key into an environment variable name: letters are uppercased, every character
+// other than A-Z, 0-9 and _ is replaced by _, and names starting with a digit are prefixed with _.
+func sanitizeEnvKey(key string) string {
+	var builder strings.Builder
+	for _, char := range strings.ToUpper(key) {
+		if (char >= 'A' && char <= 'Z') || (char >= '0' && char <= '9') || char == '_' {
+			builder.WriteRune(char)
+		} else {
+			builder.WriteRune('_')
+		}
+	}
+
+	name := builder.String()
+	if name != "" && name[0] >= '0' && name[0] <= '9' {
+		name = "_" + name
+	}
+	return name
+}
+
+// sanitizeEnvSecrets sanitises all keys of secrets and returns them sorted by their sanitised key. Keys which
+// are empty or collide after sanitisation are rejected, since one of the values would silently be lost.
+func sanitizeEnvSecrets(secrets map[string]types.String) ([]envSecret, error) {
+	originalKeys := map[string]string{}
+	sanitized := make([]envSecret, 0, len(secrets))
+
+	for _, key := range sortedKeys(secrets) {
+		value := secrets[key]
+		if value.IsNull() {
+			return nil, fmt.Errorf("the value of key %q must not be null", key)
+		}
+
+		name := sanitizeEnvKey(key)
+		if name == "" {
+			return nil, fmt.Errorf("the key %q is empty", key)
+		}
+		if other, ok := originalKeys[name]; ok {
+			return nil, fmt.Errorf("the keys %q and %q are both sanitised to %q", other, key, name)
+		}
+		originalKeys[name] = key
+
+		sanitized = append(sanitized, envSecret{Key: name, Value: value.ValueString()})
+	}
+
+	// Sanitisation changes the order of some keys, e.g. "a-b" and "A_A".
+	sort.Slice(sanitized, func(i, j int) bool {
+		return sanitized[i].Key < sanitized[j].Key
+	})
+	return sanitized, nil
+}
+
+// dotenvQuote quotes a value for .env files. Values without single quotes, backslashes and line breaks are
+// single-quoted, which dotenv parsers and shells read literally, without expanding $VAR or backticks. All other values
+// are double-quoted with backslashes, double quotes, dollar signs, backticks and line breaks escaped with a backslash.
+// Tabs are not escaped, since some parsers, e.g. godotenv, do not understand \t.
+func dotenvQuote(value string) string {
+	if !strings.ContainsAny(value, "'\\\n\r") {
+		return "'" + value + "'"
+	}
+
+	replacer := strings.NewReplacer(
+		`\`, `\\`,
+		`"`, `\"`,
+		`$`, `\$`,
+		"`", "\\`",
+		"\n", `\n`,
+		"\r", `\r`,
+	)
+	return `"` + replacer.Replace(value) + `"`
+}
+
+// jsonQuote encodes a string as JSON string without escaping HTML characters. JSON strings are also valid YAML
+// double-quoted scalars.
+func jsonQuote(value string) string {
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	// Encoding a string cannot fail.
+	_ = encoder.Encode(value)
+	return strings.TrimSuffix(buffer.String(), "\n")
+}
+
+// kubernetesNameRegex matches valid names of Kubernetes objects (RFC 1123 subdomains).
+var kubernetesNameRegex = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`)
+
+func validateKubernetesName(name string) error {
+	if len(name) > 253 || !kubernetesNameRegex.MatchString(name) {
+		return fmt.Errorf("%q is not a valid Kubernetes name: it must consist of at most 253 lower case alphanumeric characters, '-' or '.', and must start and end with an alphanumeric character", name)
+	}
+	return nil
+}
@@ -0,0 +1,256 @@
+package provider
+
+import (
+	"context"
+	"flag"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/joho/godotenv"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var updateGolden = flag.Bool("update", false, "update the golden files in testdata")
+
+// goldenSecrets contains keys which need sanitisation and values which need escaping.
+var goldenSecrets = map[string]string{
+	"database-password": `p@ss"wo\rd$HOME`,
+	"api.key":           "<abc>&def",
+	"multi_line":        "line 1\nline 2\r\n\ttabbed",
+	"1st_token":         "'single' quotes",
+	"Empty":             "",
+	"unicode":           "äöü ✓ \u2028",
+	"command":           "`id` $(whoami) ${HOME}",
+}
+
+func envSecretsMap(secrets map[string]string) types.Map {
+	elements := map[string]attr.Value{}
+	for key, value := range secrets {
+		elements[key] = types.StringValue(value)
+	}
+	return types.MapValueMust(types.StringType, elements)
+}
+
+func runRenderFunction(t *testing.T, f function.Function, arguments ...attr.Value) (string, *function.FuncError) {
+	t.Helper()
+
+	req := function.RunRequest{
+		Arguments: function.NewArgumentsData(arguments),
+	}
+	resp := function.RunResponse{
+		Result: function.NewResultData(types.StringUnknown()),
+	}
+
+	f.Run(context.Background(), req, &resp)
+	if resp.Error != nil {
+		return "", resp.Error
+	}
+	return resp.Result.Value().(types.String).ValueString(), nil
+}
+
+// assertGolden compares actual with the content of testdata/<name>. Run the tests with -update to rewrite the file.
+func assertGolden(t *testing.T, name string, actual string) {
+	t.Helper()
+
+	path := filepath.Join("testdata", name)
+	if *updateGolden {
+		if err := os.WriteFile(path, []byte(actual), 0o644); err != nil {
+			t.Fatalf("unable to update golden file: %s", err)
+		}
+	}
+
+	expected, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("unable to read golden file: %s", err)
+	}
+	if string(expected) != actual {
+		t.Fatalf("output differs from %s:\nexpected:\n%s\nactual:\n%s", path, expected, actual)
+	}
+}
+
+func TestSanitizeEnvKey(t *testing.T) {
+	tests := map[string]string{
+		"DATABASE_PASSWORD": "DATABASE_PASSWORD",
+		"database-password": "DATABASE_PASSWORD",
+		"api.key":           "API_KEY",
+		"path/to/secret":    "PATH_TO_SECRET",
+		"1st_token":         "_1ST_TOKEN",
+		"_private":          "_PRIVATE",
+		"with space":        "WITH_SPACE",
+		"ümlaut":            "_MLAUT",
+		"":                  "",
+	}
+
+	for key, expected := range tests {
+		if actual := sanitizeEnvKey(key); actual != expected {
+			t.Errorf("expected %q to be sanitised to %q, got %q", key, expected, actual)
+		}
+	}
+}
+
+func TestSanitizeEnvSecretsErrors(t *testing.T) {
+	tests := map[string]struct {
+		secrets  map[string]types.String
+		expected string
+	}{
+		"collision": {
+			secrets: map[string]types.String{
+				"db-password": types.StringValue("a"),
+				"DB_PASSWORD": types.StringValue("b"),
+			},
+			expected: `the keys "DB_PASSWORD" and "db-password" are both sanitised to "DB_PASSWORD"`,
+		},
+		"empty key": {
+			secrets:  map[string]types.String{"": types.StringValue("a")},
+			expected: `the key "" is empty`,
+		},
+		"null value": {
+			secrets:  map[string]types.String{"key": types.StringNull()},
+			expected: `the value of key "key" must not be null`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := sanitizeEnvSecrets(test.secrets)
+			if err == nil || err.Error() != test.expected {
+				t.Fatalf("expected the error %q, got: %v", test.expected, err)
+			}
+		})
+	}
+}
+
+func TestToDotenvFunction(t *testing.T) {
+	output, err := runRenderFunction(t, NewToDotenvFunction(), envSecretsMap(goldenSecrets))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	assertGolden(t, "secrets.env.golden", output)
+
+	output, err = runRenderFunction(t, NewToDotenvFunction(), envSecretsMap(map[string]string{}))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if output != "" {
+		t.Fatalf("expected an empty file for no secrets, got %q", output)
+	}
+}
+
+// TestToDotenvFunctionRoundTrip parses the rendered .env file with godotenv, which expands variables and escape
+// sequences in double-quoted values, and expects the original values. godotenv misreads values ending with a backslash
+// or a double quote, which cannot be escaped for it, so no value ends with one.
+func TestToDotenvFunctionRoundTrip(t *testing.T) {
+	secrets := map[string]string{
+		"dollar":         "$HOME ${HOME} $(id) \\$HOME",
+		"backticks":      "`id` \\`id\\`",
+		"quotes":         `it's "quoted".`,
+		"backslashes":    `C:\new\table \n`,
+		"windows":        "line 1\r\nline 2",
+		"mixed":          "'$HOME'\n\t\"`id`\" ok",
+		"hash":           "value # not a comment",
+		"spaces":         "  padded  ",
+		"line_separator": "a\u2028b\u2029c",
+	}
+	for key, value := range goldenSecrets {
+		secrets[key] = value
+	}
+
+	output, err := runRenderFunction(t, NewToDotenvFunction(), envSecretsMap(secrets))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	parsed, parseErr := godotenv.Unmarshal(output)
+	if parseErr != nil {
+		t.Fatalf("unable to parse the rendered .env file: %s\n%s", parseErr, output)
+	}
+
+	if len(parsed) != len(secrets) {
+		t.Fatalf("expected %d variables, got %d: %v", len(secrets), len(parsed), parsed)
+	}
+	for key, value := range secrets {
+		if parsed[sanitizeEnvKey(key)] != value {
+			t.Errorf("expected %s to be parsed as %q, got %q", sanitizeEnvKey(key), value, parsed[sanitizeEnvKey(key)])
+		}
+	}
+}
+
+func TestToEnvJSONFunction(t *testing.T) {
+	output, err := runRenderFunction(t, NewToEnvJSONFunction(), envSecretsMap(goldenSecrets))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	assertGolden(t, "secrets.json.golden", output)
+
+	output, err = runRenderFunction(t, NewToEnvJSONFunction(), envSecretsMap(map[string]string{}))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if output != "{}\n" {
+		t.Fatalf("expected an empty object for no secrets, got %q", output)
+	}
+}
+
+func TestToK8sSecretYAMLFunction(t *testing.T) {
+	output, err := runRenderFunction(t, NewToK8sSecretYAMLFunction(), types.StringValue("app-secrets"), envSecretsMap(goldenSecrets))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	assertGolden(t, "secrets.k8s.yaml.golden", output)
+
+	output, err = runRenderFunction(t, NewToK8sSecretYAMLFunction(), types.StringValue("empty"), envSecretsMap(map[string]string{}))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	assertGolden(t, "empty.k8s.yaml.golden", output)
+}
+
+func TestRenderFunctionErrors(t *testing.T) {
+	collision := envSecretsMap(map[string]string{"a-b": "1", "A_B": "2"})
+
+	tests := map[string]struct {
+		function  function.Function
+		arguments []attr.Value
+		argument  int64
+		expected  string
+	}{
+		"dotenv collision": {
+			function:  NewToDotenvFunction(),
+			arguments: []attr.Value{collision},
+			argument:  0,
+			expected:  `are both sanitised to "A_B"`,
+		},
+		"json collision": {
+			function:  NewToEnvJSONFunction(),
+			arguments: []attr.Value{collision},
+			argument:  0,
+			expected:  `are both sanitised to "A_B"`,
+		},
+		"k8s collision": {
+			function:  NewToK8sSecretYAMLFunction(),
+			arguments: []attr.Value{types.StringValue("app"), collision},
+			argument:  1,
+			expected:  `are both sanitised to "A_B"`,
+		},
+		"k8s invalid name": {
+			function:  NewToK8sSecretYAMLFunction(),
+			arguments: []attr.Value{types.StringValue("App_Secrets"), envSecretsMap(map[string]string{})},
+			argument:  0,
+			expected:  `"App_Secrets" is not a valid Kubernetes name`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := runRenderFunction(t, test.function, test.arguments...)
+			if err == nil || !strings.Contains(err.Error(), test.expected) {
+				t.Fatalf("expected an error containing %q, got: %v", test.expected, err)
+			}
+			if err.FunctionArgument == nil || *err.FunctionArgument != test.argument {
+				t.Fatalf("expected the error to refer to argument %d, got: %v", test.argument, err.FunctionArgument)
+			}
+		})
+	}
+}
@@ -0,0 +1,432 @@
+package provider
+
+import (
+	"fmt"
+	"github.com/bitwarden/sdk-go"
+	"github.com/google/uuid"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// fakeOrganizationID is the organization of all projects and secrets of the fake Bitwarden client.
+	fakeOrganizationID = "3b2c3d6e-9b3f-4a7e-8f3a-b2a600f0c1f5"
+	// fakeAccessToken is accepted by the fake Bitwarden client and grants access to all projects and secrets.
+	fakeAccessToken = "0.5d5a8a06-2d3b-4f0e-9a39-b2a600f0c1f4.fake-client-secret:AAECAwQFBgcICQoLDA0ODw=="
+	// fakeNoAccessToken is accepted by the fake Bitwarden client, but grants access to no project or secret.
+	fakeNoAccessToken = "0.6e6b9b17-3e4c-4f1f-8b4a-c3b711f1d2e5.fake-client-secret:AAECAwQFBgcICQoLDA0ODw=="
+)
+
+// fakeBitwardenStore holds the projects and secrets of an in-memory Bitwarden Secrets Manager organization. All
+// clients created by the same store share its data, like clients of the same server do.
+type fakeBitwardenStore struct {
+	mu             sync.Mutex
+	organizationId string
+	projects       map[string]sdk.ProjectResponse
+	secrets        map[string]sdk.SecretResponse
+	// accessTokens maps the accepted access tokens to whether they grant access to the projects and secrets.
+	accessTokens map[string]bool
+}
+
+func newFakeBitwardenStore(organizationId string) *fakeBitwardenStore {
+	return &fakeBitwardenStore{
+		organizationId: organizationId,
+		projects:       map[string]sdk.ProjectResponse{},
+		secrets:        map[string]sdk.SecretResponse{},
+		accessTokens: map[string]bool{
+			fakeAccessToken:   true,
+			fakeNoAccessToken: false,
+		},
+	}
+}
+
+// newClient returns an unauthenticated client of the store, like sdk.NewBitwardenClient.
+func (s *fakeBitwardenStore) newClient() *fakeBitwardenClient {
+	return &fakeBitwardenClient{store: s}
+}
+
+// fakeAPIError returns an error in the format of errors returned by the Bitwarden SDK for failed API requests.
+func fakeAPIError(statusCode int, message string) error {
+	return fmt.Errorf("API error: Received error message from server: [%d %s] %s", statusCode, http.StatusText(statusCode), message)
+}
+
+// fakeBitwardenClient implements sdk.BitwardenClientInterface on top of a fakeBitwardenStore.
+type fakeBitwardenClient struct {
+	store *fakeBitwardenStore
+	// authenticated and hasAccess are set by AccessTokenLogin. Requests without a successful login fail.
+	authenticated bool
+	hasAccess     bool
+}
+
+var _ sdk.BitwardenClientInterface = &fakeBitwardenClient{}
+
+func (c *fakeBitwardenClient) AccessTokenLogin(accessToken string, _ *string) error {
+	c.store.mu.Lock()
+	defer c.store.mu.Unlock()
+
+	hasAccess, ok := c.store.accessTokens[accessToken]
+	if !ok {
+		return fakeAPIError(http.StatusBadRequest, "invalid_client")
+	}
+
+	c.authenticated = true
+	c.hasAccess = hasAccess
+	return nil
+}
+
+func (c *fakeBitwardenClient) Projects() sdk.ProjectsInterface {
+	return &fakeProjects{client: c}
+}
+
+func (c *fakeBitwardenClient) Secrets() sdk.SecretsInterface {
+	return &fakeSecrets{client: c}
+}
+
+func (c *fakeBitwardenClient) Generators() sdk.GeneratorsInterface {
+	return &fakeGenerators{}
+}
+
+func (c *fakeBitwardenClient) Close() {}
+
+// lock locks the store and checks that the client may access it. The caller must unlock the store if no error
+// is returned.
+func (c *fakeBitwardenClient) lock(organizationId string) error {
+	if !c.authenticated {
+		return fmt.Errorf("API error: Access token is not authenticated")
+	}
+
+	c.store.mu.Lock()
+	if organizationId != "" && organizationId != c.store.organizationId {
+		c.store.mu.Unlock()
+		return fakeAPIError(http.StatusNotFound, "Resource not found.")
+	}
+	return nil
+}
+
+type fakeProjects struct {
+	client *fakeBitwardenClient
+}
+
+func (p *fakeProjects) Create(organizationID string, name string) (*sdk.ProjectResponse, error) {
+	if err := p.client.lock(organizationID); err != nil {
+		return nil, err
+	}
+	defer p.client.store.mu.Unlock()
+
+	if name == "" {
+		return nil, fakeAPIError(http.StatusBadRequest, "The Name field is required.")
+	}
+
+	now := time.Now().UTC()
+	project := sdk.ProjectResponse{
+		ID:             uuid.NewString(),
+		Name:           name,
+		OrganizationID: organizationID,
+		CreationDate:   now,
+		RevisionDate:   now,
+	}
+	p.client.store.projects[project.ID] = project
+
+	return &project, nil
+}
+
+func (p *fakeProjects) List(organizationID string) (*sdk.ProjectsResponse, error) {
+	if err := p.client.lock(organizationID); err != nil {
+		return nil, err
+	}
+	defer p.client.store.mu.Unlock()
+
+	projects := []sdk.ProjectResponse{}
+	if p.client.hasAccess {
+		for _, id := range sortedKeys(p.client.store.projects) {
+			projects = append(projects, p.client.store.projects[id])
+		}
+	}
+
+	return &sdk.ProjectsResponse{Data: projects}, nil
+}
+
+func (p *fakeProjects) Get(projectID string) (*sdk.ProjectResponse, error) {
+	if err := p.client.lock(""); err != nil {
+		return nil, err
+	}
+	defer p.client.store.mu.Unlock()
+
+	project, ok := p.client.store.projects[projectID]
+	if !ok || !p.client.hasAccess {
+		return nil, fakeAPIError(http.StatusNotFound, "Resource not found.")
+	}
+
+	return &project, nil
+}
+
+func (p *fakeProjects) Update(projectID string, organizationID string, name string) (*sdk.ProjectResponse, error) {
+	if err := p.client.lock(organizationID); err != nil {
+		return nil, err
+	}
+	defer p.client.store.mu.Unlock()
+
+	project, ok := p.client.store.projects[projectID]
+	if !ok || !p.client.hasAccess {
+		return nil, fakeAPIError(http.StatusNotFound, "Resource not found.")
+	}
+	if name == "" {
+		return nil, fakeAPIError(http.StatusBadRequest, "The Name field is required.")
+	}
+
+	project.Name = name
+	project.RevisionDate = time.Now().UTC()
+	p.client.store.projects[projectID] = project
+
+	return &project, nil
+}
+
+func (p *fakeProjects) Delete(projectIDs []string) (*sdk.ProjectsDeleteResponse, error) {
+	if err := p.client.lock(""); err != nil {
+		return nil, err
+	}
+	defer p.client.store.mu.Unlock()
+
+	response := &sdk.ProjectsDeleteResponse{Data: []sdk.ProjectDeleteResponse{}}
+	for _, id := range projectIDs {
+		if _, ok := p.client.store.projects[id]; !ok || !p.client.hasAccess {
+			message := "Project not found."
+			response.Data = append(response.Data, sdk.ProjectDeleteResponse{ID: id, Error: &message})
+			continue
+		}
+
+		delete(p.client.store.projects, id)
+		// Like in Bitwarden Secrets Manager, the secrets of a deleted project are kept without a project.
+		for secretID, secret := range p.client.store.secrets {
+			if secret.ProjectID != nil && *secret.ProjectID == id {
+				secret.ProjectID = nil
+				p.client.store.secrets[secretID] = secret
+			}
+		}
+		response.Data = append(response.Data, sdk.ProjectDeleteResponse{ID: id})
+	}
+
+	return response, nil
+}
+
+type fakeSecrets struct {
+	client *fakeBitwardenClient
+}
+
+// projectID validates the project IDs of a create or update request and returns the project of the secret.
+func (s *fakeSecrets) projectID(projectIDs []string) (*string, error) {
+	if len(projectIDs) > 1 {
+		return nil, fakeAPIError(http.StatusBadRequest, "A secret can only be in one project.")
+	}
+	if len(projectIDs) == 0 {
+		return nil, nil
+	}
+
+	if _, ok := s.client.store.projects[projectIDs[0]]; !ok || !s.client.hasAccess {
+		return nil, fakeAPIError(http.StatusNotFound, "Resource not found.")
+	}
+	projectID := projectIDs[0]
+	return &projectID, nil
+}
+
+func (s *fakeSecrets) Create(key, value, note string, organizationID string, projectIDs []string) (*sdk.SecretResponse, error) {
+	if err := s.client.lock(organizationID); err != nil {
+		return nil, err
+	}
+	defer s.client.store.mu.Unlock()
+
+	if key == "" {
+		return nil, fakeAPIError(http.StatusBadRequest, "The Key field is required.")
+	}
+	projectID, err := s.projectID(projectIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	secret := sdk.SecretResponse{
+		ID:             uuid.NewString(),
+		Key:            key,
+		Value:          value,
+		Note:           note,
+		OrganizationID: organizationID,
+		ProjectID:      projectID,
+		CreationDate:   now,
+		RevisionDate:   now,
+	}
+	s.client.store.secrets[secret.ID] = secret
+
+	return &secret, nil
+}
+
+func (s *fakeSecrets) List(organizationID string) (*sdk.SecretIdentifiersResponse, error) {
+	if err := s.client.lock(organizationID); err != nil {
+		return nil, err
+	}
+	defer s.client.store.mu.Unlock()
+
+	identifiers := []sdk.SecretIdentifierResponse{}
+	if s.client.hasAccess {
+		for _, id := range sortedKeys(s.client.store.secrets) {
+			secret := s.client.store.secrets[id]
+			identifiers = append(identifiers, sdk.SecretIdentifierResponse{
+				ID:             secret.ID,
+				Key:            secret.Key,
+				OrganizationID: secret.OrganizationID,
+			})
+		}
+	}
+
+	return &sdk.SecretIdentifiersResponse{Data: identifiers}, nil
+}
+
+func (s *fakeSecrets) Get(secretID string) (*sdk.SecretResponse, error) {
+	if err := s.client.lock(""); err != nil {
+		return nil, err
+	}
+	defer s.client.store.mu.Unlock()
+
+	secret, ok := s.client.store.secrets[secretID]
+	if !ok || !s.client.hasAccess {
+		return nil, fakeAPIError(http.StatusNotFound, "Resource not found.")
+	}
+
+	return &secret, nil
+}
+
+func (s *fakeSecrets) GetByIDS(secretIDs []string) (*sdk.SecretsResponse, error) {
+	if err := s.client.lock(""); err != nil {
+		return nil, err
+	}
+	defer s.client.store.mu.Unlock()
+
+	// Like the API, the request fails if any of the secrets does not exist.
+	secrets := []sdk.SecretResponse{}
+	for _, id := range secretIDs {
+		secret, ok := s.client.store.secrets[id]
+		if !ok || !s.client.hasAccess {
+			return nil, fakeAPIError(http.StatusNotFound, "Resource not found.")
+		}
+		secrets = append(secrets, secret)
+	}
+
+	return &sdk.SecretsResponse{Data: secrets}, nil
+}
+
+func (s *fakeSecrets) Update(secretID string, key, value, note string, organizationID string, projectIDs []string) (*sdk.SecretResponse, error) {
+	if err := s.client.lock(organizationID); err != nil {
+		return nil, err
+	}
+	defer s.client.store.mu.Unlock()
+
+	secret, ok := s.client.store.secrets[secretID]
+	if !ok || !s.client.hasAccess {
+		return nil, fakeAPIError(http.StatusNotFound, "Resource not found.")
+	}
+	if key == "" {
+		return nil, fakeAPIError(http.StatusBadRequest, "The Key field is required.")
+	}
+	projectID, err := s.projectID(projectIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	secret.Key = key
+	secret.Value = value
+	secret.Note = note
+	secret.ProjectID = projectID
+	secret.RevisionDate = time.Now().UTC()
+	s.client.store.secrets[secretID] = secret
+
+	return &secret, nil
+}
+
+func (s *fakeSecrets) Delete(secretIDs []string) (*sdk.SecretsDeleteResponse, error) {
+	if err := s.client.lock(""); err != nil {
+		return nil, err
+	}
+	defer s.client.store.mu.Unlock()
+
+	response := &sdk.SecretsDeleteResponse{Data: []sdk.SecretDeleteResponse{}}
+	for _, id := range secretIDs {
+		if _, ok := s.client.store.secrets[id]; !ok || !s.client.hasAccess {
+			message := "Secret not found."
+			response.Data = append(response.Data, sdk.SecretDeleteResponse{ID: id, Error: &message})
+			continue
+		}
+
+		delete(s.client.store.secrets, id)
+		response.Data = append(response.Data, sdk.SecretDeleteResponse{ID: id})
+	}
+
+	return response, nil
+}
+
+func (s *fakeSecrets) Sync(organizationID string, lastSyncedDate *time.Time) (*sdk.SecretsSyncResponse, error) {
+	if err := s.client.lock(organizationID); err != nil {
+		return nil, err
+	}
+	defer s.client.store.mu.Unlock()
+
+	if !s.client.hasAccess {
+		return &sdk.SecretsSyncResponse{HasChanges: false}, nil
+	}
+
+	// Like the API, all secrets are returned if any of them changed since the last sync.
+	hasChanges := lastSyncedDate == nil
+	secrets := make([]sdk.SecretResponse, 0, len(s.client.store.secrets))
+	for _, id := range sortedKeys(s.client.store.secrets) {
+		secret := s.client.store.secrets[id]
+		if lastSyncedDate != nil && secret.RevisionDate.After(*lastSyncedDate) {
+			hasChanges = true
+		}
+		secrets = append(secrets, secret)
+	}
+
+	if !hasChanges {
+		return &sdk.SecretsSyncResponse{HasChanges: false}, nil
+	}
+	sort.SliceStable(secrets, func(i, j int) bool {
+		return secrets[i].CreationDate.Before(secrets[j].CreationDate)
+	})
+	return &sdk.SecretsSyncResponse{HasChanges: true, Secrets: secrets}, nil
+}
+
+// fakeGenerators generates passwords locally with the generator of the provider.
+type fakeGenerators struct{}
+
+func (g *fakeGenerators) GeneratePassword(request sdk.PasswordGeneratorRequest) (*string, error) {
+	options := passwordGeneratorOptions{
+		AvoidAmbiguous:    request.AvoidAmbiguous,
+		Length:            request.Length,
+		Lowercase:         request.Lowercase,
+		MinLowercase:      defaultMinimum,
+		Uppercase:         request.Uppercase,
+		MinUppercase:      defaultMinimum,
+		Numbers:           request.Numbers,
+		MinNumber:         defaultMinimum,
+		Special:           request.Special,
+		MinSpecial:        defaultMinimum,
+		SpecialCharacters: defaultSpecialCharacters,
+	}
+	if request.MinLowercase != nil {
+		options.MinLowercase = *request.MinLowercase
+	}
+	if request.MinUppercase != nil {
+		options.MinUppercase = *request.MinUppercase
+	}
+	if request.MinNumber != nil {
+		options.MinNumber = *request.MinNumber
+	}
+	if request.MinSpecial != nil {
+		options.MinSpecial = *request.MinSpecial
+	}
+
+	password, err := generatePassword(options)
+	if err != nil {
+		return nil, fmt.Errorf("API error: %w", err)
+	}
+	return &password, nil
+}
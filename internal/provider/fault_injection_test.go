@@ -0,0 +1,458 @@
+package provider
+
+import (
+	"errors"
+	"github.com/bitwarden/sdk-go"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// errFaultTimeout is the error of a request whose response did not arrive in time.
+var errFaultTimeout = errors.New("API error: error sending request: operation timed out")
+
+// fault describes the behaviour injected into a single call of an operation of the Bitwarden client.
+type fault struct {
+	// latency delays the call.
+	latency time.Duration
+	// block blocks the call until the channel is closed, like a request to a server which does not respond.
+	block <-chan struct{}
+	// err is returned by the call. The operation is not executed, unless commit is set.
+	err error
+	// commit executes the operation before err is returned, like a request which succeeds on the server, but whose
+	// response is lost.
+	commit bool
+	// hang delays the response after the operation was executed, like a server which completes a request, but
+	// responds slowly.
+	hang time.Duration
+}
+
+// faultStatus returns the error of a response with the given status code.
+func faultStatus(statusCode int) error {
+	switch statusCode {
+	case http.StatusTooManyRequests:
+		return fakeAPIError(statusCode, "Slow down! Too many requests. Try again in 1m.")
+	default:
+		return fakeAPIError(statusCode, `{"message":"An error has occurred.","object":"error"}`)
+	}
+}
+
+// faultInjectingClient wraps a Bitwarden client and injects faults into the calls of its secrets and projects
+// operations. Operations are named after the SDK methods, e.g. "Secrets.Create" or "Projects.Delete".
+type faultInjectingClient struct {
+	sdk.BitwardenClientInterface
+
+	mu     sync.Mutex
+	faults map[string][]fault
+	calls  map[string]int
+}
+
+func newFaultInjectingClient(bitwardenClient sdk.BitwardenClientInterface) *faultInjectingClient {
+	return &faultInjectingClient{
+		BitwardenClientInterface: bitwardenClient,
+		faults:                   map[string][]fault{},
+		calls:                    map[string]int{},
+	}
+}
+
+// inject queues faults for the next calls of an operation, one fault per call. Later calls are not affected.
+func (c *faultInjectingClient) inject(operation string, faults ...fault) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.faults[operation] = append(c.faults[operation], faults...)
+}
+
+// callCount returns how often an operation was called.
+func (c *faultInjectingClient) callCount(operation string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.calls[operation]
+}
+
+func (c *faultInjectingClient) Secrets() sdk.SecretsInterface {
+	return &faultInjectingSecrets{client: c, secrets: c.BitwardenClientInterface.Secrets()}
+}
+
+func (c *faultInjectingClient) Projects() sdk.ProjectsInterface {
+	return &faultInjectingProjects{client: c, projects: c.BitwardenClientInterface.Projects()}
+}
+
+// withFault executes a call of an operation with the next fault queued for it.
+func withFault[T any](c *faultInjectingClient, operation string, call func() (T, error)) (T, error) {
+	c.mu.Lock()
+	c.calls[operation]++
+	var next fault
+	if queued := c.faults[operation]; len(queued) > 0 {
+		next = queued[0]
+		c.faults[operation] = queued[1:]
+	}
+	c.mu.Unlock()
+
+	time.Sleep(next.latency)
+	if next.block != nil {
+		<-next.block
+	}
+
+	if next.err == nil {
+		value, err := call()
+		time.Sleep(next.hang)
+		return value, err
+	}
+
+	var zero T
+	if next.commit {
+		if _, err := call(); err != nil {
+			return zero, err
+		}
+	}
+	time.Sleep(next.hang)
+	return zero, next.err
+}
+
+type faultInjectingSecrets struct {
+	client  *faultInjectingClient
+	secrets sdk.SecretsInterface
+}
+
+func (s *faultInjectingSecrets) Create(key, value, note string, organizationID string, projectIDs []string) (*sdk.SecretResponse, error) {
+	return withFault(s.client, "Secrets.Create", func() (*sdk.SecretResponse, error) {
+		return s.secrets.Create(key, value, note, organizationID, projectIDs)
+	})
+}
+
+func (s *faultInjectingSecrets) List(organizationID string) (*sdk.SecretIdentifiersResponse, error) {
+	return withFault(s.client, "Secrets.List", func() (*sdk.SecretIdentifiersResponse, error) {
+		return s.secrets.List(organizationID)
+	})
+}
+
+func (s *faultInjectingSecrets) Get(secretID string) (*sdk.SecretResponse, error) {
+	return withFault(s.client, "Secrets.Get", func() (*sdk.SecretResponse, error) {
+		return s.secrets.Get(secretID)
+	})
+}
+
+func (s *faultInjectingSecrets) GetByIDS(secretIDs []string) (*sdk.SecretsResponse, error) {
+	return withFault(s.client, "Secrets.GetByIDS", func() (*sdk.SecretsResponse, error) {
+		return s.secrets.GetByIDS(secretIDs)
+	})
+}
+
+func (s *faultInjectingSecrets) Update(secretID string, key, value, note string, organizationID string, projectIDs []string) (*sdk.SecretResponse, error) {
+	return withFault(s.client, "Secrets.Update", func() (*sdk.SecretResponse, error) {
+		return s.secrets.Update(secretID, key, value, note, organizationID, projectIDs)
+	})
+}
+
+func (s *faultInjectingSecrets) Delete(secretIDs []string) (*sdk.SecretsDeleteResponse, error) {
+	return withFault(s.client, "Secrets.Delete", func() (*sdk.SecretsDeleteResponse, error) {
+		return s.secrets.Delete(secretIDs)
+	})
+}
+
+func (s *faultInjectingSecrets) Sync(organizationID string, lastSyncedDate *time.Time) (*sdk.SecretsSyncResponse, error) {
+	return withFault(s.client, "Secrets.Sync", func() (*sdk.SecretsSyncResponse, error) {
+		return s.secrets.Sync(organizationID, lastSyncedDate)
+	})
+}
+
+type faultInjectingProjects struct {
+	client   *faultInjectingClient
+	projects sdk.ProjectsInterface
+}
+
+func (p *faultInjectingProjects) Create(organizationID string, name string) (*sdk.ProjectResponse, error) {
+	return withFault(p.client, "Projects.Create", func() (*sdk.ProjectResponse, error) {
+		return p.projects.Create(organizationID, name)
+	})
+}
+
+func (p *faultInjectingProjects) List(organizationID string) (*sdk.ProjectsResponse, error) {
+	return withFault(p.client, "Projects.List", func() (*sdk.ProjectsResponse, error) {
+		return p.projects.List(organizationID)
+	})
+}
+
+func (p *faultInjectingProjects) Get(projectID string) (*sdk.ProjectResponse, error) {
+	return withFault(p.client, "Projects.Get", func() (*sdk.ProjectResponse, error) {
+		return p.projects.Get(projectID)
+	})
+}
+
+func (p *faultInjectingProjects) Update(projectID string, organizationID string, name string) (*sdk.ProjectResponse, error) {
+	return withFault(p.client, "Projects.Update", func() (*sdk.ProjectResponse, error) {
+		return p.projects.Update(projectID, organizationID, name)
+	})
+}
+
+func (p *faultInjectingProjects) Delete(projectIDs []string) (*sdk.ProjectsDeleteResponse, error) {
+	return withFault(p.client, "Projects.Delete", func() (*sdk.ProjectsDeleteResponse, error) {
+		return p.projects.Delete(projectIDs)
+	})
+}
+
+// withoutCreateRetryDelay retries failed create requests immediately for the duration of the test.
+func withoutCreateRetryDelay(t *testing.T) {
+	delay := createRetryDelay
+	createRetryDelay = 0
+	t.Cleanup(func() {
+		createRetryDelay = delay
+	})
+}
+
+// newFaultInjectionHarness returns a harness for the secret resource which uses a fault injecting client of an
+// in-memory organization.
+func newFaultInjectionHarness(t *testing.T) (*resourceHarness, *faultInjectingClient, *fakeBitwardenStore) {
+	store := newFakeBitwardenStore(fakeOrganizationID)
+	client := newFaultInjectingClient(store.newClient())
+	return newResourceHarness(t, "bitwarden-sm_secret", client), client, store
+}
+
+// storedValues returns the values of all secrets of the store by id.
+func storedValues(store *fakeBitwardenStore) map[string]string {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	values := map[string]string{}
+	for id, secret := range store.secrets {
+		values[id] = secret.Value
+	}
+	return values
+}
+
+func secretConfig(value string) map[string]tftypes.Value {
+	return map[string]tftypes.Value{
+		"key":   tftypes.NewValue(tftypes.String, "API_KEY"),
+		"value": tftypes.NewValue(tftypes.String, value),
+	}
+}
+
+func expectDiagnosticError(t *testing.T, diagnostics []*tfprotov6.Diagnostic, expected string) {
+	t.Helper()
+
+	if expected == "" {
+		if diagnosticsHaveError(diagnostics) {
+			t.Fatalf("unexpected error: %s", diagnosticsString(diagnostics))
+		}
+		return
+	}
+	if !diagnosticsHaveError(diagnostics) || !strings.Contains(diagnosticsString(diagnostics), expected) {
+		t.Fatalf("expected an error containing %q, got: %s", expected, diagnosticsString(diagnostics))
+	}
+}
+
+func TestSecretResourceCreateFaults(t *testing.T) {
+	withoutCreateRetryDelay(t)
+
+	serverError := fault{err: faultStatus(http.StatusInternalServerError)}
+	rateLimited := fault{err: faultStatus(http.StatusTooManyRequests)}
+	tests := map[string]struct {
+		faults   []fault
+		expected string
+		calls    int
+		tracked  bool
+	}{
+		"server error is retried": {
+			faults:  []fault{serverError},
+			calls:   2,
+			tracked: true,
+		},
+		"server error on every attempt": {
+			faults:   []fault{serverError, serverError, serverError},
+			expected: "Unable to Create Secret",
+			calls:    maxCreateAttempts,
+		},
+		"rate limited is retried": {
+			faults:  []fault{rateLimited},
+			calls:   2,
+			tracked: true,
+		},
+		"rate limited on every attempt": {
+			faults:   []fault{rateLimited, rateLimited, rateLimited},
+			expected: "Too Many Requests",
+			calls:    maxCreateAttempts,
+		},
+		// The secret created by the lost request is recovered instead of being created again.
+		"response lost after commit": {
+			faults:  []fault{{err: errFaultTimeout, commit: true}},
+			calls:   1,
+			tracked: true,
+		},
+		"latency": {
+			faults:  []fault{{latency: 20 * time.Millisecond}},
+			calls:   1,
+			tracked: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			harness, client, store := newFaultInjectionHarness(t)
+			client.inject("Secrets.Create", test.faults...)
+
+			started := time.Now()
+			state, diagnostics := harness.apply(secretConfig("value"), harness.nullState())
+			expectDiagnosticError(t, diagnostics, test.expected)
+			if elapsed := time.Since(started); elapsed < test.faults[0].latency {
+				t.Fatalf("expected the create to take at least %s, took %s", test.faults[0].latency, elapsed)
+			}
+
+			if calls := client.callCount("Secrets.Create"); calls != test.calls {
+				t.Fatalf("expected %d create requests, got %d", test.calls, calls)
+			}
+			if tracked := !state.value.IsNull(); tracked != test.tracked {
+				t.Fatalf("expected the secret to be tracked in the state: %t, got %t", test.tracked, tracked)
+			}
+
+			// Failed creates are retried by the next apply, no scenario leaves a duplicate.
+			if !test.tracked {
+				if stored := len(storedValues(store)); stored != 0 {
+					t.Fatalf("expected no stored secrets, got %d", stored)
+				}
+				state, diagnostics = harness.apply(secretConfig("value"), state)
+				expectDiagnosticError(t, diagnostics, "")
+			}
+			if stored := len(storedValues(store)); stored != 1 {
+				t.Fatalf("expected a single stored secret, got %d", stored)
+			}
+			if storedValues(store)[harness.stringAttribute(state, "id")] != "value" {
+				t.Fatal("expected the state to track the stored secret")
+			}
+		})
+	}
+}
+
+func TestSecretResourceReadFaults(t *testing.T) {
+	harness, client, _ := newFaultInjectionHarness(t)
+	state, diagnostics := harness.apply(secretConfig("value"), harness.nullState())
+	expectDiagnosticError(t, diagnostics, "")
+
+	client.inject("Secrets.Get", fault{err: faultStatus(http.StatusInternalServerError)})
+	refreshed, diagnostics := harness.read(state)
+	expectDiagnosticError(t, diagnostics, "Unable to Read Secret with id: "+harness.stringAttribute(state, "id"))
+	if !refreshed.value.Equal(state.value) {
+		t.Fatal("expected a failed refresh to keep the state")
+	}
+
+	client.inject("Secrets.Get", fault{latency: 20 * time.Millisecond})
+	refreshed, diagnostics = harness.read(state)
+	expectDiagnosticError(t, diagnostics, "")
+	if harness.stringAttribute(refreshed, "value") != "value" {
+		t.Fatal("expected a delayed refresh to keep the value")
+	}
+}
+
+func TestSecretResourceUpdateFaults(t *testing.T) {
+	tests := map[string]struct {
+		fault       fault
+		expected    string
+		storedValue string
+	}{
+		"server error": {
+			fault:       fault{err: faultStatus(http.StatusInternalServerError)},
+			expected:    "Unable to Update Secret",
+			storedValue: "old",
+		},
+		"response lost after commit": {
+			fault:       fault{err: errFaultTimeout, commit: true},
+			expected:    "operation timed out",
+			storedValue: "new",
+		},
+		"latency": {
+			fault:       fault{latency: 20 * time.Millisecond},
+			storedValue: "new",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			harness, client, store := newFaultInjectionHarness(t)
+			state, diagnostics := harness.apply(secretConfig("old"), harness.nullState())
+			expectDiagnosticError(t, diagnostics, "")
+			id := harness.stringAttribute(state, "id")
+
+			client.inject("Secrets.Update", test.fault)
+			state, diagnostics = harness.apply(secretConfig("new"), state)
+			expectDiagnosticError(t, diagnostics, test.expected)
+
+			// A failed update keeps the prior state, the next refresh picks up a committed change.
+			expectedState := "new"
+			if test.expected != "" {
+				expectedState = "old"
+			}
+			if value := harness.stringAttribute(state, "value"); value != expectedState {
+				t.Fatalf("expected the value %q in the state, got %q", expectedState, value)
+			}
+			if value := storedValues(store)[id]; value != test.storedValue {
+				t.Fatalf("expected the stored value %q, got %q", test.storedValue, value)
+			}
+
+			state, diagnostics = harness.read(state)
+			expectDiagnosticError(t, diagnostics, "")
+			if value := harness.stringAttribute(state, "value"); value != test.storedValue {
+				t.Fatalf("expected the refreshed value %q, got %q", test.storedValue, value)
+			}
+
+			state, diagnostics = harness.apply(secretConfig("new"), state)
+			expectDiagnosticError(t, diagnostics, "")
+			if harness.stringAttribute(state, "id") != id || storedValues(store)[id] != "new" {
+				t.Fatal("expected the retry to update the same secret")
+			}
+		})
+	}
+}
+
+func TestSecretResourceDeleteFaults(t *testing.T) {
+	tests := map[string]struct {
+		fault    fault
+		expected string
+		stored   int
+		// retryExpected is the error of the retried destroy.
+		retryExpected string
+	}{
+		"server error": {
+			fault:    fault{err: faultStatus(http.StatusInternalServerError)},
+			expected: "Unable to Delete Secret",
+			stored:   1,
+		},
+		// The secret is deleted, but still tracked in the state, the retried destroy treats it as deleted.
+		"response lost after commit": {
+			fault:    fault{err: errFaultTimeout, commit: true},
+			expected: "operation timed out",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			harness, client, store := newFaultInjectionHarness(t)
+			state, diagnostics := harness.apply(secretConfig("value"), harness.nullState())
+			expectDiagnosticError(t, diagnostics, "")
+
+			client.inject("Secrets.Delete", test.fault)
+			state, diagnostics = harness.destroy(state)
+			expectDiagnosticError(t, diagnostics, test.expected)
+
+			if state.value.IsNull() {
+				t.Fatal("expected a failed destroy to keep the state")
+			}
+			if stored := len(storedValues(store)); stored != test.stored {
+				t.Fatalf("expected %d stored secrets, got %d", test.stored, stored)
+			}
+
+			state, diagnostics = harness.destroy(state)
+			expectDiagnosticError(t, diagnostics, test.retryExpected)
+			if test.retryExpected == "" && !state.value.IsNull() {
+				t.Fatal("expected the retried destroy to remove the state")
+			}
+			if stored := len(storedValues(store)); stored != 0 {
+				t.Fatalf("expected no stored secrets, got %d", stored)
+			}
+		})
+	}
+}
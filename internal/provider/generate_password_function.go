@@ -0,0 +1,171 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"math/big"
+	"strings"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ function.Function = &generatePasswordFunction{}
+
+// NewGeneratePasswordFunction is a helper function to simplify the provider implementation.
+func NewGeneratePasswordFunction() function.Function {
+	return &generatePasswordFunction{}
+}
+
+// generatePasswordFunction defines the function implementation. It generates the value locally, so it neither
+// needs a configured provider nor network access.
+type generatePasswordFunction struct{}
+
+// passwordOptionSetters assigns the options of the function, which are named like the generator attributes of
+// the secret resource.
+var passwordOptionSetters = map[string]func(options *passwordGeneratorOptions, value attr.Value) error{
+	"avoid_ambiguous":    boolOption(func(o *passwordGeneratorOptions, v bool) { o.AvoidAmbiguous = v }),
+	"length":             int64Option(func(o *passwordGeneratorOptions, v int64) { o.Length = v }),
+	"lowercase":          boolOption(func(o *passwordGeneratorOptions, v bool) { o.Lowercase = v }),
+	"min_lowercase":      int64Option(func(o *passwordGeneratorOptions, v int64) { o.MinLowercase = v }),
+	"uppercase":          boolOption(func(o *passwordGeneratorOptions, v bool) { o.Uppercase = v }),
+	"min_uppercase":      int64Option(func(o *passwordGeneratorOptions, v int64) { o.MinUppercase = v }),
+	"numbers":            boolOption(func(o *passwordGeneratorOptions, v bool) { o.Numbers = v }),
+	"min_number":         int64Option(func(o *passwordGeneratorOptions, v int64) { o.MinNumber = v }),
+	"special":            boolOption(func(o *passwordGeneratorOptions, v bool) { o.Special = v }),
+	"min_special":        int64Option(func(o *passwordGeneratorOptions, v int64) { o.MinSpecial = v }),
+	"special_characters": stringOption(func(o *passwordGeneratorOptions, v string) { o.SpecialCharacters = v }),
+	"exclude_characters": stringOption(func(o *passwordGeneratorOptions, v string) { o.ExcludeCharacters = v }),
+}
+
+func (f *generatePasswordFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "generate_password"
+}
+
+func (f *generatePasswordFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:             "Generates a random password",
+		Description:         "Generates a random password locally with the same options and defaults as the generator attributes of the secret resource: avoid_ambiguous, length, lowercase, min_lowercase, uppercase, min_uppercase, numbers, min_number, special, min_special, special_characters and exclude_characters. The password is generated on every evaluation, so it changes with every plan unless it is stored.",
+		MarkdownDescription: "Generates a random password locally with the same options and defaults as the generator attributes of the `secret` resource: `avoid_ambiguous`, `length`, `lowercase`, `min_lowercase`, `uppercase`, `min_uppercase`, `numbers`, `min_number`, `special`, `min_special`, `special_characters` and `exclude_characters`. The password is generated on every evaluation, so it changes with every plan unless it is stored.",
+		Parameters: []function.Parameter{
+			function.DynamicParameter{
+				Name:                "options",
+				Description:         "Object with the generator options, unset options use the defaults of the secret resource. Use {} for the defaults.",
+				MarkdownDescription: "Object with the generator options, unset options use the defaults of the `secret` resource. Use `{}` for the defaults.",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *generatePasswordFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var argument types.Dynamic
+	resp.Error = req.Arguments.Get(ctx, &argument)
+	if resp.Error != nil {
+		return
+	}
+
+	options, err := parsePasswordGeneratorOptions(argument)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+
+	password, err := generatePassword(options)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, fmt.Sprintf("The secret generator cannot create a value for the given options: %s", err))
+		return
+	}
+
+	resp.Error = resp.Result.Set(ctx, password)
+}
+
+// parsePasswordGeneratorOptions reads the generator options from an object or map and applies the defaults of
+// the secret resource to all unset options.
+func parsePasswordGeneratorOptions(argument types.Dynamic) (passwordGeneratorOptions, error) {
+	options := defaultPasswordGeneratorOptions()
+
+	var attributes map[string]attr.Value
+	switch value := argument.UnderlyingValue().(type) {
+	case types.Object:
+		attributes = value.Attributes()
+	case types.Map:
+		attributes = value.Elements()
+	default:
+		return options, fmt.Errorf("options must be an object, got: %s", argument.UnderlyingValue().Type(context.Background()))
+	}
+
+	for _, name := range sortedKeys(attributes) {
+		value := attributes[name]
+		setter, ok := passwordOptionSetters[name]
+		if !ok {
+			return options, fmt.Errorf("unsupported option %q, supported options are: %s", name, strings.Join(sortedKeys(passwordOptionSetters), ", "))
+		}
+		if value.IsNull() {
+			continue
+		}
+		if value.IsUnknown() {
+			return options, fmt.Errorf("option %q must be known", name)
+		}
+		if err := setter(&options, value); err != nil {
+			return options, fmt.Errorf("option %q %s", name, err)
+		}
+	}
+
+	// The secret resource requires all minimums to be at least 1, even for disabled character classes.
+	minimums := []struct {
+		name  string
+		value int64
+	}{
+		{"min_lowercase", options.MinLowercase},
+		{"min_uppercase", options.MinUppercase},
+		{"min_number", options.MinNumber},
+		{"min_special", options.MinSpecial},
+	}
+	for _, minimum := range minimums {
+		if minimum.value < 1 {
+			return options, fmt.Errorf("option %q must be at least 1, got: %d", minimum.name, minimum.value)
+		}
+	}
+
+	return options, nil
+}
+
+func boolOption(set func(options *passwordGeneratorOptions, value bool)) func(*passwordGeneratorOptions, attr.Value) error {
+	return func(options *passwordGeneratorOptions, value attr.Value) error {
+		boolValue, ok := value.(types.Bool)
+		if !ok {
+			return fmt.Errorf("must be a bool, got: %s", value.Type(context.Background()))
+		}
+		set(options, boolValue.ValueBool())
+		return nil
+	}
+}
+
+func int64Option(set func(options *passwordGeneratorOptions, value int64)) func(*passwordGeneratorOptions, attr.Value) error {
+	return func(options *passwordGeneratorOptions, value attr.Value) error {
+		numberValue, ok := value.(types.Number)
+		if !ok {
+			return fmt.Errorf("must be a number, got: %s", value.Type(context.Background()))
+		}
+		number := numberValue.ValueBigFloat()
+		integer, accuracy := number.Int64()
+		if !number.IsInt() || accuracy != big.Exact {
+			return fmt.Errorf("must be a whole number, got: %s", number.String())
+		}
+		set(options, integer)
+		return nil
+	}
+}
+
+func stringOption(set func(options *passwordGeneratorOptions, value string)) func(*passwordGeneratorOptions, attr.Value) error {
+	return func(options *passwordGeneratorOptions, value attr.Value) error {
+		stringValue, ok := value.(types.String)
+		if !ok {
+			return fmt.Errorf("must be a string, got: %s", value.Type(context.Background()))
+		}
+		set(options, stringValue.ValueString())
+		return nil
+	}
+}
@@ -0,0 +1,255 @@
+package provider
+
+import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"math/big"
+	"strings"
+	"testing"
+	"testing/quick"
+	"unicode/utf8"
+)
+
+func runGeneratePasswordFunction(t *testing.T, options attr.Value) (string, *function.FuncError) {
+	t.Helper()
+
+	req := function.RunRequest{
+		Arguments: function.NewArgumentsData([]attr.Value{types.DynamicValue(options)}),
+	}
+	resp := function.RunResponse{
+		Result: function.NewResultData(types.StringUnknown()),
+	}
+
+	NewGeneratePasswordFunction().Run(context.Background(), req, &resp)
+	if resp.Error != nil {
+		return "", resp.Error
+	}
+	return resp.Result.Value().(types.String).ValueString(), nil
+}
+
+func passwordOptionsObject(attributes map[string]attr.Value) types.Object {
+	attributeTypes := map[string]attr.Type{}
+	for name, value := range attributes {
+		attributeTypes[name] = value.Type(context.Background())
+	}
+	return types.ObjectValueMust(attributeTypes, attributes)
+}
+
+func TestGeneratePasswordFunctionDefaults(t *testing.T) {
+	password, err := runGeneratePasswordFunction(t, passwordOptionsObject(map[string]attr.Value{}))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(password) != defaultLength {
+		t.Fatalf("expected the default length %d, got %d", defaultLength, len(password))
+	}
+	for _, characters := range []string{lowercaseCharacters, uppercaseCharacters, numberCharacters} {
+		if !strings.ContainsAny(password, characters) {
+			t.Fatalf("expected the password to contain one of %q, got %q", characters, password)
+		}
+	}
+	if strings.ContainsAny(password, defaultSpecialCharacters) {
+		t.Fatalf("expected no special characters by default, got %q", password)
+	}
+}
+
+func TestGeneratePasswordFunctionOptions(t *testing.T) {
+	password, err := runGeneratePasswordFunction(t, passwordOptionsObject(map[string]attr.Value{
+		"length":             types.NumberValue(big.NewFloat(20)),
+		"lowercase":          types.BoolValue(false),
+		"uppercase":          types.BoolValue(false),
+		"special":            types.BoolValue(true),
+		"min_special":        types.NumberValue(big.NewFloat(10)),
+		"special_characters": types.StringValue("-_"),
+		"exclude_characters": types.StringValue("9"),
+		"avoid_ambiguous":    types.BoolValue(true),
+		"min_number":         types.NumberNull(),
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(password) != 20 {
+		t.Fatalf("expected a length of 20, got %d", len(password))
+	}
+	if strings.Trim(password, "2345678-_") != "" {
+		t.Fatalf("unexpected characters in %q", password)
+	}
+	if strings.Count(password, "-")+strings.Count(password, "_") < 10 {
+		t.Fatalf("expected at least 10 special characters in %q", password)
+	}
+}
+
+func TestGeneratePasswordFunctionAcceptsMap(t *testing.T) {
+	options := types.MapValueMust(types.NumberType, map[string]attr.Value{
+		"length": types.NumberValue(big.NewFloat(12)),
+	})
+
+	password, err := runGeneratePasswordFunction(t, options)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(password) != 12 {
+		t.Fatalf("expected a length of 12, got %d", len(password))
+	}
+}
+
+func TestGeneratePasswordFunctionErrors(t *testing.T) {
+	tests := map[string]struct {
+		options  attr.Value
+		expected string
+	}{
+		"not an object": {
+			options:  types.StringValue("length=12"),
+			expected: "options must be an object",
+		},
+		"unsupported option": {
+			options:  passwordOptionsObject(map[string]attr.Value{"size": types.NumberValue(big.NewFloat(12))}),
+			expected: `unsupported option "size"`,
+		},
+		"wrong type": {
+			options:  passwordOptionsObject(map[string]attr.Value{"special": types.StringValue("yes")}),
+			expected: `option "special" must be a bool`,
+		},
+		"fractional number": {
+			options:  passwordOptionsObject(map[string]attr.Value{"length": types.NumberValue(big.NewFloat(12.5))}),
+			expected: `option "length" must be a whole number`,
+		},
+		"minimum below one": {
+			options:  passwordOptionsObject(map[string]attr.Value{"min_number": types.NumberValue(big.NewFloat(0))}),
+			expected: `option "min_number" must be at least 1`,
+		},
+		"length below minimums": {
+			options:  passwordOptionsObject(map[string]attr.Value{"length": types.NumberValue(big.NewFloat(2))}),
+			expected: "length 2 is smaller than the sum of all minimums",
+		},
+		"no character class": {
+			options: passwordOptionsObject(map[string]attr.Value{
+				"lowercase": types.BoolValue(false),
+				"uppercase": types.BoolValue(false),
+				"numbers":   types.BoolValue(false),
+			}),
+			expected: "at least one of lowercase, uppercase, numbers or special must be enabled",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := runGeneratePasswordFunction(t, test.options)
+			if err == nil || !strings.Contains(err.Error(), test.expected) {
+				t.Fatalf("expected an error containing %q, got: %v", test.expected, err)
+			}
+			if err.FunctionArgument == nil || *err.FunctionArgument != 0 {
+				t.Fatalf("expected the error to refer to the options argument, got: %v", err.FunctionArgument)
+			}
+		})
+	}
+}
+
+// randomPasswordOptions is filled with random values by testing/quick and mapped to generator options.
+type randomPasswordOptions struct {
+	AvoidAmbiguous      bool
+	Lowercase           bool
+	Uppercase           bool
+	Numbers             bool
+	Special             bool
+	Length              uint8
+	MinLowercase        uint8
+	MinUppercase        uint8
+	MinNumber           uint8
+	MinSpecial          uint8
+	SpecialCharacterSet uint8
+	ExcludeCharacterSet uint8
+}
+
+func (r randomPasswordOptions) object() (types.Object, passwordGeneratorOptions) {
+	specialCharacterSets := []string{defaultSpecialCharacters, "-_", "!", "~`'\"", "äöü"}
+	excludeCharacterSets := []string{"", "aeiou", "0123456789", "ABCDEFGHIJKLMNOPQRSTUVWXYZ", "!-~"}
+
+	options := passwordGeneratorOptions{
+		AvoidAmbiguous:    r.AvoidAmbiguous,
+		Length:            int64(r.Length % 80),
+		Lowercase:         r.Lowercase,
+		MinLowercase:      1 + int64(r.MinLowercase%10),
+		Uppercase:         r.Uppercase,
+		MinUppercase:      1 + int64(r.MinUppercase%10),
+		Numbers:           r.Numbers,
+		MinNumber:         1 + int64(r.MinNumber%10),
+		Special:           r.Special,
+		MinSpecial:        1 + int64(r.MinSpecial%10),
+		SpecialCharacters: specialCharacterSets[int(r.SpecialCharacterSet)%len(specialCharacterSets)],
+		ExcludeCharacters: excludeCharacterSets[int(r.ExcludeCharacterSet)%len(excludeCharacterSets)],
+	}
+
+	return passwordOptionsObject(map[string]attr.Value{
+		"avoid_ambiguous":    types.BoolValue(options.AvoidAmbiguous),
+		"length":             types.NumberValue(big.NewFloat(float64(options.Length))),
+		"lowercase":          types.BoolValue(options.Lowercase),
+		"min_lowercase":      types.NumberValue(big.NewFloat(float64(options.MinLowercase))),
+		"uppercase":          types.BoolValue(options.Uppercase),
+		"min_uppercase":      types.NumberValue(big.NewFloat(float64(options.MinUppercase))),
+		"numbers":            types.BoolValue(options.Numbers),
+		"min_number":         types.NumberValue(big.NewFloat(float64(options.MinNumber))),
+		"special":            types.BoolValue(options.Special),
+		"min_special":        types.NumberValue(big.NewFloat(float64(options.MinSpecial))),
+		"special_characters": types.StringValue(options.SpecialCharacters),
+		"exclude_characters": types.StringValue(options.ExcludeCharacters),
+	}), options
+}
+
+// The generated password must satisfy every constraint of the options, and unsatisfiable options must be rejected.
+func TestGeneratePasswordFunctionProperties(t *testing.T) {
+	property := func(random randomPasswordOptions) bool {
+		object, options := random.object()
+		password, funcErr := runGeneratePasswordFunction(t, object)
+
+		if err := options.validate(); err != nil {
+			if funcErr == nil {
+				t.Logf("expected an error for %+v", options)
+				return false
+			}
+			return true
+		}
+		if funcErr != nil {
+			t.Logf("unexpected error for %+v: %s", options, funcErr)
+			return false
+		}
+
+		if int64(utf8.RuneCountInString(password)) != options.Length {
+			t.Logf("expected length %d for %+v, got %q", options.Length, options, password)
+			return false
+		}
+
+		allowed := ""
+		for _, class := range options.characterClasses() {
+			allowed += class.characters
+			count := int64(0)
+			for _, char := range password {
+				if strings.ContainsRune(class.characters, char) {
+					count++
+				}
+			}
+			if count < class.minimum {
+				t.Logf("expected at least %d %s characters for %+v, got %q", class.minimum, class.name, options, password)
+				return false
+			}
+		}
+
+		for _, char := range password {
+			if !strings.ContainsRune(allowed, char) ||
+				strings.ContainsRune(options.ExcludeCharacters, char) ||
+				(options.AvoidAmbiguous && strings.ContainsRune(ambiguousCharacters, char)) {
+				t.Logf("unexpected character %q for %+v in %q", char, options, password)
+				return false
+			}
+		}
+		return true
+	}
+
+	if err := quick.Check(property, &quick.Config{MaxCount: 500}); err != nil {
+		t.Fatal(err)
+	}
+}
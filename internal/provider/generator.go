@@ -0,0 +1,185 @@
+package provider
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+const (
+	lowercaseCharacters      = "abcdefghijklmnopqrstuvwxyz"
+	uppercaseCharacters      = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	numberCharacters         = "0123456789"
+	defaultSpecialCharacters = "!@#$%^&*"
+	ambiguousCharacters      = "IOl01"
+
+	// sdkGeneratorMaxMinimum is the highest per class minimum the Bitwarden SDK generator accepts.
+	sdkGeneratorMaxMinimum = 9
+)
+
+// passwordGeneratorOptions mirrors the generator attributes of the secret resource.
+type passwordGeneratorOptions struct {
+	AvoidAmbiguous    bool
+	Length            int64
+	Lowercase         bool
+	MinLowercase      int64
+	Uppercase         bool
+	MinUppercase      int64
+	Numbers           bool
+	MinNumber         int64
+	Special           bool
+	MinSpecial        int64
+	SpecialCharacters string
+	ExcludeCharacters string
+}
+
+// defaultPasswordGeneratorOptions returns the options used by the secret resource if no generator attribute is set.
+func defaultPasswordGeneratorOptions() passwordGeneratorOptions {
+	return passwordGeneratorOptions{
+		AvoidAmbiguous:    defaultAvoidAmbiguous,
+		Length:            defaultLength,
+		Lowercase:         defaultLowercase,
+		MinLowercase:      defaultMinimum,
+		Uppercase:         defaultUppercase,
+		MinUppercase:      defaultMinimum,
+		Numbers:           defaultNumbers,
+		MinNumber:         defaultMinimum,
+		Special:           defaultSpecial,
+		MinSpecial:        defaultMinimum,
+		SpecialCharacters: defaultSpecialCharacters,
+		ExcludeCharacters: "",
+	}
+}
+
+// characterClass is a named set of characters the generator draws from together with its minimum occurrence.
+type characterClass struct {
+	name       string
+	characters string
+	minimum    int64
+}
+
+// supportedBySDK reports whether the Bitwarden SDK generator is able to honour the given options.
+// The SDK only knows the default special character set, has no notion of excluded characters and
+// caps every minimum at 9.
+func (o passwordGeneratorOptions) supportedBySDK() bool {
+	return o.SpecialCharacters == defaultSpecialCharacters &&
+		o.ExcludeCharacters == "" &&
+		o.MinLowercase <= sdkGeneratorMaxMinimum &&
+		o.MinUppercase <= sdkGeneratorMaxMinimum &&
+		o.MinNumber <= sdkGeneratorMaxMinimum &&
+		o.MinSpecial <= sdkGeneratorMaxMinimum
+}
+
+// characterClasses returns all enabled character classes with ambiguous and excluded characters removed.
+func (o passwordGeneratorOptions) characterClasses() []characterClass {
+	var classes []characterClass
+	if o.Lowercase {
+		classes = append(classes, characterClass{"lowercase", o.filter(lowercaseCharacters), o.MinLowercase})
+	}
+	if o.Uppercase {
+		classes = append(classes, characterClass{"uppercase", o.filter(uppercaseCharacters), o.MinUppercase})
+	}
+	if o.Numbers {
+		classes = append(classes, characterClass{"numbers", o.filter(numberCharacters), o.MinNumber})
+	}
+	if o.Special {
+		classes = append(classes, characterClass{"special", o.filter(o.SpecialCharacters), o.MinSpecial})
+	}
+	return classes
+}
+
+// filter removes excluded and, if requested, ambiguous characters as well as duplicates from characters.
+func (o passwordGeneratorOptions) filter(characters string) string {
+	var builder strings.Builder
+	for _, char := range characters {
+		if strings.ContainsRune(o.ExcludeCharacters, char) ||
+			(o.AvoidAmbiguous && strings.ContainsRune(ambiguousCharacters, char)) ||
+			strings.ContainsRune(builder.String(), char) {
+			continue
+		}
+		builder.WriteRune(char)
+	}
+	return builder.String()
+}
+
+// validate checks that the options can be satisfied by the local generator.
+func (o passwordGeneratorOptions) validate() error {
+	classes := o.characterClasses()
+	if len(classes) == 0 {
+		return fmt.Errorf("at least one of lowercase, uppercase, numbers or special must be enabled")
+	}
+
+	sumOfMinimums := int64(0)
+	for _, class := range classes {
+		if class.characters == "" {
+			return fmt.Errorf("the %s character class is enabled but all of its characters are excluded", class.name)
+		}
+		sumOfMinimums += class.minimum
+	}
+
+	if o.Length < sumOfMinimums {
+		return fmt.Errorf("length %d is smaller than the sum of all minimums of the enabled character classes: %d", o.Length, sumOfMinimums)
+	}
+
+	return nil
+}
+
+// generatePassword creates a random value satisfying the given options using crypto/rand.
+// It is used whenever the Bitwarden SDK generator cannot honour the requested constraints.
+func generatePassword(options passwordGeneratorOptions) (string, error) {
+	if err := options.validate(); err != nil {
+		return "", err
+	}
+
+	classes := options.characterClasses()
+	password := make([]rune, 0, options.Length)
+	allCharacters := ""
+
+	for _, class := range classes {
+		allCharacters += class.characters
+		for i := int64(0); i < class.minimum; i++ {
+			char, err := randomCharacter(class.characters)
+			if err != nil {
+				return "", err
+			}
+			password = append(password, char)
+		}
+	}
+
+	for int64(len(password)) < options.Length {
+		char, err := randomCharacter(allCharacters)
+		if err != nil {
+			return "", err
+		}
+		password = append(password, char)
+	}
+
+	// Shuffle the result so that the guaranteed minimums are not grouped at the beginning.
+	for i := len(password) - 1; i > 0; i-- {
+		j, err := randomInt(i + 1)
+		if err != nil {
+			return "", err
+		}
+		password[i], password[j] = password[j], password[i]
+	}
+
+	return string(password), nil
+}
+
+func randomCharacter(characters string) (rune, error) {
+	runes := []rune(characters)
+	index, err := randomInt(len(runes))
+	if err != nil {
+		return 0, err
+	}
+	return runes[index], nil
+}
+
+func randomInt(upperBound int) (int, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(int64(upperBound)))
+	if err != nil {
+		return 0, fmt.Errorf("unable to read random data: %w", err)
+	}
+	return int(n.Int64()), nil
+}
@@ -0,0 +1,156 @@
+package provider
+
+import (
+	"strings"
+	"testing"
+	"unicode"
+)
+
+func TestGeneratePasswordHonoursCustomSpecialAndExcludedCharacters(t *testing.T) {
+	options := defaultPasswordGeneratorOptions()
+	options.Length = 40
+	options.Special = true
+	options.MinSpecial = 12
+	options.MinNumber = 12
+	options.SpecialCharacters = "-_.~@#"
+	options.ExcludeCharacters = "@#"
+
+	for i := 0; i < 50; i++ {
+		password, err := generatePassword(options)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		if len(password) != 40 {
+			t.Fatalf("expected length 40, got %d", len(password))
+		}
+		if strings.ContainsAny(password, "@#!$%^&*") {
+			t.Fatalf("password contains excluded or non-configured special characters: %s", password)
+		}
+
+		specialCount, digitCount := 0, 0
+		for _, char := range password {
+			if strings.ContainsRune("-_.~", char) {
+				specialCount++
+			} else if unicode.IsDigit(char) {
+				digitCount++
+			}
+		}
+		if specialCount < 12 {
+			t.Fatalf("expected at least 12 special characters, got %d", specialCount)
+		}
+		if digitCount < 12 {
+			t.Fatalf("expected at least 12 numbers, got %d", digitCount)
+		}
+	}
+}
+
+func TestGeneratePasswordAvoidsAmbiguousCharacters(t *testing.T) {
+	options := defaultPasswordGeneratorOptions()
+	options.AvoidAmbiguous = true
+	options.Length = 256
+
+	password, err := generatePassword(options)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if strings.ContainsAny(password, ambiguousCharacters) {
+		t.Fatalf("password contains ambiguous characters: %s", password)
+	}
+}
+
+func TestPasswordGeneratorOptionsValidate(t *testing.T) {
+	testCases := map[string]struct {
+		modify      func(options *passwordGeneratorOptions)
+		expectError string
+	}{
+		"default": {
+			modify: func(options *passwordGeneratorOptions) {},
+		},
+		"no character class enabled": {
+			modify: func(options *passwordGeneratorOptions) {
+				options.Lowercase = false
+				options.Uppercase = false
+				options.Numbers = false
+			},
+			expectError: "at least one of lowercase, uppercase, numbers or special must be enabled",
+		},
+		"all numbers excluded": {
+			modify: func(options *passwordGeneratorOptions) {
+				options.ExcludeCharacters = numberCharacters
+			},
+			expectError: "the numbers character class is enabled but all of its characters are excluded",
+		},
+		"all numbers excluded but numbers disabled": {
+			modify: func(options *passwordGeneratorOptions) {
+				options.Numbers = false
+				options.ExcludeCharacters = numberCharacters
+			},
+		},
+		"special characters only ambiguous": {
+			modify: func(options *passwordGeneratorOptions) {
+				options.Special = true
+				options.AvoidAmbiguous = true
+				options.SpecialCharacters = "l1"
+			},
+			expectError: "the special character class is enabled but all of its characters are excluded",
+		},
+		"minimums exceed length": {
+			modify: func(options *passwordGeneratorOptions) {
+				options.Length = 20
+				options.MinLowercase = 10
+				options.MinUppercase = 11
+			},
+			expectError: "length 20 is smaller than the sum of all minimums of the enabled character classes: 22",
+		},
+		"minimums of disabled classes are ignored": {
+			modify: func(options *passwordGeneratorOptions) {
+				options.Length = 20
+				options.MinLowercase = 18
+				options.MinSpecial = 5
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			options := defaultPasswordGeneratorOptions()
+			testCase.modify(&options)
+
+			err := options.validate()
+			if testCase.expectError == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != testCase.expectError {
+				t.Fatalf("expected error %q, got: %v", testCase.expectError, err)
+			}
+		})
+	}
+}
+
+func TestPasswordGeneratorOptionsSupportedBySDK(t *testing.T) {
+	options := defaultPasswordGeneratorOptions()
+	if !options.supportedBySDK() {
+		t.Fatal("expected default options to be supported by the SDK generator")
+	}
+
+	options.MinNumber = 10
+	if options.supportedBySDK() {
+		t.Fatal("expected minimums above 9 to require the provider generator")
+	}
+
+	options = defaultPasswordGeneratorOptions()
+	options.ExcludeCharacters = "@"
+	if options.supportedBySDK() {
+		t.Fatal("expected excluded characters to require the provider generator")
+	}
+
+	options = defaultPasswordGeneratorOptions()
+	options.SpecialCharacters = "-_"
+	if options.supportedBySDK() {
+		t.Fatal("expected custom special characters to require the provider generator")
+	}
+}
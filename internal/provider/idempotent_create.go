@@ -0,0 +1,210 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/bitwarden/sdk-go"
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"strings"
+	"time"
+)
+
+const (
+	// maxCreateAttempts is the number of create requests sent for a secret before an ambiguous failure is reported.
+	maxCreateAttempts = 3
+	// createAttemptMarkerPrefix starts the line a create request appends to the note of the secret, followed by a UUID
+	// which is unique to the request.
+	createAttemptMarkerPrefix = "bitwarden-sm-create-attempt: "
+	// createRecoveryTimeout limits the lookup of a secret whose create request was aborted by a timeout or an
+	// interrupt.
+	createRecoveryTimeout = 30 * time.Second
+)
+
+// createRetryDelay is the time to wait before a create request is sent again. It doubles with every further attempt,
+// so a rate limited create request backs off.
+var createRetryDelay = 2 * time.Second
+
+// secretCreation records the markers its create requests added to the note of the secret, so a secret created by a
+// request whose response was lost can be recognised. The markers are removed once the secret is found.
+type secretCreation struct {
+	key            string
+	value          string
+	note           string
+	organizationId string
+	projectIds     []string
+	markers        []string
+}
+
+// markedNote returns the note to send with the next create request, which ends with a new marker.
+func (c *secretCreation) markedNote() string {
+	marker := createAttemptMarkerPrefix + uuid.NewString()
+	c.markers = append(c.markers, marker)
+	if c.note == "" {
+		return marker
+	}
+	return c.note + "\n" + marker
+}
+
+// created reports whether the secret was created by one of the create requests.
+func (c *secretCreation) created(secret sdk.SecretResponse) bool {
+	for _, marker := range c.markers {
+		if strings.HasSuffix(secret.Note, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// createSecretIdempotently creates a secret and retries failed create requests without creating duplicates: every
+// request marks the note of the secret, and if a request fails in a way which leaves open whether the secret was
+// created, the secret is looked up by the markers before the request is sent again. Rate limited requests are sent
+// again without a lookup. The marker is removed from the created secret. If that fails, the marked secret is returned
+// together with the error.
+//
+// The requests are bound to ctx. If ctx is done before the secret was confirmed, the abandoned request may still be
+// completed, so the secret is looked up once more with a context which is not cancelled, see recoverCreatedSecret.
+func createSecretIdempotently(ctx context.Context, bitwardenClient sdk.BitwardenClientInterface, key string, value string, note string, organizationId string, projectIds []string) (*sdk.SecretResponse, error) {
+	creation := &secretCreation{
+		key:            key,
+		value:          value,
+		note:           note,
+		organizationId: organizationId,
+		projectIds:     projectIds,
+	}
+
+	secret, err := sendCreateRequests(ctx, clientWithContext(ctx, bitwardenClient), creation)
+	if err == nil || ctx.Err() == nil || len(creation.markers) == 0 {
+		return secret, err
+	}
+	// Requests rejected by Bitwarden Secrets Manager did not create a secret.
+	if secret == nil && !isContextError(err) && classifyRequestError(err) != requestAmbiguous {
+		return nil, err
+	}
+	return recoverCreatedSecret(ctx, bitwardenClient, creation, secret)
+}
+
+// sendCreateRequests sends the create requests of a secret, see createSecretIdempotently.
+func sendCreateRequests(ctx context.Context, bitwardenClient sdk.BitwardenClientInterface, creation *secretCreation) (*sdk.SecretResponse, error) {
+	requestCtx := withRequestLogging(ctx)
+	var lastErr error
+	for attempt := 1; attempt <= maxCreateAttempts; attempt++ {
+		if attempt > 1 {
+			select {
+			case <-ctx.Done():
+				return nil, fmt.Errorf("%w (retry aborted: %w)", lastErr, ctx.Err())
+			case <-time.After(createRetryDelay << (attempt - 2)):
+			}
+			tflog.SubsystemDebug(requestCtx, requestLogSubsystem, "Retrying request to Bitwarden Secrets Manager", map[string]any{
+				"operation":   "Secrets.Create",
+				"key":         creation.key,
+				"retry_count": attempt - 1,
+			})
+		}
+
+		secret, err := bitwardenClient.Secrets().Create(creation.key, creation.value, creation.markedNote(), creation.organizationId, creation.projectIds)
+		if err == nil {
+			return removeCreateAttemptMarker(bitwardenClient, creation, secret)
+		}
+		lastErr = err
+
+		switch classifyRequestError(err) {
+		case requestAmbiguous:
+			tflog.Warn(ctx, "Create request of secret failed ambiguously, looking for a secret created by it", map[string]any{
+				"attempt": attempt,
+				"error":   err.Error(),
+			})
+		case requestRateLimited:
+			// The server did not process the request, so there is no secret to look for.
+			tflog.Warn(ctx, "Create request of secret was rate limited, sending it again", map[string]any{
+				"attempt": attempt,
+				"error":   err.Error(),
+			})
+			continue
+		default:
+			return nil, err
+		}
+
+		created, lookupErr := findCreatedSecret(bitwardenClient, creation)
+		if lookupErr != nil {
+			return nil, fmt.Errorf("%w\n\nIt could not be checked whether the secret was created anyway: %s. "+
+				"Set enforce_unique_key to \"adopt_existing\" to take over a secret which was created before retrying.", err, lookupErr)
+		}
+		if created != nil {
+			tflog.Info(ctx, "Recovered secret created by a failed create request", map[string]any{"id": created.ID})
+			return removeCreateAttemptMarker(bitwardenClient, creation, created)
+		}
+	}
+
+	return nil, lastErr
+}
+
+// recoverCreatedSecret looks up the secret of create requests which were aborted because ctx is done, with a new
+// context which keeps the values of ctx, and removes the marker from its note. The secret was created if its create
+// request or the removal of the marker was aborted.
+func recoverCreatedSecret(ctx context.Context, bitwardenClient sdk.BitwardenClientInterface, creation *secretCreation, secret *sdk.SecretResponse) (*sdk.SecretResponse, error) {
+	recoveryCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), createRecoveryTimeout)
+	defer cancel()
+	client := clientWithContext(recoveryCtx, bitwardenClient)
+
+	if secret == nil {
+		created, err := findCreatedSecret(client, creation)
+		if err != nil {
+			return nil, fmt.Errorf("the create request was aborted before Bitwarden Secrets Manager confirmed it and it could not be "+
+				"checked whether the secret was created anyway: %s. Set enforce_unique_key to \"adopt_existing\" to take over a "+
+				"secret which was created before retrying.", err)
+		}
+		if created == nil {
+			return nil, fmt.Errorf("the create request was aborted before Bitwarden Secrets Manager confirmed it and no secret "+
+				"created by it was found. If the request is completed later, the secret with the key %q has a note ending with "+
+				"a line starting with %q. Set enforce_unique_key to \"adopt_existing\" to take it over when retrying.",
+				creation.key, strings.TrimSpace(createAttemptMarkerPrefix))
+		}
+		secret = created
+	}
+
+	tflog.Info(ctx, "Recovered secret created by an aborted create request", map[string]any{"id": secret.ID})
+	return removeCreateAttemptMarker(client, creation, secret)
+}
+
+// findCreatedSecret returns the secret created by one of the create requests, or nil if no such secret exists.
+func findCreatedSecret(bitwardenClient sdk.BitwardenClientInterface, creation *secretCreation) (*sdk.SecretResponse, error) {
+	var projectId *string
+	if len(creation.projectIds) > 0 {
+		projectId = &creation.projectIds[0]
+	}
+	candidates, err := findSecretsWithKey(bitwardenClient, creation.organizationId, creation.key, projectId)
+	if err != nil {
+		return nil, err
+	}
+
+	var created []sdk.SecretResponse
+	for _, candidate := range candidates {
+		if creation.created(candidate) {
+			created = append(created, candidate)
+		}
+	}
+
+	switch len(created) {
+	case 0:
+		return nil, nil
+	case 1:
+		return &created[0], nil
+	default:
+		ids := make([]string, 0, len(created))
+		for _, secret := range created {
+			ids = append(ids, secret.ID)
+		}
+		return nil, fmt.Errorf("the secrets with ids %s were created by the create requests", strings.Join(ids, ", "))
+	}
+}
+
+// removeCreateAttemptMarker restores the configured note of a secret created by one of the create requests.
+func removeCreateAttemptMarker(bitwardenClient sdk.BitwardenClientInterface, creation *secretCreation, secret *sdk.SecretResponse) (*sdk.SecretResponse, error) {
+	updated, err := bitwardenClient.Secrets().Update(secret.ID, creation.key, creation.value, creation.note, creation.organizationId, creation.projectIds)
+	if err != nil {
+		return secret, fmt.Errorf("the secret with id %s was created, but the marker of the create request could not be "+
+			"removed from its note: %w", secret.ID, err)
+	}
+	return updated, nil
+}
@@ -0,0 +1,210 @@
+package provider
+
+import (
+	"context"
+	"github.com/bitwarden/sdk-go"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCreateSecretIdempotently(t *testing.T) {
+	withoutCreateRetryDelay(t)
+
+	store := newFakeBitwardenStore(fakeOrganizationID)
+	client := newFaultInjectingClient(store.newClient())
+	if err := client.AccessTokenLogin(fakeAccessToken, nil); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	project, err := client.Projects().Create(fakeOrganizationID, "project")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// An identical secret which was not created by the create requests is not mistaken for the secret of a lost request.
+	existing, err := client.Secrets().Create("API_KEY", "value", "note", fakeOrganizationID, []string{project.ID})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	client.inject("Secrets.Create", fault{err: faultStatus(http.StatusBadGateway)}, fault{err: errFaultTimeout, commit: true})
+	secret, err := createSecretIdempotently(context.Background(), client, "API_KEY", "value", "note", fakeOrganizationID, []string{project.ID})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if secret.ID == existing.ID {
+		t.Fatal("expected the secret created by the lost request, got the existing secret")
+	}
+	if secret.Note != "note" || store.secrets[secret.ID].Note != "note" {
+		t.Fatalf("expected the marker to be removed from the note, got %q", store.secrets[secret.ID].Note)
+	}
+	if calls := client.callCount("Secrets.Create"); calls != 3 {
+		t.Fatalf("expected 3 create requests, got %d", calls)
+	}
+	if stored := len(storedValues(store)); stored != 2 {
+		t.Fatalf("expected 2 stored secrets, got %d", stored)
+	}
+}
+
+func TestCreateSecretIdempotentlyRateLimited(t *testing.T) {
+	delay := createRetryDelay
+	createRetryDelay = 10 * time.Millisecond
+	t.Cleanup(func() {
+		createRetryDelay = delay
+	})
+
+	store := newFakeBitwardenStore(fakeOrganizationID)
+	client := newFaultInjectingClient(store.newClient())
+	if err := client.AccessTokenLogin(fakeAccessToken, nil); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	rateLimited := fault{err: faultStatus(http.StatusTooManyRequests)}
+	client.inject("Secrets.Create", rateLimited, rateLimited)
+
+	started := time.Now()
+	secret, err := createSecretIdempotently(context.Background(), client, "API_KEY", "value", "note", fakeOrganizationID, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	// The delay doubles with every retry.
+	if elapsed := time.Since(started); elapsed < 3*createRetryDelay {
+		t.Fatalf("expected the retries to back off for at least %s, took %s", 3*createRetryDelay, elapsed)
+	}
+	if calls := client.callCount("Secrets.Create"); calls != 3 {
+		t.Fatalf("expected 3 create requests, got %d", calls)
+	}
+	// Rate limited requests were not processed, so no secret is looked up.
+	if calls := client.callCount("Secrets.List"); calls != 0 {
+		t.Fatalf("expected no lookup of the secret, got %d", calls)
+	}
+	if secret.Note != "note" || len(storedValues(store)) != 1 {
+		t.Fatalf("expected a single secret without marker, got %q and %d secrets", secret.Note, len(storedValues(store)))
+	}
+}
+
+func TestSecretCreationMarkers(t *testing.T) {
+	creation := &secretCreation{note: "note"}
+	first := creation.markedNote()
+	second := creation.markedNote()
+	if first == second || !strings.HasPrefix(first, "note\n"+createAttemptMarkerPrefix) {
+		t.Fatalf("expected a new marker per create request, got %q and %q", first, second)
+	}
+
+	for note, expected := range map[string]bool{
+		first:  true,
+		second: true,
+		"note": false,
+		"note\n" + createAttemptMarkerPrefix + "00000000-0000-0000-0000-000000000000": false,
+	} {
+		if actual := creation.created(sdk.SecretResponse{Note: note}); actual != expected {
+			t.Errorf("expected %q to be created by the create requests: %t, got %t", note, expected, actual)
+		}
+	}
+
+	if marked := (&secretCreation{}).markedNote(); !strings.HasPrefix(marked, createAttemptMarkerPrefix) {
+		t.Fatalf("expected an empty note to consist of the marker, got %q", marked)
+	}
+}
+
+func TestCreateSecretIdempotentlyErrors(t *testing.T) {
+	withoutCreateRetryDelay(t)
+
+	tests := map[string]struct {
+		setup    func(client *faultInjectingClient)
+		expected string
+	}{
+		"not ambiguous": {
+			setup: func(client *faultInjectingClient) {
+				client.inject("Secrets.Create", fault{err: faultStatus(http.StatusBadRequest)})
+			},
+			expected: "[400 Bad Request]",
+		},
+		"lookup fails": {
+			setup: func(client *faultInjectingClient) {
+				client.inject("Secrets.Create", fault{err: errFaultTimeout, commit: true})
+				client.inject("Secrets.List", fault{err: faultStatus(http.StatusServiceUnavailable)})
+			},
+			expected: "It could not be checked whether the secret was created anyway: API error: Received error message from server: [503 Service Unavailable]",
+		},
+		"marker not removed": {
+			setup: func(client *faultInjectingClient) {
+				client.inject("Secrets.Update", fault{err: faultStatus(http.StatusServiceUnavailable)})
+			},
+			expected: "but the marker of the create request could not be removed from its note",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			client := newFaultInjectingClient(newFakeBitwardenStore(fakeOrganizationID).newClient())
+			if err := client.AccessTokenLogin(fakeAccessToken, nil); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			test.setup(client)
+
+			_, err := createSecretIdempotently(context.Background(), client, "API_KEY", "value", "", fakeOrganizationID, nil)
+			if err == nil || !strings.Contains(err.Error(), test.expected) {
+				t.Fatalf("expected an error containing %q, got: %v", test.expected, err)
+			}
+		})
+	}
+}
+
+func TestCreateSecretIdempotentlyCancelled(t *testing.T) {
+	client := newFaultInjectingClient(newFakeBitwardenStore(fakeOrganizationID).newClient())
+	if err := client.AccessTokenLogin(fakeAccessToken, nil); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	client.inject("Secrets.Create", fault{err: faultStatus(http.StatusInternalServerError)})
+
+	// The context is cancelled while the retry waits.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer time.AfterFunc(20*time.Millisecond, cancel).Stop()
+
+	_, err := createSecretIdempotently(ctx, client, "API_KEY", "value", "", fakeOrganizationID, nil)
+	if err == nil || !strings.Contains(err.Error(), "no secret created by it was found") {
+		t.Fatalf("expected the retry to be aborted, got: %v", err)
+	}
+	if calls := client.callCount("Secrets.Create"); calls != 1 {
+		t.Fatalf("expected a single create request, got %d", calls)
+	}
+	// After the cancellation, the secret was looked up once more.
+	if calls := client.callCount("Secrets.List"); calls != 2 {
+		t.Fatalf("expected 2 lookups of the secret, got %d", calls)
+	}
+}
+
+func TestCreateSecretIdempotentlyRecoversAbortedCreate(t *testing.T) {
+	for name, faults := range map[string][]fault{
+		"create aborted":         {{hang: 200 * time.Millisecond}},
+		"lost response aborted":  {{err: errFaultTimeout, commit: true, hang: 200 * time.Millisecond}},
+		"marker removal aborted": nil,
+	} {
+		t.Run(name, func(t *testing.T) {
+			store := newFakeBitwardenStore(fakeOrganizationID)
+			client := newFaultInjectingClient(store.newClient())
+			if err := client.AccessTokenLogin(fakeAccessToken, nil); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			client.inject("Secrets.Create", faults...)
+			if faults == nil {
+				client.inject("Secrets.Update", fault{latency: 200 * time.Millisecond, err: faultStatus(http.StatusServiceUnavailable)})
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+			defer cancel()
+
+			secret, err := createSecretIdempotently(ctx, client, "API_KEY", "value", "note", fakeOrganizationID, nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if secret.Note != "note" || store.secrets[secret.ID].Note != "note" {
+				t.Fatalf("expected the marker to be removed from the note, got %q", store.secrets[secret.ID].Note)
+			}
+			if stored := len(storedValues(store)); stored != 1 {
+				t.Fatalf("expected 1 stored secret, got %d", stored)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package provider
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// apiIdentity describes the machine account the provider is authenticated as.
+type apiIdentity struct {
+	MachineAccountID string
+	OrganizationID   string
+	AccessTokenID    string
+	// ExpiresAt is the expiration time of the bearer token issued by the identity endpoint.
+	ExpiresAt time.Time
+}
+
+// projectPermission is the effective permission of the authenticated machine account on a project.
+type projectPermission struct {
+	Read  bool
+	Write bool
+}
+
+type projectPermissionResponse struct {
+	ID    string `json:"id"`
+	Read  bool   `json:"read"`
+	Write bool   `json:"write"`
+}
+
+// Identity returns the identity of the authenticated machine account, which is taken from the claims of the
+// bearer token issued by the identity endpoint.
+func (c *httpAPIClient) Identity(ctx context.Context) (*apiIdentity, error) {
+	token, err := c.login(ctx)
+	if err != nil {
+		return nil, err
+	}
+	identity, err := parseIdentityClaims(token.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+	identity.AccessTokenID = c.credentials.AccessTokenID
+	return identity, nil
+}
+
+// ProjectPermission returns the permission of the authenticated machine account on the project.
+func (c *httpAPIClient) ProjectPermission(ctx context.Context, projectID string) (*projectPermission, error) {
+	var response projectPermissionResponse
+	if err := c.do(ctx, http.MethodGet, "/projects/"+url.PathEscape(projectID), nil, &response); err != nil {
+		return nil, err
+	}
+	return &projectPermission{Read: response.Read, Write: response.Write}, nil
+}
+
+// parseIdentityClaims reads the identity from the claims of a JWT bearer token. The signature is not verified,
+// the token was received from the identity endpoint over TLS and is only used to describe the identity.
+func parseIdentityClaims(jwt string) (*apiIdentity, error) {
+	parts := strings.Split(jwt, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("bearer token is not a JWT")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode the claims of the bearer token: %w", err)
+	}
+
+	var claims struct {
+		Subject      string `json:"sub"`
+		Organization string `json:"organization"`
+		Expiration   int64  `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, fmt.Errorf("unable to decode the claims of the bearer token: %w", err)
+	}
+	if claims.Subject == "" {
+		return nil, errors.New("bearer token does not contain the machine account")
+	}
+
+	return &apiIdentity{
+		MachineAccountID: claims.Subject,
+		OrganizationID:   claims.Organization,
+		ExpiresAt:        time.Unix(claims.Expiration, 0).UTC(),
+	}, nil
+}
@@ -0,0 +1,60 @@
+package provider
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestParseIdentityClaims(t *testing.T) {
+	identity, err := parseIdentityClaims(testBearerToken)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if identity.MachineAccountID != "5d5a8a06-2d3b-4f0e-9a39-b2a600f0c1f4" ||
+		identity.OrganizationID != "3b2c3d6e-9b3f-4a7e-8f3a-b2a600f0c1f5" ||
+		!identity.ExpiresAt.Equal(time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected identity: %+v", identity)
+	}
+
+	withoutSubject := "e30." + base64.RawURLEncoding.EncodeToString([]byte(`{"exp":1893456000}`)) + ".c2ln"
+	for _, invalid := range []string{"", "stand-in-token", "e30.!!!.c2ln", "e30.e30", withoutSubject} {
+		if _, err := parseIdentityClaims(invalid); err == nil {
+			t.Fatalf("expected an error for bearer token %q", invalid)
+		}
+	}
+}
+
+func TestHTTPAPIClientIdentity(t *testing.T) {
+	standIn := newAPIStandIn(t)
+	standIn.handle("GET /projects/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("id") != validProjectUUID {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"id": validProjectUUID, "name": "project", "read": true, "write": false})
+	})
+	client := standIn.client()
+
+	identity, err := client.Identity(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if identity.MachineAccountID != "5d5a8a06-2d3b-4f0e-9a39-b2a600f0c1f4" || identity.AccessTokenID != "ec2c1d46-6a4b-4751-a310-af9601317f2d" {
+		t.Fatalf("unexpected identity: %+v", identity)
+	}
+
+	permission, err := client.ProjectPermission(context.Background(), validProjectUUID)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if *permission != (projectPermission{Read: true, Write: false}) {
+		t.Fatalf("unexpected permission: %+v", permission)
+	}
+
+	if _, err := client.ProjectPermission(context.Background(), invalidProjectUUID1); !isNotFound(err) {
+		t.Fatalf("expected a not found error, got: %v", err)
+	}
+}
@@ -0,0 +1,46 @@
+package provider
+
+import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ function.Function = &isUUIDFunction{}
+
+// NewIsUUIDFunction is a helper function to simplify the provider implementation.
+func NewIsUUIDFunction() function.Function {
+	return &isUUIDFunction{}
+}
+
+// isUUIDFunction defines the function implementation.
+type isUUIDFunction struct{}
+
+func (f *isUUIDFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "is_uuid"
+}
+
+func (f *isUUIDFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:             "Checks whether a string is a valid UUID",
+		Description:         "Returns true if the given string is a valid UUID, using the same rules as the validation of ID attributes of this provider.",
+		MarkdownDescription: "Returns `true` if the given string is a valid UUID, using the same rules as the validation of `ID` attributes of this provider.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "value",
+				Description: "The string to check.",
+			},
+		},
+		Return: function.BoolReturn{},
+	}
+}
+
+func (f *isUUIDFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var value string
+	resp.Error = req.Arguments.Get(ctx, &value)
+	if resp.Error != nil {
+		return
+	}
+
+	resp.Error = resp.Result.Set(ctx, isUUID(value))
+}
@@ -0,0 +1,43 @@
+package provider
+
+import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"testing"
+)
+
+func TestIsUUIDFunction(t *testing.T) {
+	tests := map[string]struct {
+		value    string
+		expected bool
+	}{
+		"valid":                   {value: validProjectUUID, expected: true},
+		"invalid character":       {value: invalidProjectUUID1, expected: false},
+		"missing character":       {value: invalidProjectUUID2, expected: false},
+		"empty":                   {value: "", expected: false},
+		"secret reference":        {value: "bws://" + validProjectUUID + "/KEY", expected: false},
+		"upper case is supported": {value: "5D5A8A06-2D3B-4F0E-9A39-B2A600F0C1F4", expected: true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{types.StringValue(test.value)}),
+			}
+			resp := function.RunResponse{
+				Result: function.NewResultData(types.BoolUnknown()),
+			}
+
+			NewIsUUIDFunction().Run(context.Background(), req, &resp)
+
+			if resp.Error != nil {
+				t.Fatalf("unexpected error: %s", resp.Error)
+			}
+			if !resp.Result.Value().Equal(types.BoolValue(test.expected)) {
+				t.Fatalf("expected %t, got %s", test.expected, resp.Result.Value())
+			}
+		})
+	}
+}
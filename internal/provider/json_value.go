@@ -0,0 +1,236 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"math/big"
+)
+
+// encodeJSONValue converts the value of a value_json attribute into the normalised JSON document stored in
+// Bitwarden Secrets Manager. Structured values (objects, tuples, lists, maps, ...) are encoded to JSON. Plain
+// strings are expected to already contain a JSON document, e.g. the result of jsonencode() or file(), and are
+// validated and normalised.
+func encodeJSONValue(ctx context.Context, value types.Dynamic) (string, error) {
+	if value.IsUnknown() || value.IsUnderlyingValueUnknown() {
+		return "", fmt.Errorf("value_json is not known yet")
+	}
+
+	if stringValue, ok := value.UnderlyingValue().(types.String); ok {
+		return normaliseJSON(stringValue.ValueString())
+	}
+
+	terraformValue, err := value.ToTerraformValue(ctx)
+	if err != nil {
+		return "", fmt.Errorf("unable to convert value_json: %w", err)
+	}
+
+	decoded, err := terraformValueToJSON(terraformValue)
+	if err != nil {
+		return "", err
+	}
+
+	return marshalJSON(decoded)
+}
+
+// decodeJSONValue converts a JSON document stored in Bitwarden Secrets Manager into a dynamic value. JSON objects
+// become objects, arrays become tuples and scalars become their respective primitive type.
+func decodeJSONValue(document string) (types.Dynamic, error) {
+	decoded, err := unmarshalJSON(document)
+	if err != nil {
+		return types.DynamicNull(), err
+	}
+
+	value, err := jsonToAttrValue(decoded)
+	if err != nil {
+		return types.DynamicNull(), err
+	}
+
+	return types.DynamicValue(value), nil
+}
+
+// decodeSecretJSONValue returns the value_json representation of a secret read by the secret data source. Only JSON
+// objects and arrays are decoded. All other values, including JSON scalars like "123", "true" or "null", are plain
+// secrets and returned as the string they are stored as.
+func decodeSecretJSONValue(value string) types.Dynamic {
+	decoded, err := unmarshalJSON(value)
+	if err == nil {
+		switch decoded.(type) {
+		case map[string]any, []any:
+			if structured, err := jsonToAttrValue(decoded); err == nil {
+				return types.DynamicValue(structured)
+			}
+		}
+	}
+	return types.DynamicValue(types.StringValue(value))
+}
+
+// refreshJSONValue returns the value_json representation of the secret value read from Bitwarden Secrets Manager.
+// If the remote document is semantically equal to the current value, the current value is kept, so that
+// whitespace, key order or the chosen representation (object or encoded string) do not cause a diff.
+func refreshJSONValue(ctx context.Context, current types.Dynamic, remote string) (types.Dynamic, error) {
+	normalisedRemote, err := normaliseJSON(remote)
+	if err != nil {
+		return current, err
+	}
+
+	if normalisedCurrent, err := encodeJSONValue(ctx, current); err == nil && normalisedCurrent == normalisedRemote {
+		return current, nil
+	}
+
+	// Keep the representation chosen in the configuration if the value was provided as encoded string.
+	if _, ok := current.UnderlyingValue().(types.String); ok {
+		return types.DynamicValue(types.StringValue(normalisedRemote)), nil
+	}
+
+	return decodeJSONValue(normalisedRemote)
+}
+
+// normaliseJSON validates the given document and returns its compact representation with sorted object keys.
+func normaliseJSON(document string) (string, error) {
+	decoded, err := unmarshalJSON(document)
+	if err != nil {
+		return "", err
+	}
+	return marshalJSON(decoded)
+}
+
+func unmarshalJSON(document string) (any, error) {
+	decoder := json.NewDecoder(bytes.NewReader([]byte(document)))
+	decoder.UseNumber()
+
+	var decoded any
+	if err := decoder.Decode(&decoded); err != nil {
+		return nil, fmt.Errorf("value is not a valid JSON document: %w", err)
+	}
+	if decoder.More() {
+		return nil, fmt.Errorf("value is not a valid JSON document: unexpected data after top-level value")
+	}
+
+	return decoded, nil
+}
+
+func marshalJSON(value any) (string, error) {
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return "", fmt.Errorf("unable to encode value as JSON: %w", err)
+	}
+	return string(bytes.TrimSuffix(buffer.Bytes(), []byte("\n"))), nil
+}
+
+func terraformValueToJSON(value tftypes.Value) (any, error) {
+	if !value.IsKnown() {
+		return nil, fmt.Errorf("value_json contains unknown values")
+	}
+	if value.IsNull() {
+		return nil, nil
+	}
+
+	valueType := value.Type()
+	switch {
+	case valueType.Is(tftypes.String):
+		var s string
+		err := value.As(&s)
+		return s, err
+	case valueType.Is(tftypes.Bool):
+		var b bool
+		err := value.As(&b)
+		return b, err
+	case valueType.Is(tftypes.Number):
+		n := new(big.Float)
+		if err := value.As(&n); err != nil {
+			return nil, err
+		}
+		if n.IsInt() {
+			return json.Number(n.Text('f', 0)), nil
+		}
+		return json.Number(n.Text('g', -1)), nil
+	case valueType.Is(tftypes.List{}), valueType.Is(tftypes.Set{}), valueType.Is(tftypes.Tuple{}):
+		var elements []tftypes.Value
+		if err := value.As(&elements); err != nil {
+			return nil, err
+		}
+		result := make([]any, 0, len(elements))
+		for _, element := range elements {
+			decoded, err := terraformValueToJSON(element)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, decoded)
+		}
+		return result, nil
+	case valueType.Is(tftypes.Map{}), valueType.Is(tftypes.Object{}):
+		var attributes map[string]tftypes.Value
+		if err := value.As(&attributes); err != nil {
+			return nil, err
+		}
+		result := make(map[string]any, len(attributes))
+		for key, attribute := range attributes {
+			decoded, err := terraformValueToJSON(attribute)
+			if err != nil {
+				return nil, err
+			}
+			result[key] = decoded
+		}
+		return result, nil
+	}
+
+	return nil, fmt.Errorf("value_json contains an unsupported type: %s", valueType)
+}
+
+func jsonToAttrValue(value any) (attr.Value, error) {
+	switch v := value.(type) {
+	case nil:
+		return types.DynamicNull(), nil
+	case string:
+		return types.StringValue(v), nil
+	case bool:
+		return types.BoolValue(v), nil
+	case json.Number:
+		n, _, err := big.ParseFloat(v.String(), 10, 512, big.ToNearestEven)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse JSON number %s: %w", v, err)
+		}
+		return types.NumberValue(n), nil
+	case []any:
+		elementTypes := make([]attr.Type, 0, len(v))
+		elements := make([]attr.Value, 0, len(v))
+		for _, element := range v {
+			converted, err := jsonToAttrValue(element)
+			if err != nil {
+				return nil, err
+			}
+			elementTypes = append(elementTypes, converted.Type(context.Background()))
+			elements = append(elements, converted)
+		}
+		tuple, diags := types.TupleValue(elementTypes, elements)
+		if diags.HasError() {
+			return nil, fmt.Errorf("unable to convert JSON array")
+		}
+		return tuple, nil
+	case map[string]any:
+		attributeTypes := make(map[string]attr.Type, len(v))
+		attributes := make(map[string]attr.Value, len(v))
+		for key, element := range v {
+			converted, err := jsonToAttrValue(element)
+			if err != nil {
+				return nil, err
+			}
+			attributeTypes[key] = converted.Type(context.Background())
+			attributes[key] = converted
+		}
+		object, diags := types.ObjectValue(attributeTypes, attributes)
+		if diags.HasError() {
+			return nil, fmt.Errorf("unable to convert JSON object")
+		}
+		return object, nil
+	}
+
+	return nil, fmt.Errorf("unsupported JSON value of type %T", value)
+}
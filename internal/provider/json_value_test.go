@@ -0,0 +1,151 @@
+package provider
+
+import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"math/big"
+	"testing"
+)
+
+func testJSONObject(t *testing.T) types.Dynamic {
+	object, diags := types.ObjectValue(
+		map[string]attr.Type{
+			"user":  types.StringType,
+			"port":  types.NumberType,
+			"ratio": types.NumberType,
+			"tls":   types.BoolType,
+			"hosts": types.TupleType{ElemTypes: []attr.Type{types.StringType, types.StringType}},
+		},
+		map[string]attr.Value{
+			"user":  types.StringValue("admin<&>"),
+			"port":  types.NumberValue(big.NewFloat(5432)),
+			"ratio": types.NumberValue(big.NewFloat(0.5)),
+			"tls":   types.BoolValue(true),
+			"hosts": types.TupleValueMust([]attr.Type{types.StringType, types.StringType}, []attr.Value{types.StringValue("a"), types.StringValue("b")}),
+		},
+	)
+	if diags.HasError() {
+		t.Fatalf("unable to build test object: %v", diags)
+	}
+	return types.DynamicValue(object)
+}
+
+const testJSONDocument = `{"hosts":["a","b"],"port":5432,"ratio":0.5,"tls":true,"user":"admin<&>"}`
+
+func TestEncodeJSONValueStructured(t *testing.T) {
+	encoded, err := encodeJSONValue(context.Background(), testJSONObject(t))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if encoded != testJSONDocument {
+		t.Fatalf("expected %s, got %s", testJSONDocument, encoded)
+	}
+}
+
+func TestEncodeJSONValueNormalisesString(t *testing.T) {
+	document := "{\n  \"user\": \"admin<&>\",\n  \"tls\": true, \"ratio\": 0.5,\r\n \"port\": 5432, \"hosts\": [ \"a\", \"b\" ]\n}\n"
+
+	encoded, err := encodeJSONValue(context.Background(), types.DynamicValue(types.StringValue(document)))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if encoded != testJSONDocument {
+		t.Fatalf("expected %s, got %s", testJSONDocument, encoded)
+	}
+}
+
+func TestEncodeJSONValueRejectsInvalidDocuments(t *testing.T) {
+	for _, document := range []string{"", "not json", `{"a": 1`, `{"a": 1} {"b": 2}`} {
+		if _, err := encodeJSONValue(context.Background(), types.DynamicValue(types.StringValue(document))); err == nil {
+			t.Fatalf("expected error for document %q", document)
+		}
+	}
+}
+
+func TestDecodeJSONValueRoundTrip(t *testing.T) {
+	decoded, err := decodeJSONValue(testJSONDocument)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !decoded.Equal(testJSONObject(t)) {
+		t.Fatalf("expected %s, got %s", testJSONObject(t), decoded)
+	}
+
+	encoded, err := encodeJSONValue(context.Background(), decoded)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if encoded != testJSONDocument {
+		t.Fatalf("expected %s, got %s", testJSONDocument, encoded)
+	}
+}
+
+func TestRefreshJSONValue(t *testing.T) {
+	ctx := context.Background()
+
+	// Cosmetic differences keep the current value.
+	current := testJSONObject(t)
+	refreshed, err := refreshJSONValue(ctx, current, "{ \"user\": \"admin<&>\", \"port\": 5432, \"ratio\": 0.5, \"tls\": true, \"hosts\": [\"a\", \"b\"] }")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !refreshed.Equal(current) {
+		t.Fatalf("expected unchanged value, got %s", refreshed)
+	}
+
+	// A string representation is kept as string.
+	currentString := types.DynamicValue(types.StringValue("{\"a\": 1}\n"))
+	refreshed, err = refreshJSONValue(ctx, currentString, `{"a":1}`)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !refreshed.Equal(currentString) {
+		t.Fatalf("expected unchanged value, got %s", refreshed)
+	}
+	refreshed, err = refreshJSONValue(ctx, currentString, `{"a":2}`)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !refreshed.Equal(types.DynamicValue(types.StringValue(`{"a":2}`))) {
+		t.Fatalf("expected updated string value, got %s", refreshed)
+	}
+
+	// Real changes are decoded into a structured value.
+	refreshed, err = refreshJSONValue(ctx, current, `{"user":"other"}`)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	expected := types.DynamicValue(types.ObjectValueMust(
+		map[string]attr.Type{"user": types.StringType},
+		map[string]attr.Value{"user": types.StringValue("other")},
+	))
+	if !refreshed.Equal(expected) {
+		t.Fatalf("expected %s, got %s", expected, refreshed)
+	}
+
+	// Invalid remote documents are reported.
+	if _, err = refreshJSONValue(ctx, current, "not json"); err == nil {
+		t.Fatal("expected error for invalid remote document")
+	}
+}
+
+func TestDecodeSecretJSONValue(t *testing.T) {
+	decoded := decodeSecretJSONValue(testJSONDocument)
+	if !decoded.Equal(testJSONObject(t)) {
+		t.Fatalf("expected %s, got %s", testJSONObject(t), decoded)
+	}
+
+	array := decodeSecretJSONValue(`["a", 1]`)
+	if _, ok := array.UnderlyingValue().(types.Tuple); !ok {
+		t.Fatalf("expected a tuple, got %s", array)
+	}
+
+	// Scalars and values which are no JSON documents are plain secrets.
+	for _, value := range []string{"123", "true", "null", `"quoted"`, "plain", "{invalid"} {
+		decoded := decodeSecretJSONValue(value)
+		if !decoded.Equal(types.DynamicValue(types.StringValue(value))) {
+			t.Fatalf("expected %q to be returned as string, got %s", value, decoded)
+		}
+	}
+}
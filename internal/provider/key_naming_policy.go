@@ -0,0 +1,356 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// Case styles supported by the key naming policy.
+	caseStyleUpperSnake = "upper_snake"
+	caseStyleLowerSnake = "lower_snake"
+	caseStyleKebab      = "kebab"
+	caseStyleCamel      = "camel"
+	caseStylePascal     = "pascal"
+)
+
+var caseStyles = []string{caseStyleUpperSnake, caseStyleLowerSnake, caseStyleKebab, caseStyleCamel, caseStylePascal}
+
+// keyNamingPolicyModel describes the key_naming_policy attribute of the provider and of the secret and secrets
+// resources.
+type keyNamingPolicyModel struct {
+	Pattern           types.String `tfsdk:"pattern"`
+	MaxLength         types.Int64  `tfsdk:"max_length"`
+	AllowedCharacters types.String `tfsdk:"allowed_characters"`
+	CaseStyle         types.String `tfsdk:"case_style"`
+}
+
+// keyNamingPolicy holds the rules every secret key has to satisfy. Unset rules are not checked.
+type keyNamingPolicy struct {
+	pattern           *regexp.Regexp
+	maxLength         int64
+	allowedCharacters string
+	allowedCharacter  *regexp.Regexp
+	caseStyle         string
+	// owner is "provider" or "resource", depending on where the policy is configured.
+	owner string
+}
+
+// newKeyNamingPolicy compiles the configured rules of a key naming policy.
+func newKeyNamingPolicy(model keyNamingPolicyModel) (*keyNamingPolicy, error) {
+	policy := &keyNamingPolicy{
+		maxLength: model.MaxLength.ValueInt64(),
+		caseStyle: model.CaseStyle.ValueString(),
+		owner:     "provider",
+	}
+
+	if pattern := model.Pattern.ValueString(); pattern != "" {
+		compiled, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("pattern is not a valid regular expression: %w", err)
+		}
+		policy.pattern = compiled
+	}
+
+	if allowedCharacters := model.AllowedCharacters.ValueString(); allowedCharacters != "" {
+		compiled, err := regexp.Compile("^[" + allowedCharacters + "]$")
+		if err != nil {
+			return nil, fmt.Errorf("allowed_characters is not a valid character class: %w", err)
+		}
+		policy.allowedCharacters = allowedCharacters
+		policy.allowedCharacter = compiled
+	}
+
+	return policy, nil
+}
+
+// violations returns a description of every rule the key does not satisfy.
+func (p *keyNamingPolicy) violations(key string) []string {
+	var violations []string
+
+	if p.maxLength > 0 && int64(utf8.RuneCountInString(key)) > p.maxLength {
+		violations = append(violations, fmt.Sprintf("it is longer than %d characters", p.maxLength))
+	}
+
+	if p.allowedCharacter != nil {
+		var disallowed []string
+		for _, char := range key {
+			if !p.allowedCharacter.MatchString(string(char)) && !slices.Contains(disallowed, string(char)) {
+				disallowed = append(disallowed, string(char))
+			}
+		}
+		if len(disallowed) > 0 {
+			violations = append(violations, fmt.Sprintf("it contains the characters %q which are not in [%s]", strings.Join(disallowed, ""), p.allowedCharacters))
+		}
+	}
+
+	if p.caseStyle != "" && formatKeyWords(splitKeyWords(key), p.caseStyle) != key {
+		violations = append(violations, fmt.Sprintf("it is not in %s case", p.caseStyle))
+	}
+
+	if p.pattern != nil && !p.pattern.MatchString(key) {
+		violations = append(violations, fmt.Sprintf("it does not match the pattern %s", p.pattern))
+	}
+
+	return violations
+}
+
+// normalise suggests a key which satisfies the policy. It returns false if no such key can be derived.
+func (p *keyNamingPolicy) normalise(key string) (string, bool) {
+	normalised := key
+	if p.caseStyle != "" {
+		normalised = formatKeyWords(splitKeyWords(key), p.caseStyle)
+	}
+
+	if p.allowedCharacter != nil {
+		var builder strings.Builder
+		for _, char := range normalised {
+			if p.allowedCharacter.MatchString(string(char)) {
+				builder.WriteRune(char)
+			} else {
+				builder.WriteRune('_')
+			}
+		}
+		normalised = builder.String()
+	}
+
+	if p.maxLength > 0 && int64(utf8.RuneCountInString(normalised)) > p.maxLength {
+		normalised = string([]rune(normalised)[:p.maxLength])
+	}
+
+	if normalised == "" || normalised == key || len(p.violations(normalised)) > 0 {
+		return "", false
+	}
+	return normalised, true
+}
+
+// splitKeyWords splits a key into words at every character which is neither a letter nor a digit and at case
+// changes, e.g. "dbPassword", "DB_PASSWORD" and "Db Password" all consist of the words "db" and "password".
+func splitKeyWords(key string) []string {
+	var words []string
+	var current []rune
+	runes := []rune(key)
+
+	flush := func() {
+		if len(current) > 0 {
+			words = append(words, string(current))
+			current = nil
+		}
+	}
+
+	for i, char := range runes {
+		if !unicode.IsLetter(char) && !unicode.IsDigit(char) {
+			flush()
+			continue
+		}
+
+		if len(current) > 0 && unicode.IsUpper(char) {
+			previous := current[len(current)-1]
+			// An upper case letter starts a new word after a lower case letter or a digit, and as last letter
+			// of an acronym which is followed by a lower case letter, e.g. "API" and "Key" of "APIKey".
+			nextIsLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(previous) || unicode.IsDigit(previous) || (unicode.IsUpper(previous) && nextIsLower) {
+				flush()
+			}
+		}
+
+		current = append(current, char)
+	}
+	flush()
+
+	return words
+}
+
+// formatKeyWords joins words in the given case style.
+func formatKeyWords(words []string, caseStyle string) string {
+	formatted := make([]string, 0, len(words))
+	for i, word := range words {
+		switch caseStyle {
+		case caseStyleUpperSnake:
+			formatted = append(formatted, strings.ToUpper(word))
+		case caseStyleLowerSnake, caseStyleKebab:
+			formatted = append(formatted, strings.ToLower(word))
+		case caseStyleCamel:
+			if i == 0 {
+				formatted = append(formatted, strings.ToLower(word))
+			} else {
+				formatted = append(formatted, capitalise(word))
+			}
+		case caseStylePascal:
+			formatted = append(formatted, capitalise(word))
+		}
+	}
+
+	switch caseStyle {
+	case caseStyleUpperSnake, caseStyleLowerSnake:
+		return strings.Join(formatted, "_")
+	case caseStyleKebab:
+		return strings.Join(formatted, "-")
+	default:
+		return strings.Join(formatted, "")
+	}
+}
+
+func capitalise(word string) string {
+	first, size := utf8.DecodeRuneInString(word)
+	return string(unicode.ToUpper(first)) + strings.ToLower(word[size:])
+}
+
+// checkKeyNamingPolicy returns an error if the planned key violates the policy, which may be nil. The policy of the
+// provider is part of the provider configuration, so resources check it in ModifyPlan: the configuration is validated
+// without a configured provider. Unknown keys are checked once they are known.
+func checkKeyNamingPolicy(ctx context.Context, policy *keyNamingPolicy, keyPath path.Path, key types.String) diag.Diagnostics {
+	validateResp := validator.StringResponse{}
+	keyNamingPolicyValidate(policy).ValidateString(ctx, validator.StringRequest{Path: keyPath, ConfigValue: key}, &validateResp)
+	return validateResp.Diagnostics
+}
+
+// resourceKeyNamingPolicyAttribute is the key_naming_policy attribute of the secret and secrets resources.
+func resourceKeyNamingPolicyAttribute(keys string) schema.SingleNestedAttribute {
+	return schema.SingleNestedAttribute{
+		Description: fmt.Sprintf("Naming rules which %s must satisfy, instead of the key_naming_policy of the provider. "+
+			"Unlike the policy of the provider, these rules are also checked by terraform validate once all of them are known. "+
+			"Unset rules are not checked.", keys),
+		MarkdownDescription: fmt.Sprintf("Naming rules which %s must satisfy, instead of the `key_naming_policy` of the provider. "+
+			"Unlike the policy of the provider, these rules are also checked by `terraform validate` once all of them are known. "+
+			"Unset rules are not checked.", keys),
+		Optional: true,
+		Attributes: map[string]schema.Attribute{
+			"pattern": schema.StringAttribute{
+				Description:         "Regular expression in RE2 syntax which keys must match, e.g. ^[A-Z][A-Z0-9_]*$. Anchor the expression to match the whole key.",
+				MarkdownDescription: "Regular expression in [RE2 syntax](https://github.com/google/re2/wiki/Syntax) which keys must match, e.g. `^[A-Z][A-Z0-9_]*$`. Anchor the expression to match the whole key.",
+				Optional:            true,
+			},
+			"max_length": schema.Int64Attribute{
+				Description: "Maximum number of characters of keys.",
+				Optional:    true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"allowed_characters": schema.StringAttribute{
+				Description:         "Characters allowed in keys, written as the content of a regular expression character class, e.g. A-Z0-9_.",
+				MarkdownDescription: "Characters allowed in keys, written as the content of a regular expression character class, e.g. `A-Z0-9_`.",
+				Optional:            true,
+			},
+			"case_style": schema.StringAttribute{
+				Description: "Case style of keys, one of upper_snake (DB_PASSWORD), lower_snake (db_password), kebab (db-password), " +
+					"camel (dbPassword) or pascal (DbPassword).",
+				MarkdownDescription: "Case style of keys, one of `upper_snake` (`DB_PASSWORD`), `lower_snake` (`db_password`), `kebab` (`db-password`), " +
+					"`camel` (`dbPassword`) or `pascal` (`DbPassword`).",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(caseStyles...),
+				},
+			},
+		},
+	}
+}
+
+// newResourceKeyNamingPolicy compiles the key_naming_policy attribute of a resource. It returns nil if the attribute
+// is null or any of its rules is unknown.
+func newResourceKeyNamingPolicy(ctx context.Context, object types.Object) (*keyNamingPolicy, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	if object.IsNull() || object.IsUnknown() {
+		return nil, diags
+	}
+	for _, value := range object.Attributes() {
+		if value.IsUnknown() {
+			return nil, diags
+		}
+	}
+
+	var model keyNamingPolicyModel
+	diags.Append(object.As(ctx, &model, basetypes.ObjectAsOptions{})...)
+	if diags.HasError() {
+		return nil, diags
+	}
+	policy, err := newKeyNamingPolicy(model)
+	if err != nil {
+		diags.AddAttributeError(
+			path.Root("key_naming_policy"),
+			"Invalid Key Naming Policy",
+			fmt.Sprintf("The key_naming_policy of the resource is invalid: %s.", err),
+		)
+		return nil, diags
+	}
+	policy.owner = "resource"
+	return policy, diags
+}
+
+// effectiveKeyNamingPolicy returns the key naming policy of a resource if it configures one, otherwise the policy of
+// the provider, which may be nil.
+func effectiveKeyNamingPolicy(ctx context.Context, object types.Object, providerPolicy *keyNamingPolicy) (*keyNamingPolicy, diag.Diagnostics) {
+	if object.IsNull() {
+		return providerPolicy, nil
+	}
+	return newResourceKeyNamingPolicy(ctx, object)
+}
+
+var (
+	_ validator.String = resourceKeyNamingPolicyValidator{}
+	_ validator.Map    = resourceKeyNamingPolicyValidator{}
+)
+
+// resourceKeyNamingPolicyValidator validates keys against the key_naming_policy attribute of their resource. Unlike
+// the policy of the provider, it needs no provider configuration, so keys are already checked by terraform validate.
+// It validates the key attribute of the secret resource and the keys of the secrets map of the secrets resource.
+type resourceKeyNamingPolicyValidator struct{}
+
+func (v resourceKeyNamingPolicyValidator) Description(_ context.Context) string {
+	return "the keys must satisfy the key_naming_policy of the resource"
+}
+
+func (v resourceKeyNamingPolicyValidator) MarkdownDescription(_ context.Context) string {
+	return "the keys must satisfy the `key_naming_policy` of the resource"
+}
+
+func (v resourceKeyNamingPolicyValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	policy, diags := configuredResourceKeyNamingPolicy(ctx, req.Config)
+	resp.Diagnostics.Append(diags...)
+	if policy == nil {
+		return
+	}
+	resp.Diagnostics.Append(checkKeyNamingPolicy(ctx, policy, req.Path, req.ConfigValue)...)
+}
+
+func (v resourceKeyNamingPolicyValidator) ValidateMap(ctx context.Context, req validator.MapRequest, resp *validator.MapResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	policy, diags := configuredResourceKeyNamingPolicy(ctx, req.Config)
+	resp.Diagnostics.Append(diags...)
+	if policy == nil {
+		return
+	}
+	for _, key := range sortedKeys(req.ConfigValue.Elements()) {
+		resp.Diagnostics.Append(checkKeyNamingPolicy(ctx, policy, req.Path.AtMapKey(key), types.StringValue(key))...)
+	}
+}
+
+func configuredResourceKeyNamingPolicy(ctx context.Context, config tfsdk.Config) (*keyNamingPolicy, diag.Diagnostics) {
+	var object types.Object
+	diags := config.GetAttribute(ctx, path.Root("key_naming_policy"), &object)
+	if diags.HasError() {
+		return nil, diags
+	}
+	return newResourceKeyNamingPolicy(ctx, object)
+}
+
+func resourceKeyNamingPolicyValidate() resourceKeyNamingPolicyValidator {
+	return resourceKeyNamingPolicyValidator{}
+}
@@ -0,0 +1,312 @@
+package provider
+
+import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func newTestKeyNamingPolicy(t *testing.T, model keyNamingPolicyModel) *keyNamingPolicy {
+	t.Helper()
+
+	policy, err := newKeyNamingPolicy(model)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	return policy
+}
+
+func TestSplitKeyWords(t *testing.T) {
+	tests := map[string][]string{
+		"DB_PASSWORD":   {"DB", "PASSWORD"},
+		"db-password":   {"db", "password"},
+		"Db Password":   {"Db", "Password"},
+		"dbPassword":    {"db", "Password"},
+		"APIKey":        {"API", "Key"},
+		"api2Key":       {"api2", "Key"},
+		"__leading__":   {"leading"},
+		"path/to.value": {"path", "to", "value"},
+	}
+
+	for key, expected := range tests {
+		if actual := splitKeyWords(key); !reflect.DeepEqual(actual, expected) {
+			t.Errorf("expected %q to be split into %q, got %q", key, expected, actual)
+		}
+	}
+}
+
+func TestFormatKeyWords(t *testing.T) {
+	words := []string{"db", "PASSWORD", "Key"}
+	tests := map[string]string{
+		caseStyleUpperSnake: "DB_PASSWORD_KEY",
+		caseStyleLowerSnake: "db_password_key",
+		caseStyleKebab:      "db-password-key",
+		caseStyleCamel:      "dbPasswordKey",
+		caseStylePascal:     "DbPasswordKey",
+	}
+
+	for caseStyle, expected := range tests {
+		if actual := formatKeyWords(words, caseStyle); actual != expected {
+			t.Errorf("expected %s case %q, got %q", caseStyle, expected, actual)
+		}
+	}
+}
+
+func TestKeyNamingPolicyViolations(t *testing.T) {
+	policy := newTestKeyNamingPolicy(t, keyNamingPolicyModel{
+		Pattern:           types.StringValue("^[A-Z]"),
+		MaxLength:         types.Int64Value(12),
+		AllowedCharacters: types.StringValue("A-Z0-9_"),
+		CaseStyle:         types.StringValue(caseStyleUpperSnake),
+	})
+
+	if violations := policy.violations("DB_PASSWORD"); len(violations) != 0 {
+		t.Fatalf("expected no violations, got %q", violations)
+	}
+
+	violations := policy.violations("1st-database-password")
+	expected := []string{
+		"it is longer than 12 characters",
+		`it contains the characters "st-dabepwor" which are not in [A-Z0-9_]`,
+		"it is not in upper_snake case",
+		"it does not match the pattern ^[A-Z]",
+	}
+	if !reflect.DeepEqual(violations, expected) {
+		t.Fatalf("expected the violations %q, got %q", expected, violations)
+	}
+}
+
+func TestKeyNamingPolicyNormalise(t *testing.T) {
+	tests := map[string]struct {
+		model      keyNamingPolicyModel
+		key        string
+		normalised string
+		ok         bool
+	}{
+		"case style": {
+			model:      keyNamingPolicyModel{CaseStyle: types.StringValue(caseStyleUpperSnake)},
+			key:        "Db Password",
+			normalised: "DB_PASSWORD",
+			ok:         true,
+		},
+		"camel case": {
+			model:      keyNamingPolicyModel{CaseStyle: types.StringValue(caseStyleCamel)},
+			key:        "db-password",
+			normalised: "dbPassword",
+			ok:         true,
+		},
+		"allowed characters": {
+			model:      keyNamingPolicyModel{AllowedCharacters: types.StringValue("a-z_")},
+			key:        "db.password",
+			normalised: "db_password",
+			ok:         true,
+		},
+		"max length": {
+			model:      keyNamingPolicyModel{MaxLength: types.Int64Value(5)},
+			key:        "DB_PASSWORD",
+			normalised: "DB_PA",
+			ok:         true,
+		},
+		"pattern cannot be derived": {
+			model: keyNamingPolicyModel{Pattern: types.StringValue("^APP_")},
+			key:   "DB_PASSWORD",
+			ok:    false,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			normalised, ok := newTestKeyNamingPolicy(t, test.model).normalise(test.key)
+			if normalised != test.normalised || ok != test.ok {
+				t.Fatalf("expected (%q, %t), got (%q, %t)", test.normalised, test.ok, normalised, ok)
+			}
+		})
+	}
+}
+
+func TestNewKeyNamingPolicyErrors(t *testing.T) {
+	tests := map[string]struct {
+		model    keyNamingPolicyModel
+		expected string
+	}{
+		"pattern": {
+			model:    keyNamingPolicyModel{Pattern: types.StringValue("^[A-Z")},
+			expected: "pattern is not a valid regular expression",
+		},
+		"allowed characters": {
+			model:    keyNamingPolicyModel{AllowedCharacters: types.StringValue("z-a")},
+			expected: "allowed_characters is not a valid character class",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := newKeyNamingPolicy(test.model)
+			if err == nil || !strings.Contains(err.Error(), test.expected) {
+				t.Fatalf("expected an error containing %q, got: %v", test.expected, err)
+			}
+		})
+	}
+}
+
+func TestKeyNamingPolicyValidator(t *testing.T) {
+	policy := newTestKeyNamingPolicy(t, keyNamingPolicyModel{CaseStyle: types.StringValue(caseStyleUpperSnake)})
+
+	tests := map[string]struct {
+		value    types.String
+		expected string
+	}{
+		"valid":   {value: types.StringValue("DB_PASSWORD")},
+		"unknown": {value: types.StringUnknown()},
+		"invalid": {
+			value:    types.StringValue("db-password"),
+			expected: `The key "db-password" violates the key_naming_policy of the provider: it is not in upper_snake case. Use "DB_PASSWORD" instead.`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := validator.StringRequest{Path: path.Root("key"), ConfigValue: test.value}
+			resp := validator.StringResponse{}
+			keyNamingPolicyValidate(policy).ValidateString(context.Background(), req, &resp)
+
+			if test.expected == "" {
+				if resp.Diagnostics.HasError() {
+					t.Fatalf("unexpected error: %v", resp.Diagnostics)
+				}
+				return
+			}
+			if resp.Diagnostics.ErrorsCount() != 1 || resp.Diagnostics.Errors()[0].Detail() != test.expected {
+				t.Fatalf("expected the error %q, got: %v", test.expected, resp.Diagnostics)
+			}
+		})
+	}
+}
+
+// keyNamingPolicyValue returns a key_naming_policy attribute with the given rules, all other rules are unset.
+func keyNamingPolicyValue(rules map[string]tftypes.Value) tftypes.Value {
+	policyType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"pattern":            tftypes.String,
+		"max_length":         tftypes.Number,
+		"allowed_characters": tftypes.String,
+		"case_style":         tftypes.String,
+	}}
+	return newObjectValue(policyType, rules)
+}
+
+// upperSnakeKeyNamingPolicy is the key_naming_policy attribute of a provider which requires upper_snake case keys.
+func upperSnakeKeyNamingPolicy() map[string]tftypes.Value {
+	return map[string]tftypes.Value{
+		"key_naming_policy": keyNamingPolicyValue(map[string]tftypes.Value{
+			"case_style": tftypes.NewValue(tftypes.String, caseStyleUpperSnake),
+		}),
+	}
+}
+
+func TestSecretResourceKeyNamingPolicy(t *testing.T) {
+	store := newFakeBitwardenStore(fakeOrganizationID)
+	harness := newResourceHarnessWithConfig(t, "bitwarden-sm_secret", store.newClient(), upperSnakeKeyNamingPolicy())
+
+	config := secretConfig("value")
+	config["key"] = tftypes.NewValue(tftypes.String, "db-password")
+	_, diagnostics := harness.apply(config, harness.nullState())
+	expectDiagnosticError(t, diagnostics, `Use "DB_PASSWORD" instead`)
+	if stored := len(storedValues(store)); stored != 0 {
+		t.Fatalf("expected the plan to fail before the secret is created, got %d stored secrets", stored)
+	}
+
+	_, diagnostics = harness.apply(secretConfig("value"), harness.nullState())
+	expectDiagnosticError(t, diagnostics, "")
+}
+
+func TestSecretsResourceKeyNamingPolicy(t *testing.T) {
+	store := newFakeBitwardenStore(fakeOrganizationID)
+	client := store.newClient()
+	if err := client.AccessTokenLogin(fakeAccessToken, nil); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	project, err := client.Projects().Create(fakeOrganizationID, "project")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	harness := newResourceHarnessWithConfig(t, "bitwarden-sm_secrets", client, upperSnakeKeyNamingPolicy())
+
+	config := func(keys ...string) map[string]tftypes.Value {
+		secrets := map[string]tftypes.Value{}
+		for _, key := range keys {
+			secrets[key] = tftypes.NewValue(tftypes.String, "value")
+		}
+		return map[string]tftypes.Value{
+			"project_id": tftypes.NewValue(tftypes.String, project.ID),
+			"secrets":    tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, secrets),
+		}
+	}
+
+	state, diagnostics := harness.apply(config("API_KEY"), harness.nullState())
+	expectDiagnosticError(t, diagnostics, "")
+
+	// Keys added to an existing resource are checked as well.
+	_, diagnostics = harness.apply(config("API_KEY", "db-password"), state)
+	expectDiagnosticError(t, diagnostics, `Use "DB_PASSWORD" instead`)
+	if stored := len(storedValues(store)); stored != 1 {
+		t.Fatalf("expected the plan to fail before the secret is created, got %d stored secrets", stored)
+	}
+}
+
+func TestSecretResourceKeyNamingPolicyOfTheResource(t *testing.T) {
+	store := newFakeBitwardenStore(fakeOrganizationID)
+	harness := newResourceHarnessWithConfig(t, "bitwarden-sm_secret", store.newClient(), upperSnakeKeyNamingPolicy())
+
+	config := secretConfig("value")
+	config["key"] = tftypes.NewValue(tftypes.String, "db password")
+	config["key_naming_policy"] = keyNamingPolicyValue(map[string]tftypes.Value{
+		"allowed_characters": tftypes.NewValue(tftypes.String, "a-z-"),
+	})
+
+	// The policy of the resource needs no provider configuration, so terraform validate reports violations.
+	expectDiagnosticError(t, harness.validate(config), `The key "db password" violates the key_naming_policy of the resource: it contains the characters " " which are not in [a-z-].`)
+
+	// The policy of the resource replaces the upper_snake case style of the provider.
+	config["key"] = tftypes.NewValue(tftypes.String, "db-password")
+	expectDiagnosticError(t, harness.validate(config), "")
+	state, diagnostics := harness.apply(config, harness.nullState())
+	expectDiagnosticError(t, diagnostics, "")
+	if harness.stringAttribute(state, "key") != "db-password" {
+		t.Fatalf("unexpected key %q", harness.stringAttribute(state, "key"))
+	}
+
+	// Unknown rules are only checked once they are known.
+	config["key"] = tftypes.NewValue(tftypes.String, "db password")
+	config["key_naming_policy"] = keyNamingPolicyValue(map[string]tftypes.Value{
+		"allowed_characters": tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+	})
+	expectDiagnosticError(t, harness.validate(config), "")
+
+	config["key_naming_policy"] = keyNamingPolicyValue(map[string]tftypes.Value{
+		"pattern": tftypes.NewValue(tftypes.String, "[a-z"),
+	})
+	expectDiagnosticError(t, harness.validate(config), "The key_naming_policy of the resource is invalid: pattern is not a valid regular expression")
+}
+
+func TestSecretsResourceKeyNamingPolicyOfTheResource(t *testing.T) {
+	store := newFakeBitwardenStore(fakeOrganizationID)
+	harness := newResourceHarness(t, "bitwarden-sm_secrets", store.newClient())
+
+	config := map[string]tftypes.Value{
+		"project_id": tftypes.NewValue(tftypes.String, validProjectUUID),
+		"secrets": tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, map[string]tftypes.Value{
+			"API_KEY":     tftypes.NewValue(tftypes.String, "value"),
+			"db-password": tftypes.NewValue(tftypes.String, "value"),
+		}),
+		"key_naming_policy": keyNamingPolicyValue(map[string]tftypes.Value{
+			"case_style": tftypes.NewValue(tftypes.String, caseStyleUpperSnake),
+			"max_length": tftypes.NewValue(tftypes.Number, 16),
+		}),
+	}
+	expectDiagnosticError(t, harness.validate(config), `The key "db-password" violates the key_naming_policy of the resource: it is not in upper_snake case. Use "DB_PASSWORD" instead.`)
+}
@@ -4,8 +4,10 @@ import (
 	"context"
 	"fmt"
 	"github.com/bitwarden/sdk-go"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/datasource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
@@ -28,6 +30,8 @@ type listSecretsDataSource struct {
 
 type listSecretsDataSourceModel struct {
 	Secrets []listSecretDataSourceModel `tfsdk:"secrets"`
+
+	Timeouts timeouts.Value `tfsdk:"timeouts"`
 }
 
 type listSecretDataSourceModel struct {
@@ -39,7 +43,7 @@ func (l *listSecretsDataSource) Metadata(_ context.Context, req datasource.Metad
 	resp.TypeName = req.ProviderTypeName + "_list_secrets"
 }
 
-func (l *listSecretsDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+func (l *listSecretsDataSource) Schema(ctx context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		Description:         "The list_secrets data source fetches all secrets accessible by the used machine account.",
 		MarkdownDescription: "The `list_secrets` data source fetches all secrets accessible by the used machine account.",
@@ -63,6 +67,9 @@ func (l *listSecretsDataSource) Schema(_ context.Context, _ datasource.SchemaReq
 				},
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx),
+		},
 	}
 }
 
@@ -110,7 +117,7 @@ func (l *listSecretsDataSource) Configure(ctx context.Context, req datasource.Co
 	tflog.Info(ctx, "Datasource Configured")
 }
 
-func (l *listSecretsDataSource) Read(ctx context.Context, _ datasource.ReadRequest, resp *datasource.ReadResponse) {
+func (l *listSecretsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
 	tflog.Info(ctx, "Reading List Secrets Datasource")
 
 	var state listSecretsDataSourceModel
@@ -123,7 +130,19 @@ func (l *listSecretsDataSource) Read(ctx context.Context, _ datasource.ReadReque
 		return
 	}
 
-	secrets, err := l.bitwardenClient.Secrets().List(l.organizationId)
+	var readTimeouts timeouts.Value
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("timeouts"), &readTimeouts)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, client, done := withClientTimeout(ctx, l.bitwardenClient, readTimeouts.Read, "read", "the list_secrets data source", &resp.Diagnostics)
+	defer done()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	secrets, err := client.Secrets().List(l.organizationId)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to List Secrets",
@@ -140,6 +159,8 @@ func (l *listSecretsDataSource) Read(ctx context.Context, _ datasource.ReadReque
 		state.Secrets = append(state.Secrets, secretState)
 	}
 
+	state.Timeouts = readTimeouts
+
 	// Set state
 	diags := resp.State.Set(ctx, &state)
 	resp.Diagnostics.Append(diags...)
@@ -9,7 +9,7 @@ import (
 )
 
 func TestAccDatasourceListSecretsZeroSecretsMachineAccountWithNoAccess(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	runProviderTest(t, resource.TestCase{
 		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
@@ -31,7 +31,7 @@ func TestAccDatasourceListSecretsOneSecret(t *testing.T) {
 	if err != nil {
 		t.Fatalf("Error creating bitwardenClient: %s", err)
 	}
-	resource.Test(t, resource.TestCase{
+	runProviderTest(t, resource.TestCase{
 		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		PreCheck: func() {
 			project, preCheckErr := bitwardenClient.Projects().Create(organizationId, projectName)
@@ -80,7 +80,7 @@ func TestAccDatasourceListSecretsTwoSecrets(t *testing.T) {
 	if err != nil {
 		t.Fatalf("Error creating bitwardenClient: %s", err)
 	}
-	resource.Test(t, resource.TestCase{
+	runProviderTest(t, resource.TestCase{
 		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		PreCheck: func() {
 			project, preCheckErr := bitwardenClient.Projects().Create(organizationId, projectName)
@@ -0,0 +1,236 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"time"
+)
+
+var (
+	// Ensure provider defined types fully satisfy framework interfaces.
+	_ ephemeral.EphemeralResource              = &machineAccountAccessTokenEphemeralResource{}
+	_ ephemeral.EphemeralResourceWithConfigure = &machineAccountAccessTokenEphemeralResource{}
+	_ ephemeral.EphemeralResourceWithClose     = &machineAccountAccessTokenEphemeralResource{}
+)
+
+const (
+	// ephemeralAccessTokenLifetime is the lifetime of ephemeral access tokens without an expiration date, so an
+	// access token which could not be revoked on close does not stay valid forever.
+	ephemeralAccessTokenLifetime = time.Hour
+
+	// ephemeralAccessTokenPrivateKey is the key of the private data which identifies the access token to revoke.
+	ephemeralAccessTokenPrivateKey = "access_token"
+)
+
+// NewMachineAccountAccessTokenEphemeralResource is a helper function to simplify the provider implementation.
+func NewMachineAccountAccessTokenEphemeralResource() ephemeral.EphemeralResource {
+	return &machineAccountAccessTokenEphemeralResource{}
+}
+
+// machineAccountAccessTokenEphemeralResource defines the ephemeral resource implementation.
+type machineAccountAccessTokenEphemeralResource struct {
+	apiClient func() (bitwardenAPIClient, error)
+}
+
+// machineAccountAccessTokenEphemeralResourceModel describes the ephemeral resource data model.
+type machineAccountAccessTokenEphemeralResourceModel struct {
+	ID               types.String `tfsdk:"id"`
+	MachineAccountID types.String `tfsdk:"machine_account_id"`
+	Name             types.String `tfsdk:"name"`
+	ExpiresAt        types.String `tfsdk:"expires_at"`
+	AccessToken      types.String `tfsdk:"access_token"`
+	CreationDate     types.String `tfsdk:"creation_date"`
+}
+
+// ephemeralAccessTokenPrivateData identifies the access token which is revoked when terraform closes the ephemeral
+// resource.
+type ephemeralAccessTokenPrivateData struct {
+	MachineAccountID string `json:"machine_account_id"`
+	ID               string `json:"id"`
+}
+
+func (r *machineAccountAccessTokenEphemeralResource) Metadata(_ context.Context, req ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_machine_account_access_token"
+}
+
+func (r *machineAccountAccessTokenEphemeralResource) Schema(_ context.Context, _ ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description:         "The machine_account_access_token ephemeral resource creates an access token for a machine account in Bitwarden Secrets Manager, which is never stored in the plan or the state. Terraform creates a new access token in every run which uses it and revokes it when it is no longer needed, so the access token can only be used during the run, e.g. in the provider configuration of another provider. Use the machine_account_access_token resource for access tokens which are handed over to workloads. Ephemeral resources require Terraform 1.10 or later.",
+		MarkdownDescription: "The `machine_account_access_token` ephemeral resource creates an access token for a machine account in Bitwarden Secrets Manager, which is never stored in the plan or the state. Terraform creates a new access token in every run which uses it and revokes it when it is no longer needed, so the access token can only be used during the run, e.g. in the provider configuration of another provider. Use the `machine_account_access_token` resource for access tokens which are handed over to workloads. Ephemeral resources require Terraform 1.10 or later.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description:         "String representation of the ID of the access token inside Bitwarden Secrets Manager.",
+				MarkdownDescription: "String representation of the `ID` of the access token inside Bitwarden Secrets Manager.",
+				Computed:            true,
+			},
+			"machine_account_id": schema.StringAttribute{
+				Description:         "String representation of the ID of the machine account for which the access token is created.",
+				MarkdownDescription: "String representation of the `ID` of the machine account for which the access token is created.",
+				Required:            true,
+				Validators: []validator.String{
+					stringUUIDValidate(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Description:         "String representation of the name of the access token.",
+				MarkdownDescription: "String representation of the name of the access token.",
+				Required:            true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"expires_at": schema.StringAttribute{
+				Description:         "Expiration date of the access token in RFC3339 format, e.g. 2030-01-31T00:00:00Z. The access token expires one hour after its creation if unset, in case it cannot be revoked at the end of the run.",
+				MarkdownDescription: "Expiration date of the access token in RFC3339 format, e.g. `2030-01-31T00:00:00Z`. The access token expires one hour after its creation if unset, in case it cannot be revoked at the end of the run.",
+				Optional:            true,
+				Computed:            true,
+				Validators: []validator.String{
+					stringRFC3339Validate(),
+				},
+			},
+			"access_token": schema.StringAttribute{
+				Description:         "The access token, which can be used to authenticate as the machine account.",
+				MarkdownDescription: "The access token, which can be used to authenticate as the machine account.",
+				Computed:            true,
+				Sensitive:           true,
+			},
+			"creation_date": schema.StringAttribute{
+				Description:         "String representation of the creation date of the access token.",
+				MarkdownDescription: "String representation of the creation date of the access token.",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (r *machineAccountAccessTokenEphemeralResource) Configure(ctx context.Context, req ephemeral.ConfigureRequest, resp *ephemeral.ConfigureResponse) {
+	// Add a nil check when handling BitwardenSecretsManagerProviderDataStruct because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	tflog.Info(ctx, "Configuring Machine Account Access Token Ephemeral Resource")
+	if req.ProviderData == nil {
+		tflog.Debug(ctx, "Skipping Ephemeral Resource Configuration because Provider has not been configured yet.")
+		return
+	}
+
+	providerDataStruct, ok := req.ProviderData.(BitwardenSecretsManagerProviderDataStruct)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Ephemeral Resource Configure Type",
+			fmt.Sprintf("Expected sdk.BitwardenClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	if providerDataStruct.apiClient == nil {
+		resp.Diagnostics.AddError(
+			"Client Not Initialized",
+			"The Bitwarden client was not properly initialized due to a missing Bitwarden API Client.",
+		)
+		return
+	}
+
+	r.apiClient = providerDataStruct.apiClient
+
+	tflog.Info(ctx, "Ephemeral Resource Configured")
+}
+
+func (r *machineAccountAccessTokenEphemeralResource) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+	var config machineAccountAccessTokenEphemeralResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	apiClient := configuredAPIClient(r.apiClient, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Ephemeral resources have no timeouts block, so the default timeout of the resources applies.
+	ctx, cancel := context.WithTimeout(ctx, defaultOperationTimeout)
+	defer cancel()
+
+	expiresAt := time.Now().Add(ephemeralAccessTokenLifetime).UTC().Truncate(time.Second)
+	if !config.ExpiresAt.IsNull() {
+		// The format was checked by the validator of the attribute.
+		expiresAt, _ = time.Parse(time.RFC3339, config.ExpiresAt.ValueString())
+		if !expiresAt.After(time.Now()) {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("expires_at"),
+				"Invalid Expiration Date",
+				"The expiration date of the access token must be in the future.",
+			)
+			return
+		}
+	}
+
+	accessToken, err := apiClient.MachineAccounts().CreateAccessToken(ctx, config.MachineAccountID.ValueString(), config.Name.ValueString(), &expiresAt)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Create Machine Account Access Token",
+			err.Error(),
+		)
+		return
+	}
+
+	privateData, err := json.Marshal(ephemeralAccessTokenPrivateData{MachineAccountID: config.MachineAccountID.ValueString(), ID: accessToken.ID})
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Store Machine Account Access Token",
+			err.Error(),
+		)
+		return
+	}
+	resp.Diagnostics.Append(resp.Private.SetKey(ctx, ephemeralAccessTokenPrivateKey, privateData)...)
+
+	config.ID = types.StringValue(accessToken.ID)
+	config.AccessToken = types.StringValue(accessToken.Token)
+	config.CreationDate = types.StringValue(accessToken.CreationDate)
+	if config.ExpiresAt.IsNull() {
+		config.ExpiresAt = types.StringValue(expiresAt.Format(time.RFC3339))
+	}
+
+	resp.Diagnostics.Append(resp.Result.Set(ctx, config)...)
+}
+
+func (r *machineAccountAccessTokenEphemeralResource) Close(ctx context.Context, req ephemeral.CloseRequest, resp *ephemeral.CloseResponse) {
+	data, diags := req.Private.GetKey(ctx, ephemeralAccessTokenPrivateKey)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() || data == nil {
+		return
+	}
+
+	var privateData ephemeralAccessTokenPrivateData
+	if err := json.Unmarshal(data, &privateData); err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read Machine Account Access Token",
+			err.Error(),
+		)
+		return
+	}
+
+	apiClient := configuredAPIClient(r.apiClient, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, defaultOperationTimeout)
+	defer cancel()
+
+	err := apiClient.MachineAccounts().RevokeAccessToken(ctx, privateData.MachineAccountID, privateData.ID)
+	if err != nil && !isNotFound(err) {
+		resp.Diagnostics.AddError(
+			"Unable to Revoke Machine Account Access Token",
+			fmt.Sprintf("The access token %s stays valid until it expires: %s", privateData.ID, err),
+		)
+	}
+}
@@ -0,0 +1,154 @@
+package provider
+
+import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// ephemeral returns a harness for the ephemeral resource of the same type name, which uses the configured provider of
+// h.
+func (h *resourceHarness) ephemeral() *resourceHarness {
+	h.t.Helper()
+
+	schemaResponse, err := h.server.GetProviderSchema(h.ctx, &tfprotov6.GetProviderSchemaRequest{})
+	if err != nil {
+		h.t.Fatalf("unexpected error: %s", err)
+	}
+	schema, ok := schemaResponse.EphemeralResourceSchemas[h.typeName]
+	if !ok {
+		h.t.Fatalf("unknown ephemeral resource type %s", h.typeName)
+	}
+
+	ephemeralHarness := *h
+	ephemeralHarness.schema = schema
+	ephemeralHarness.objectType = schema.ValueType().(tftypes.Object)
+	return &ephemeralHarness
+}
+
+// open opens an ephemeral resource with the given attributes, all other attributes are null. It returns the result,
+// the private data for close and the diagnostics.
+func (h *resourceHarness) open(attributes map[string]tftypes.Value) (*resourceState, []*tfprotov6.Diagnostic) {
+	h.t.Helper()
+
+	openResponse, err := h.server.OpenEphemeralResource(h.ctx, &tfprotov6.OpenEphemeralResourceRequest{
+		TypeName: h.typeName,
+		Config:   newDynamicValue(h.t, h.objectType, newObjectValue(h.objectType, attributes)),
+	})
+	if err != nil {
+		h.t.Fatalf("unexpected error: %s", err)
+	}
+	if diagnosticsHaveError(openResponse.Diagnostics) {
+		return h.nullState(), openResponse.Diagnostics
+	}
+	return h.newState(openResponse.Result, openResponse.Private), openResponse.Diagnostics
+}
+
+// close closes an ephemeral resource which was opened with open.
+func (h *resourceHarness) close(opened *resourceState) []*tfprotov6.Diagnostic {
+	h.t.Helper()
+
+	closeResponse, err := h.server.CloseEphemeralResource(h.ctx, &tfprotov6.CloseEphemeralResourceRequest{
+		TypeName: h.typeName,
+		Private:  opened.private,
+	})
+	if err != nil {
+		h.t.Fatalf("unexpected error: %s", err)
+	}
+	return closeResponse.Diagnostics
+}
+
+func TestMachineAccountAccessTokenEphemeralResource(t *testing.T) {
+	standIn := newMachineAccountStandIn(t)
+	account, err := standIn.client().MachineAccounts().Create(context.Background(), fakeOrganizationID, "ci-pipeline")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	harness := newAPIResourceHarness(t, "bitwarden-sm_machine_account_access_token", standIn.apiStandIn).ephemeral()
+
+	opened, diagnostics := harness.open(map[string]tftypes.Value{
+		"machine_account_id": tftypes.NewValue(tftypes.String, account.ID),
+		"name":               tftypes.NewValue(tftypes.String, "terraform-run"),
+	})
+	expectDiagnosticError(t, diagnostics, "")
+
+	id := harness.stringAttribute(opened, "id")
+	if accessToken := harness.stringAttribute(opened, "access_token"); !strings.HasPrefix(accessToken, "0."+id+".stand-in-client-secret:") {
+		t.Fatalf("unexpected access token %q", accessToken)
+	}
+	if harness.stringAttribute(opened, "creation_date") != "2025-01-01T00:00:00Z" {
+		t.Fatalf("unexpected creation date %q", harness.stringAttribute(opened, "creation_date"))
+	}
+
+	// Without an expiration date, the access token expires after an hour in case it cannot be revoked.
+	expiresAt, err := time.Parse(time.RFC3339, harness.stringAttribute(opened, "expires_at"))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if remaining := time.Until(expiresAt); remaining < 59*time.Minute || remaining > ephemeralAccessTokenLifetime {
+		t.Fatalf("expected the access token to expire in an hour, got %s", expiresAt)
+	}
+	request := standIn.tokenRequests[id]
+	if request.ExpireAt == nil || !request.ExpireAt.Equal(expiresAt) {
+		t.Fatalf("expected the expiration date %s to be sent, got %v", expiresAt, request.ExpireAt)
+	}
+
+	expectDiagnosticError(t, harness.close(opened), "")
+	if len(standIn.accessTokens[account.ID]) != 0 {
+		t.Fatalf("expected the access token to be revoked, got %+v", standIn.accessTokens[account.ID])
+	}
+
+	// Closing again, e.g. after the access token was revoked outside of terraform, succeeds.
+	expectDiagnosticError(t, harness.close(opened), "")
+}
+
+func TestMachineAccountAccessTokenEphemeralResourceExpiration(t *testing.T) {
+	standIn := newMachineAccountStandIn(t)
+	account, err := standIn.client().MachineAccounts().Create(context.Background(), fakeOrganizationID, "ci-pipeline")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	harness := newAPIResourceHarness(t, "bitwarden-sm_machine_account_access_token", standIn.apiStandIn).ephemeral()
+
+	opened, diagnostics := harness.open(map[string]tftypes.Value{
+		"machine_account_id": tftypes.NewValue(tftypes.String, account.ID),
+		"name":               tftypes.NewValue(tftypes.String, "terraform-run"),
+		"expires_at":         tftypes.NewValue(tftypes.String, "2099-01-31T00:00:00Z"),
+	})
+	expectDiagnosticError(t, diagnostics, "")
+	if harness.stringAttribute(opened, "expires_at") != "2099-01-31T00:00:00Z" {
+		t.Fatalf("expected the configured expiration date, got %q", harness.stringAttribute(opened, "expires_at"))
+	}
+
+	_, diagnostics = harness.open(map[string]tftypes.Value{
+		"machine_account_id": tftypes.NewValue(tftypes.String, account.ID),
+		"name":               tftypes.NewValue(tftypes.String, "terraform-run"),
+		"expires_at":         tftypes.NewValue(tftypes.String, "2020-01-31T00:00:00Z"),
+	})
+	expectDiagnosticError(t, diagnostics, "The expiration date of the access token must be in the future.")
+	if len(standIn.accessTokens[account.ID]) != 1 {
+		t.Fatalf("expected no access token to be created for a past expiration date, got %+v", standIn.accessTokens[account.ID])
+	}
+}
+
+func TestMachineAccountAccessTokenEphemeralResourceRevokeFailure(t *testing.T) {
+	standIn := newMachineAccountStandIn(t)
+	account, err := standIn.client().MachineAccounts().Create(context.Background(), fakeOrganizationID, "ci-pipeline")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	harness := newAPIResourceHarness(t, "bitwarden-sm_machine_account_access_token", standIn.apiStandIn).ephemeral()
+
+	opened, diagnostics := harness.open(map[string]tftypes.Value{
+		"machine_account_id": tftypes.NewValue(tftypes.String, account.ID),
+		"name":               tftypes.NewValue(tftypes.String, "terraform-run"),
+	})
+	expectDiagnosticError(t, diagnostics, "")
+
+	standIn.failNext("POST /service-accounts/"+account.ID+"/access-tokens/revoke", http.StatusInternalServerError)
+	expectDiagnosticError(t, harness.close(opened), "stays valid until it expires")
+}
@@ -0,0 +1,295 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"time"
+)
+
+var (
+	// Ensure provider defined types fully satisfy framework interfaces.
+	_ resource.Resource              = &machineAccountAccessTokenResource{}
+	_ resource.ResourceWithConfigure = &machineAccountAccessTokenResource{}
+)
+
+// NewMachineAccountAccessTokenResource is a helper function to simplify the provider implementation.
+func NewMachineAccountAccessTokenResource() resource.Resource {
+	return &machineAccountAccessTokenResource{}
+}
+
+// machineAccountAccessTokenResource defines the resource implementation.
+type machineAccountAccessTokenResource struct {
+	apiClient func() (bitwardenAPIClient, error)
+}
+
+// machineAccountAccessTokenResourceModel describes the resource data model.
+type machineAccountAccessTokenResourceModel struct {
+	ID               types.String `tfsdk:"id"`
+	MachineAccountID types.String `tfsdk:"machine_account_id"`
+	Name             types.String `tfsdk:"name"`
+	ExpiresAt        types.String `tfsdk:"expires_at"`
+	AccessToken      types.String `tfsdk:"access_token"`
+	CreationDate     types.String `tfsdk:"creation_date"`
+
+	Timeouts timeouts.Value `tfsdk:"timeouts"`
+}
+
+func (r *machineAccountAccessTokenResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_machine_account_access_token"
+}
+
+func (r *machineAccountAccessTokenResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description:         "The machine_account_access_token resource creates access tokens for machine accounts in Bitwarden Secrets Manager. The access token is only returned on creation, all changes apart from the timeouts replace the access token. Expired or revoked access tokens are removed from the state, so the next apply creates a new access token. The access token is stored in the state, use the machine_account_access_token ephemeral resource for access tokens which are only needed during a Terraform run.",
+		MarkdownDescription: "The `machine_account_access_token` resource creates access tokens for machine accounts in Bitwarden Secrets Manager. The access token is only returned on creation, all changes apart from the timeouts replace the access token. Expired or revoked access tokens are removed from the state, so the next apply creates a new access token. The access token is stored in the state, use the `machine_account_access_token` ephemeral resource for access tokens which are only needed during a Terraform run.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description:         "String representation of the ID of the access token inside Bitwarden Secrets Manager.",
+				MarkdownDescription: "String representation of the `ID` of the access token inside Bitwarden Secrets Manager.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"machine_account_id": schema.StringAttribute{
+				Description:         "String representation of the ID of the machine account for which the access token is created.",
+				MarkdownDescription: "String representation of the `ID` of the machine account for which the access token is created.",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringUUIDValidate(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Description:         "String representation of the name of the access token.",
+				MarkdownDescription: "String representation of the name of the access token.",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"expires_at": schema.StringAttribute{
+				Description:         "Expiration date of the access token in RFC3339 format, e.g. 2030-01-31T00:00:00Z. The access token never expires if unset.",
+				MarkdownDescription: "Expiration date of the access token in RFC3339 format, e.g. `2030-01-31T00:00:00Z`. The access token never expires if unset.",
+				Optional:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringRFC3339Validate(),
+				},
+			},
+			"access_token": schema.StringAttribute{
+				Description:         "The access token, which can be used to authenticate as the machine account, e.g. in the provider configuration of a downstream workload.",
+				MarkdownDescription: "The access token, which can be used to authenticate as the machine account, e.g. in the provider configuration of a downstream workload.",
+				Computed:            true,
+				Sensitive:           true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"creation_date": schema.StringAttribute{
+				Description:         "String representation of the creation date of the access token.",
+				MarkdownDescription: "String representation of the creation date of the access token.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Update: true,
+				Delete: true,
+			}),
+		},
+	}
+}
+
+func (r *machineAccountAccessTokenResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Add a nil check when handling BitwardenSecretsManagerProviderDataStruct because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	tflog.Info(ctx, "Configuring Machine Account Access Token Resource")
+	if req.ProviderData == nil {
+		tflog.Debug(ctx, "Skipping Resource Configuration because Provider has not been configured yet.")
+		return
+	}
+
+	providerDataStruct, ok := req.ProviderData.(BitwardenSecretsManagerProviderDataStruct)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected sdk.BitwardenClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	if providerDataStruct.apiClient == nil {
+		resp.Diagnostics.AddError(
+			"Client Not Initialized",
+			"The Bitwarden client was not properly initialized due to a missing Bitwarden API Client.",
+		)
+		return
+	}
+
+	r.apiClient = providerDataStruct.apiClient
+
+	tflog.Info(ctx, "Resource Configured")
+}
+
+func (r *machineAccountAccessTokenResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan machineAccountAccessTokenResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	apiClient := configuredAPIClient(r.apiClient, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, done := withOperationTimeout(ctx, plan.Timeouts.Create, "create", "the machine account access token", &resp.Diagnostics)
+	defer done()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var expiresAt *time.Time
+	if !plan.ExpiresAt.IsNull() {
+		// The format was checked by the validator of the attribute.
+		expiration, _ := time.Parse(time.RFC3339, plan.ExpiresAt.ValueString())
+		if !expiration.After(time.Now()) {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("expires_at"),
+				"Invalid Expiration Date",
+				"The expiration date of the access token must be in the future.",
+			)
+			return
+		}
+		expiresAt = &expiration
+	}
+
+	accessToken, err := apiClient.MachineAccounts().CreateAccessToken(ctx, plan.MachineAccountID.ValueString(), plan.Name.ValueString(), expiresAt)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Create Machine Account Access Token",
+			err.Error(),
+		)
+		return
+	}
+
+	plan.ID = types.StringValue(accessToken.ID)
+	plan.AccessToken = types.StringValue(accessToken.Token)
+	plan.CreationDate = types.StringValue(accessToken.CreationDate)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+func (r *machineAccountAccessTokenResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	tflog.Info(ctx, "Reading Machine Account Access Token Resource")
+
+	var state machineAccountAccessTokenResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	apiClient := configuredAPIClient(r.apiClient, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, done := withOperationTimeout(ctx, state.Timeouts.Read, "read", "the machine account access token", &resp.Diagnostics)
+	defer done()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	accessToken, err := apiClient.MachineAccounts().GetAccessToken(ctx, state.MachineAccountID.ValueString(), state.ID.ValueString())
+	if isNotFound(err) {
+		tflog.Warn(ctx, "Machine account of the access token was deleted outside of terraform", map[string]any{"id": state.ID.ValueString()})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read Machine Account Access Token with id: "+state.ID.ValueString(),
+			err.Error(),
+		)
+		return
+	}
+	if accessToken == nil {
+		tflog.Warn(ctx, "Access token was revoked outside of terraform", map[string]any{"id": state.ID.ValueString()})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if accessToken.ExpiresAt != nil && !accessToken.ExpiresAt.After(time.Now()) {
+		tflog.Warn(ctx, "Access token has expired", map[string]any{"id": state.ID.ValueString()})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	// The access token itself and the configured expiration date are kept as they are, the API does not return
+	// the access token and might format the expiration date differently.
+	state.Name = types.StringValue(accessToken.Name)
+	state.CreationDate = types.StringValue(accessToken.CreationDate)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (r *machineAccountAccessTokenResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// All other configurable attributes require replacement, so only the timeouts change without a request.
+	var plan machineAccountAccessTokenResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+func (r *machineAccountAccessTokenResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state machineAccountAccessTokenResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	apiClient := configuredAPIClient(r.apiClient, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, done := withOperationTimeout(ctx, state.Timeouts.Delete, "delete", "the machine account access token", &resp.Diagnostics)
+	defer done()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := apiClient.MachineAccounts().RevokeAccessToken(ctx, state.MachineAccountID.ValueString(), state.ID.ValueString())
+	if err != nil && !isNotFound(err) {
+		resp.Diagnostics.AddError(
+			"Unable to Revoke Machine Account Access Token",
+			err.Error(),
+		)
+	}
+}
@@ -0,0 +1,283 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var (
+	// Ensure provider defined types fully satisfy framework interfaces.
+	_ resource.Resource                = &machineAccountResource{}
+	_ resource.ResourceWithConfigure   = &machineAccountResource{}
+	_ resource.ResourceWithImportState = &machineAccountResource{}
+)
+
+// NewMachineAccountResource is a helper function to simplify the provider implementation.
+func NewMachineAccountResource() resource.Resource {
+	return &machineAccountResource{}
+}
+
+// machineAccountResource defines the resource implementation.
+type machineAccountResource struct {
+	apiClient      func() (bitwardenAPIClient, error)
+	organizationId string
+}
+
+// machineAccountResourceModel describes the resource data model.
+type machineAccountResourceModel struct {
+	ID             types.String `tfsdk:"id"`
+	Name           types.String `tfsdk:"name"`
+	OrganizationID types.String `tfsdk:"organization_id"`
+	CreationDate   types.String `tfsdk:"creation_date"`
+	RevisionDate   types.String `tfsdk:"revision_date"`
+
+	Timeouts timeouts.Value `tfsdk:"timeouts"`
+}
+
+func (r *machineAccountResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_machine_account"
+}
+
+func (r *machineAccountResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description:         "The machine_account resource manages machine accounts in Bitwarden Secrets Manager. Machine accounts are created in the organization of the provider configuration. Use the project_machine_account_access resource to grant them access to projects and the machine_account_access_token resource to create access tokens.",
+		MarkdownDescription: "The `machine_account` resource manages machine accounts in Bitwarden Secrets Manager. Machine accounts are created in the organization of the provider configuration. Use the `project_machine_account_access` resource to grant them access to projects and the `machine_account_access_token` resource to create access tokens.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description:         "String representation of the ID of the machine account inside Bitwarden Secrets Manager.",
+				MarkdownDescription: "String representation of the `ID` of the machine account inside Bitwarden Secrets Manager.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Description:         "String representation of the name of the machine account.",
+				MarkdownDescription: "String representation of the name of the machine account.",
+				Required:            true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"organization_id": schema.StringAttribute{
+				Description:         "String representation of the ID of the organization to which the machine account belongs.",
+				MarkdownDescription: "String representation of the `ID` of the organization to which the machine account belongs.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"creation_date": schema.StringAttribute{
+				Description:         "String representation of the creation date of the machine account.",
+				MarkdownDescription: "String representation of the creation date of the machine account.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"revision_date": schema.StringAttribute{
+				Description:         "String representation of the revision date of the machine account.",
+				MarkdownDescription: "String representation of the revision date of the machine account.",
+				Computed:            true,
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Update: true,
+				Delete: true,
+			}),
+		},
+	}
+}
+
+func (r *machineAccountResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Add a nil check when handling BitwardenSecretsManagerProviderDataStruct because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	tflog.Info(ctx, "Configuring Machine Account Resource")
+	if req.ProviderData == nil {
+		tflog.Debug(ctx, "Skipping Resource Configuration because Provider has not been configured yet.")
+		return
+	}
+
+	providerDataStruct, ok := req.ProviderData.(BitwardenSecretsManagerProviderDataStruct)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected sdk.BitwardenClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	if providerDataStruct.apiClient == nil {
+		resp.Diagnostics.AddError(
+			"Client Not Initialized",
+			"The Bitwarden client was not properly initialized due to a missing Bitwarden API Client.",
+		)
+		return
+	}
+
+	if providerDataStruct.organizationId == "" {
+		resp.Diagnostics.AddError(
+			"Client Not Initialized",
+			"The Bitwarden client was not properly initialized due to an empty Organization ID.",
+		)
+		return
+	}
+
+	r.apiClient = providerDataStruct.apiClient
+	r.organizationId = providerDataStruct.organizationId
+
+	tflog.Info(ctx, "Resource Configured")
+}
+
+func (r *machineAccountResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan machineAccountResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	apiClient := configuredAPIClient(r.apiClient, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, done := withOperationTimeout(ctx, plan.Timeouts.Create, "create", "the machine account", &resp.Diagnostics)
+	defer done()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	account, err := apiClient.MachineAccounts().Create(ctx, r.organizationId, plan.Name.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Create Machine Account",
+			err.Error(),
+		)
+		return
+	}
+
+	setMachineAccountModel(&plan, account)
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+func (r *machineAccountResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	tflog.Info(ctx, "Reading Machine Account Resource")
+
+	var state machineAccountResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	apiClient := configuredAPIClient(r.apiClient, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, done := withOperationTimeout(ctx, state.Timeouts.Read, "read", "the machine account", &resp.Diagnostics)
+	defer done()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	account, err := apiClient.MachineAccounts().Get(ctx, state.ID.ValueString())
+	if isNotFound(err) {
+		tflog.Warn(ctx, "Machine account was deleted outside of terraform", map[string]any{"id": state.ID.ValueString()})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read Machine Account with id: "+state.ID.ValueString(),
+			err.Error(),
+		)
+		return
+	}
+
+	setMachineAccountModel(&state, account)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (r *machineAccountResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan machineAccountResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	apiClient := configuredAPIClient(r.apiClient, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, done := withOperationTimeout(ctx, plan.Timeouts.Update, "update", "the machine account", &resp.Diagnostics)
+	defer done()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	account, err := apiClient.MachineAccounts().Update(ctx, plan.ID.ValueString(), plan.Name.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Update Machine Account",
+			err.Error(),
+		)
+		return
+	}
+
+	setMachineAccountModel(&plan, account)
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+func (r *machineAccountResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state machineAccountResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	apiClient := configuredAPIClient(r.apiClient, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, done := withOperationTimeout(ctx, state.Timeouts.Delete, "delete", "the machine account", &resp.Diagnostics)
+	defer done()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := apiClient.MachineAccounts().Delete(ctx, state.ID.ValueString()); err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Delete Machine Account",
+			err.Error(),
+		)
+	}
+}
+
+func (r *machineAccountResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// Retrieve import ID and save to id attribute
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+func setMachineAccountModel(model *machineAccountResourceModel, account *machineAccount) {
+	model.ID = types.StringValue(account.ID)
+	model.Name = types.StringValue(account.Name)
+	model.OrganizationID = types.StringValue(account.OrganizationID)
+	model.CreationDate = types.StringValue(account.CreationDate)
+	model.RevisionDate = types.StringValue(account.RevisionDate)
+}
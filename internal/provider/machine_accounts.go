@@ -0,0 +1,251 @@
+package provider
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// machineAccount is a machine account of Bitwarden Secrets Manager with its name decrypted.
+type machineAccount struct {
+	ID             string
+	OrganizationID string
+	Name           string
+	CreationDate   string
+	RevisionDate   string
+}
+
+// machineAccountAccessToken is an access token of a machine account with its name decrypted. Token is only known
+// right after the access token was created, the API does not return it again.
+type machineAccountAccessToken struct {
+	ID           string
+	Name         string
+	ExpiresAt    *time.Time
+	CreationDate string
+	Token        string
+}
+
+// machineAccountsInterface manages machine accounts and their access tokens. GetAccessToken returns nil if the
+// access token does not exist or was revoked.
+type machineAccountsInterface interface {
+	Create(ctx context.Context, organizationID string, name string) (*machineAccount, error)
+	Get(ctx context.Context, id string) (*machineAccount, error)
+	Update(ctx context.Context, id string, name string) (*machineAccount, error)
+	Delete(ctx context.Context, id string) error
+	CreateAccessToken(ctx context.Context, machineAccountID string, name string, expiresAt *time.Time) (*machineAccountAccessToken, error)
+	GetAccessToken(ctx context.Context, machineAccountID string, id string) (*machineAccountAccessToken, error)
+	RevokeAccessToken(ctx context.Context, machineAccountID string, id string) error
+}
+
+type machineAccountRequest struct {
+	Name string `json:"name"`
+}
+
+type machineAccountResponse struct {
+	ID             string `json:"id"`
+	OrganizationID string `json:"organizationId"`
+	Name           string `json:"name"`
+	CreationDate   string `json:"creationDate"`
+	RevisionDate   string `json:"revisionDate"`
+}
+
+type bulkDeleteResponse struct {
+	Data []struct {
+		ID    string `json:"id"`
+		Error string `json:"error"`
+	} `json:"data"`
+}
+
+type accessTokenCreateRequest struct {
+	Name             string     `json:"name"`
+	EncryptedPayload string     `json:"encryptedPayload"`
+	Key              string     `json:"key"`
+	ExpireAt         *time.Time `json:"expireAt"`
+}
+
+type accessTokenResponse struct {
+	ID           string     `json:"id"`
+	Name         string     `json:"name"`
+	ExpireAt     *time.Time `json:"expireAt"`
+	CreationDate string     `json:"creationDate"`
+	ClientSecret string     `json:"clientSecret"`
+}
+
+type accessTokenListResponse struct {
+	Data []accessTokenResponse `json:"data"`
+}
+
+type accessTokenRevokeRequest struct {
+	IDs []string `json:"ids"`
+}
+
+// machineAccounts implements machineAccountsInterface. Names of machine accounts and access tokens are encrypted
+// with the organization key before they are sent to the API.
+type machineAccounts struct {
+	client *httpAPIClient
+}
+
+func machineAccountPath(id string) string {
+	return "/service-accounts/" + url.PathEscape(id)
+}
+
+func (m *machineAccounts) Create(ctx context.Context, organizationID string, name string) (*machineAccount, error) {
+	key, err := m.client.organizationKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+	encryptedName, err := encryptString(key, []byte(name))
+	if err != nil {
+		return nil, err
+	}
+
+	var response machineAccountResponse
+	path := fmt.Sprintf("/organizations/%s/service-accounts", url.PathEscape(organizationID))
+	if err := m.client.do(ctx, http.MethodPost, path, machineAccountRequest{Name: encryptedName}, &response); err != nil {
+		return nil, err
+	}
+	return m.decryptMachineAccount(key, response)
+}
+
+func (m *machineAccounts) Get(ctx context.Context, id string) (*machineAccount, error) {
+	key, err := m.client.organizationKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var response machineAccountResponse
+	if err := m.client.do(ctx, http.MethodGet, machineAccountPath(id), nil, &response); err != nil {
+		return nil, err
+	}
+	return m.decryptMachineAccount(key, response)
+}
+
+func (m *machineAccounts) Update(ctx context.Context, id string, name string) (*machineAccount, error) {
+	key, err := m.client.organizationKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+	encryptedName, err := encryptString(key, []byte(name))
+	if err != nil {
+		return nil, err
+	}
+
+	var response machineAccountResponse
+	if err := m.client.do(ctx, http.MethodPut, machineAccountPath(id), machineAccountRequest{Name: encryptedName}, &response); err != nil {
+		return nil, err
+	}
+	return m.decryptMachineAccount(key, response)
+}
+
+func (m *machineAccounts) Delete(ctx context.Context, id string) error {
+	var response bulkDeleteResponse
+	if err := m.client.do(ctx, http.MethodPost, "/service-accounts/delete", []string{id}, &response); err != nil {
+		return err
+	}
+	for _, result := range response.Data {
+		if result.Error != "" {
+			return fmt.Errorf("unable to delete machine account %s: %s", result.ID, result.Error)
+		}
+	}
+	return nil
+}
+
+// CreateAccessToken creates an access token for the machine account. Like the Bitwarden clients, it generates a
+// random encryption key for the access token, which is used to share the organization key with the access token.
+func (m *machineAccounts) CreateAccessToken(ctx context.Context, machineAccountID string, name string, expiresAt *time.Time) (*machineAccountAccessToken, error) {
+	key, err := m.client.organizationKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	secret := make([]byte, 16)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+	encodedSecret := base64.StdEncoding.EncodeToString(secret)
+
+	payload, err := json.Marshal(map[string]string{"encryptionKey": base64.StdEncoding.EncodeToString(key.bytes())})
+	if err != nil {
+		return nil, err
+	}
+	request := accessTokenCreateRequest{ExpireAt: expiresAt}
+	if request.Name, err = encryptString(key, []byte(name)); err != nil {
+		return nil, err
+	}
+	if request.EncryptedPayload, err = encryptString(deriveShareableKey(secret, "accesstoken", "sm-access-token"), payload); err != nil {
+		return nil, err
+	}
+	if request.Key, err = encryptString(key, []byte(encodedSecret)); err != nil {
+		return nil, err
+	}
+
+	var response accessTokenResponse
+	if err := m.client.do(ctx, http.MethodPost, machineAccountPath(machineAccountID)+"/access-tokens", request, &response); err != nil {
+		return nil, err
+	}
+	if response.ClientSecret == "" {
+		return nil, fmt.Errorf("API response for access token %s does not contain the client secret", response.ID)
+	}
+
+	accessToken, err := m.decryptAccessToken(key, response)
+	if err != nil {
+		return nil, err
+	}
+	accessToken.Token = fmt.Sprintf("0.%s.%s:%s", response.ID, response.ClientSecret, encodedSecret)
+	return accessToken, nil
+}
+
+func (m *machineAccounts) GetAccessToken(ctx context.Context, machineAccountID string, id string) (*machineAccountAccessToken, error) {
+	key, err := m.client.organizationKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var response accessTokenListResponse
+	if err := m.client.do(ctx, http.MethodGet, machineAccountPath(machineAccountID)+"/access-tokens", nil, &response); err != nil {
+		return nil, err
+	}
+	for _, accessToken := range response.Data {
+		if accessToken.ID == id {
+			return m.decryptAccessToken(key, accessToken)
+		}
+	}
+	return nil, nil
+}
+
+func (m *machineAccounts) RevokeAccessToken(ctx context.Context, machineAccountID string, id string) error {
+	request := accessTokenRevokeRequest{IDs: []string{id}}
+	return m.client.do(ctx, http.MethodPost, machineAccountPath(machineAccountID)+"/access-tokens/revoke", request, nil)
+}
+
+func (m *machineAccounts) decryptMachineAccount(key symmetricKey, response machineAccountResponse) (*machineAccount, error) {
+	name, err := decryptString(key, response.Name)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decrypt the name of machine account %s: %w", response.ID, err)
+	}
+	return &machineAccount{
+		ID:             response.ID,
+		OrganizationID: response.OrganizationID,
+		Name:           string(name),
+		CreationDate:   response.CreationDate,
+		RevisionDate:   response.RevisionDate,
+	}, nil
+}
+
+func (m *machineAccounts) decryptAccessToken(key symmetricKey, response accessTokenResponse) (*machineAccountAccessToken, error) {
+	name, err := decryptString(key, response.Name)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decrypt the name of access token %s: %w", response.ID, err)
+	}
+	return &machineAccountAccessToken{
+		ID:           response.ID,
+		Name:         string(name),
+		ExpiresAt:    response.ExpireAt,
+		CreationDate: response.CreationDate,
+	}, nil
+}
@@ -0,0 +1,361 @@
+package provider
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"github.com/bitwarden/sdk-go"
+	"github.com/google/uuid"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// machineAccountStandIn stores machine accounts and their access tokens in memory and serves the machine account
+// endpoints. Names are stored encrypted, just like the API stores them.
+type machineAccountStandIn struct {
+	*apiStandIn
+
+	mu              sync.Mutex
+	machineAccounts map[string]machineAccountResponse
+	accessTokens    map[string][]accessTokenResponse
+	tokenRequests   map[string]accessTokenCreateRequest
+}
+
+func newMachineAccountStandIn(t *testing.T) *machineAccountStandIn {
+	standIn := &machineAccountStandIn{
+		apiStandIn:      newAPIStandIn(t),
+		machineAccounts: map[string]machineAccountResponse{},
+		accessTokens:    map[string][]accessTokenResponse{},
+		tokenRequests:   map[string]accessTokenCreateRequest{},
+	}
+
+	standIn.handle("POST /organizations/{orgId}/service-accounts", func(w http.ResponseWriter, r *http.Request) {
+		var request machineAccountRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil || request.Name == "" {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"message": "The Name field is required."})
+			return
+		}
+		standIn.mu.Lock()
+		defer standIn.mu.Unlock()
+		account := machineAccountResponse{
+			ID:             uuid.NewString(),
+			OrganizationID: r.PathValue("orgId"),
+			Name:           request.Name,
+			CreationDate:   "2025-01-01T00:00:00Z",
+			RevisionDate:   "2025-01-01T00:00:00Z",
+		}
+		standIn.machineAccounts[account.ID] = account
+		writeJSON(w, http.StatusOK, account)
+	})
+	standIn.handle("GET /service-accounts/{id}", func(w http.ResponseWriter, r *http.Request) {
+		standIn.mu.Lock()
+		defer standIn.mu.Unlock()
+		account, ok := standIn.machineAccounts[r.PathValue("id")]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		writeJSON(w, http.StatusOK, account)
+	})
+	standIn.handle("PUT /service-accounts/{id}", func(w http.ResponseWriter, r *http.Request) {
+		var request machineAccountRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil || request.Name == "" {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"message": "The Name field is required."})
+			return
+		}
+		standIn.mu.Lock()
+		defer standIn.mu.Unlock()
+		account, ok := standIn.machineAccounts[r.PathValue("id")]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		account.Name = request.Name
+		account.RevisionDate = "2025-01-02T00:00:00Z"
+		standIn.machineAccounts[account.ID] = account
+		writeJSON(w, http.StatusOK, account)
+	})
+	standIn.handle("POST /service-accounts/delete", func(w http.ResponseWriter, r *http.Request) {
+		var ids []string
+		if err := json.NewDecoder(r.Body).Decode(&ids); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		standIn.mu.Lock()
+		defer standIn.mu.Unlock()
+		results := []map[string]string{}
+		for _, id := range ids {
+			if _, ok := standIn.machineAccounts[id]; !ok {
+				results = append(results, map[string]string{"id": id, "error": "access denied"})
+				continue
+			}
+			delete(standIn.machineAccounts, id)
+			delete(standIn.accessTokens, id)
+			results = append(results, map[string]string{"id": id, "error": ""})
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"data": results})
+	})
+	standIn.handle("POST /service-accounts/{id}/access-tokens", func(w http.ResponseWriter, r *http.Request) {
+		var request accessTokenCreateRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil || request.Name == "" || request.EncryptedPayload == "" || request.Key == "" {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"message": "Invalid access token."})
+			return
+		}
+		standIn.mu.Lock()
+		defer standIn.mu.Unlock()
+		if _, ok := standIn.machineAccounts[r.PathValue("id")]; !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		accessToken := accessTokenResponse{
+			ID:           uuid.NewString(),
+			Name:         request.Name,
+			ExpireAt:     request.ExpireAt,
+			CreationDate: "2025-01-01T00:00:00Z",
+		}
+		standIn.accessTokens[r.PathValue("id")] = append(standIn.accessTokens[r.PathValue("id")], accessToken)
+		standIn.tokenRequests[accessToken.ID] = request
+
+		accessToken.ClientSecret = "stand-in-client-secret"
+		writeJSON(w, http.StatusOK, accessToken)
+	})
+	standIn.handle("GET /service-accounts/{id}/access-tokens", func(w http.ResponseWriter, r *http.Request) {
+		standIn.mu.Lock()
+		defer standIn.mu.Unlock()
+		if _, ok := standIn.machineAccounts[r.PathValue("id")]; !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"data": append([]accessTokenResponse{}, standIn.accessTokens[r.PathValue("id")]...)})
+	})
+	standIn.handle("POST /service-accounts/{id}/access-tokens/revoke", func(w http.ResponseWriter, r *http.Request) {
+		var request accessTokenRevokeRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		standIn.mu.Lock()
+		defer standIn.mu.Unlock()
+		kept := []accessTokenResponse{}
+		for _, accessToken := range standIn.accessTokens[r.PathValue("id")] {
+			revoked := false
+			for _, id := range request.IDs {
+				revoked = revoked || accessToken.ID == id
+			}
+			if !revoked {
+				kept = append(kept, accessToken)
+			}
+		}
+		standIn.accessTokens[r.PathValue("id")] = kept
+		w.WriteHeader(http.StatusOK)
+	})
+
+	return standIn
+}
+
+func TestMachineAccountLifecycle(t *testing.T) {
+	ctx := context.Background()
+	standIn := newMachineAccountStandIn(t)
+	machineAccounts := standIn.client().MachineAccounts()
+
+	account, err := machineAccounts.Create(ctx, validProjectUUID, "ci-pipeline")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if account.Name != "ci-pipeline" || account.OrganizationID != validProjectUUID {
+		t.Fatalf("unexpected machine account: %+v", account)
+	}
+
+	// The API must only receive the encrypted name.
+	stored := standIn.machineAccounts[account.ID].Name
+	if stored == "ci-pipeline" {
+		t.Fatal("expected the name to be encrypted")
+	}
+	if name, err := decryptString(standIn.orgKey, stored); err != nil || string(name) != "ci-pipeline" {
+		t.Fatalf("expected the name to be encrypted with the organization key, got %q: %v", name, err)
+	}
+
+	account, err = machineAccounts.Update(ctx, account.ID, "deploy-pipeline")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if account.Name != "deploy-pipeline" || account.RevisionDate != "2025-01-02T00:00:00Z" {
+		t.Fatalf("unexpected machine account: %+v", account)
+	}
+
+	account, err = machineAccounts.Get(ctx, account.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if account.Name != "deploy-pipeline" {
+		t.Fatalf("unexpected machine account: %+v", account)
+	}
+
+	if err := machineAccounts.Delete(ctx, account.ID); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, err := machineAccounts.Get(ctx, account.ID); !isNotFound(err) {
+		t.Fatalf("expected a not found error, got: %v", err)
+	}
+	if err := machineAccounts.Delete(ctx, account.ID); err == nil {
+		t.Fatal("expected an error when deleting a missing machine account")
+	}
+}
+
+func TestMachineAccountAccessTokenLifecycle(t *testing.T) {
+	ctx := context.Background()
+	standIn := newMachineAccountStandIn(t)
+	machineAccounts := standIn.client().MachineAccounts()
+
+	account, err := machineAccounts.Create(ctx, validProjectUUID, "ci-pipeline")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expiresAt := time.Date(2030, 1, 31, 0, 0, 0, 0, time.UTC)
+	accessToken, err := machineAccounts.CreateAccessToken(ctx, account.ID, "deploy", &expiresAt)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if accessToken.Name != "deploy" || accessToken.ExpiresAt == nil || !accessToken.ExpiresAt.Equal(expiresAt) {
+		t.Fatalf("unexpected access token: %+v", accessToken)
+	}
+
+	credentials, err := parseAccessToken(accessToken.Token)
+	if err != nil {
+		t.Fatalf("expected a valid access token, got: %s", err)
+	}
+	if credentials.AccessTokenID != accessToken.ID || credentials.ClientSecret != "stand-in-client-secret" {
+		t.Fatalf("unexpected credentials: %+v", credentials)
+	}
+
+	// The new access token must be able to decrypt the organization key from the payload sent to the API.
+	secret, err := base64.StdEncoding.DecodeString(credentials.EncryptionKey)
+	if err != nil || len(secret) != 16 {
+		t.Fatalf("unexpected encryption key: %q", credentials.EncryptionKey)
+	}
+	request := standIn.tokenRequests[accessToken.ID]
+	payload, err := decryptString(deriveShareableKey(secret, "accesstoken", "sm-access-token"), request.EncryptedPayload)
+	if err != nil {
+		t.Fatalf("unable to decrypt the payload with the access token: %s", err)
+	}
+	var decoded struct {
+		EncryptionKey string `json:"encryptionKey"`
+	}
+	if err := json.Unmarshal(payload, &decoded); err != nil || decoded.EncryptionKey != base64.StdEncoding.EncodeToString(standIn.orgKey.bytes()) {
+		t.Fatalf("expected the payload to contain the organization key, got: %s", payload)
+	}
+
+	found, err := machineAccounts.GetAccessToken(ctx, account.ID, accessToken.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if found == nil || found.Name != "deploy" || found.Token != "" {
+		t.Fatalf("unexpected access token: %+v", found)
+	}
+
+	if err := machineAccounts.RevokeAccessToken(ctx, account.ID, accessToken.ID); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	found, err = machineAccounts.GetAccessToken(ctx, account.ID, accessToken.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if found != nil {
+		t.Fatalf("expected the access token to be revoked, got %+v", found)
+	}
+}
+
+// The Bitwarden SDK must be able to log in with a created access token: it derives the key of the encrypted payload
+// from the access token, decrypts the organization key from it and uses that key to decrypt the API responses.
+func TestMachineAccountAccessTokenSDKLogin(t *testing.T) {
+	ctx := context.Background()
+	standIn := newMachineAccountStandIn(t)
+	machineAccounts := standIn.client().MachineAccounts()
+
+	account, err := machineAccounts.Create(ctx, standInOrganizationID, "ci-pipeline")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	accessToken, err := machineAccounts.CreateAccessToken(ctx, account.ID, "deploy", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	encryptedPayload := standIn.tokenRequests[accessToken.ID].EncryptedPayload
+	projectName, err := encryptString(standIn.orgKey, []byte("project"))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// The server the new access token logs in at only knows the payload sent when the access token was created.
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /identity/connect/token", func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil || r.PostForm.Get("client_id") != accessToken.ID || r.PostForm.Get("client_secret") != "stand-in-client-secret" {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_client"})
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{
+			"access_token":      testBearerToken,
+			"expires_in":        3600,
+			"token_type":        "Bearer",
+			"scope":             "api.secrets",
+			"encrypted_payload": encryptedPayload,
+		})
+	})
+	mux.HandleFunc("GET /api/projects/{id}", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, standInProject{
+			ID:             r.PathValue("id"),
+			OrganizationID: standInOrganizationID,
+			Name:           projectName,
+			Read:           true,
+			Write:          true,
+			Object:         "project",
+		})
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	apiUrl, identityUrl := server.URL+"/api", server.URL+"/identity"
+	client, err := sdk.NewBitwardenClient(&apiUrl, &identityUrl)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	t.Cleanup(client.Close)
+
+	if err := client.AccessTokenLogin(accessToken.Token, nil); err != nil {
+		t.Fatalf("expected the SDK to log in with the created access token: %s", err)
+	}
+	project, err := client.Projects().Get(validProjectUUID)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if project.Name != "project" {
+		t.Fatalf("expected the SDK to decrypt the project name, got %q", project.Name)
+	}
+}
+
+func TestMachineAccountAccessTokenWithoutExpiration(t *testing.T) {
+	ctx := context.Background()
+	standIn := newMachineAccountStandIn(t)
+	machineAccounts := standIn.client().MachineAccounts()
+
+	account, err := machineAccounts.Create(ctx, validProjectUUID, "ci-pipeline")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	accessToken, err := machineAccounts.CreateAccessToken(ctx, account.ID, "deploy", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if accessToken.ExpiresAt != nil {
+		t.Fatalf("expected an access token without expiration, got %+v", accessToken)
+	}
+
+	if _, err := machineAccounts.CreateAccessToken(ctx, uuid.NewString(), "deploy", nil); !isNotFound(err) {
+		t.Fatalf("expected a not found error for a missing machine account, got: %v", err)
+	}
+}
@@ -0,0 +1,172 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"strings"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var (
+	_ basetypes.StringTypable                    = normalizedStringType{}
+	_ basetypes.StringValuableWithSemanticEquals = normalizedStringValue{}
+)
+
+// secretStringNormalization is the default normalisation of the value and note of secrets, see secretNormalization.
+// Files read via file() usually end with a trailing newline and files edited on Windows use CRLF line endings. By
+// default, neither difference is considered a change.
+var secretStringNormalization = stringNormalization{
+	TrimTrailingNewline:  true,
+	NormalizeLineEndings: true,
+}
+
+// stringNormalization configures which differences between two strings are considered semantically irrelevant.
+type stringNormalization struct {
+	// TrimTrailingNewline ignores a single trailing line break.
+	TrimTrailingNewline bool
+	// NormalizeLineEndings treats CRLF line endings as LF.
+	NormalizeLineEndings bool
+}
+
+// apply returns the normalised representation of value.
+func (n stringNormalization) apply(value string) string {
+	if n.NormalizeLineEndings {
+		value = strings.ReplaceAll(value, "\r\n", "\n")
+	}
+	if n.TrimTrailingNewline {
+		if trimmed, ok := strings.CutSuffix(value, "\r\n"); ok {
+			return trimmed
+		}
+		value = strings.TrimSuffix(value, "\n")
+	}
+	return value
+}
+
+// secretNormalization returns the normalisation configured by the trim_trailing_newline and normalize_line_endings
+// attributes of a secret resource. Unset and unknown attributes use the default of secretStringNormalization.
+func secretNormalization(trimTrailingNewline types.Bool, normalizeLineEndings types.Bool) stringNormalization {
+	normalization := secretStringNormalization
+	if !trimTrailingNewline.IsNull() && !trimTrailingNewline.IsUnknown() {
+		normalization.TrimTrailingNewline = trimTrailingNewline.ValueBool()
+	}
+	if !normalizeLineEndings.IsNull() && !normalizeLineEndings.IsUnknown() {
+		normalization.NormalizeLineEndings = normalizeLineEndings.ValueBool()
+	}
+	return normalization
+}
+
+// normalizedValue returns the value to store for a value read from Bitwarden Secrets Manager. The prior value is kept
+// if it is semantically equal to remote, so ignored differences are not shown as a change.
+func (n stringNormalization) normalizedValue(prior types.String, remote string) types.String {
+	if prior.IsNull() || prior.IsUnknown() {
+		return types.StringValue(remote)
+	}
+	if equal, _ := newNormalizedStringValue(remote, n).StringSemanticEquals(context.Background(), normalizedString(prior)); equal {
+		return prior
+	}
+	return types.StringValue(remote)
+}
+
+// normalizedStringType is the type of the value and note of a secret. Its values are compared with the normalisation
+// configured by the trim_trailing_newline and normalize_line_endings attributes, see normalizedStringValue.
+type normalizedStringType struct {
+	basetypes.StringType
+}
+
+func (t normalizedStringType) Equal(o attr.Type) bool {
+	_, ok := o.(normalizedStringType)
+	return ok
+}
+
+func (t normalizedStringType) String() string {
+	return "normalizedStringType"
+}
+
+func (t normalizedStringType) ValueFromString(_ context.Context, in basetypes.StringValue) (basetypes.StringValuable, diag.Diagnostics) {
+	return normalizedStringValue{StringValue: in}, nil
+}
+
+func (t normalizedStringType) ValueFromTerraform(ctx context.Context, in tftypes.Value) (attr.Value, error) {
+	attrValue, err := t.StringType.ValueFromTerraform(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+
+	stringValue, ok := attrValue.(basetypes.StringValue)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value type of %T", attrValue)
+	}
+
+	stringValuable, diags := t.ValueFromString(ctx, stringValue)
+	if diags.HasError() {
+		return nil, fmt.Errorf("unexpected error converting StringValue to StringValuable: %v", diags)
+	}
+
+	return stringValuable, nil
+}
+
+func (t normalizedStringType) ValueType(_ context.Context) attr.Value {
+	return normalizedStringValue{}
+}
+
+// normalizedStringValue is the value of a normalizedStringType. The normalisation depends on other attributes of the
+// resource, which a type cannot access, so values decoded by the framework compare exactly and the resource applies
+// the configured normalisation with withNormalization before comparing them.
+type normalizedStringValue struct {
+	basetypes.StringValue
+	normalization stringNormalization
+}
+
+// normalizedString returns value as a normalizedStringValue.
+func normalizedString(value types.String) normalizedStringValue {
+	return normalizedStringValue{StringValue: value}
+}
+
+func newNormalizedStringValue(value string, normalization stringNormalization) normalizedStringValue {
+	return normalizedStringValue{StringValue: basetypes.NewStringValue(value), normalization: normalization}
+}
+
+// withNormalization returns the value compared with the given normalisation.
+func (v normalizedStringValue) withNormalization(normalization stringNormalization) normalizedStringValue {
+	v.normalization = normalization
+	return v
+}
+
+func (v normalizedStringValue) Equal(o attr.Value) bool {
+	other, ok := o.(normalizedStringValue)
+	if !ok {
+		return false
+	}
+	return v.StringValue.Equal(other.StringValue)
+}
+
+func (v normalizedStringValue) Type(_ context.Context) attr.Type {
+	return normalizedStringType{}
+}
+
+// StringSemanticEquals returns true if both values are equal after applying the normalisation of v.
+func (v normalizedStringValue) StringSemanticEquals(_ context.Context, newValuable basetypes.StringValuable) (bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	newValue, ok := newValuable.(normalizedStringValue)
+	if !ok {
+		diags.AddError(
+			"Semantic Equality Check Error",
+			"An unexpected value type was received while performing semantic equality checks. "+
+				"Please report this to the provider developers.\n\n"+
+				"Expected Value Type: "+fmt.Sprintf("%T", v)+"\n"+
+				"Got Value Type: "+fmt.Sprintf("%T", newValuable),
+		)
+		return false, diags
+	}
+
+	if v.IsNull() || v.IsUnknown() || newValue.IsNull() || newValue.IsUnknown() {
+		return v.StringValue.Equal(newValue.StringValue), diags
+	}
+	return v.normalization.apply(v.ValueString()) == v.normalization.apply(newValue.ValueString()), diags
+}
@@ -0,0 +1,150 @@
+package provider
+
+import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"testing"
+)
+
+func TestNormalizedValue(t *testing.T) {
+	testCases := map[string]struct {
+		normalization stringNormalization
+		prior         string
+		new           string
+		expectEqual   bool
+	}{
+		"identical": {
+			normalization: secretStringNormalization,
+			prior:         "secret",
+			new:           "secret",
+			expectEqual:   true,
+		},
+		"trailing newline from file()": {
+			normalization: secretStringNormalization,
+			prior:         "secret\n",
+			new:           "secret",
+			expectEqual:   true,
+		},
+		"only a single trailing newline is ignored": {
+			normalization: secretStringNormalization,
+			prior:         "secret",
+			new:           "secret\n\n",
+			expectEqual:   false,
+		},
+		"single trailing newline after a blank line": {
+			normalization: secretStringNormalization,
+			prior:         "secret\n",
+			new:           "secret\n\n",
+			expectEqual:   false,
+		},
+		"trailing CRLF": {
+			normalization: secretStringNormalization,
+			prior:         "secret\r\n",
+			new:           "secret",
+			expectEqual:   true,
+		},
+		"CRLF line endings": {
+			normalization: secretStringNormalization,
+			prior:         "line1\r\nline2\r\n",
+			new:           "line1\nline2",
+			expectEqual:   true,
+		},
+		"different content": {
+			normalization: secretStringNormalization,
+			prior:         "secret\n",
+			new:           "other\n",
+			expectEqual:   false,
+		},
+		"leading newline is significant": {
+			normalization: secretStringNormalization,
+			prior:         "\nsecret",
+			new:           "secret",
+			expectEqual:   false,
+		},
+		"inner newline is significant": {
+			normalization: secretStringNormalization,
+			prior:         "line1\nline2",
+			new:           "line1line2",
+			expectEqual:   false,
+		},
+		"trailing newline without trimming": {
+			normalization: stringNormalization{NormalizeLineEndings: true},
+			prior:         "secret\n",
+			new:           "secret",
+			expectEqual:   false,
+		},
+		"CRLF without line ending normalisation": {
+			normalization: stringNormalization{TrimTrailingNewline: true},
+			prior:         "line1\r\nline2",
+			new:           "line1\nline2",
+			expectEqual:   false,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			value := testCase.normalization.normalizedValue(types.StringValue(testCase.prior), testCase.new)
+
+			if kept := value.ValueString() == testCase.prior; kept != testCase.expectEqual {
+				t.Fatalf("expected the equality of %q and %q to be %t", testCase.prior, testCase.new, testCase.expectEqual)
+			}
+			if !testCase.expectEqual && value.ValueString() != testCase.new {
+				t.Fatalf("expected the new value %q, got %q", testCase.new, value.ValueString())
+			}
+		})
+	}
+}
+
+func TestNormalizedValueWithoutPriorValue(t *testing.T) {
+	for _, prior := range []types.String{types.StringNull(), types.StringUnknown()} {
+		if value := secretStringNormalization.normalizedValue(prior, "secret\n"); !value.Equal(types.StringValue("secret\n")) {
+			t.Fatalf("expected the new value for prior value %s, got %s", prior, value)
+		}
+	}
+}
+
+func TestSecretNormalization(t *testing.T) {
+	if normalization := secretNormalization(types.BoolNull(), types.BoolUnknown()); normalization != secretStringNormalization {
+		t.Fatalf("expected the default normalisation for unset attributes, got %+v", normalization)
+	}
+	expected := stringNormalization{NormalizeLineEndings: true}
+	if normalization := secretNormalization(types.BoolValue(false), types.BoolValue(true)); normalization != expected {
+		t.Fatalf("expected %+v, got %+v", expected, normalization)
+	}
+}
+
+func TestNormalizedStringSemanticEquals(t *testing.T) {
+	ctx := context.Background()
+	value := newNormalizedStringValue("line1\r\nline2\n", secretStringNormalization)
+
+	testCases := map[string]struct {
+		other    basetypes.StringValuable
+		expected bool
+	}{
+		"normalised":  {other: normalizedString(types.StringValue("line1\nline2")), expected: true},
+		"different":   {other: normalizedString(types.StringValue("line1\nline3")), expected: false},
+		"null":        {other: normalizedString(types.StringNull()), expected: false},
+		"unknown":     {other: normalizedString(types.StringUnknown()), expected: false},
+		"exact match": {other: normalizedString(types.StringValue("line1\r\nline2\n")), expected: true},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			equal, diags := value.StringSemanticEquals(ctx, testCase.other)
+			if diags.HasError() {
+				t.Fatalf("unexpected error: %v", diags)
+			}
+			if equal != testCase.expected {
+				t.Fatalf("expected the semantic equality to be %t", testCase.expected)
+			}
+		})
+	}
+
+	// Values decoded by the framework compare exactly.
+	if equal, _ := normalizedString(types.StringValue("secret\n")).StringSemanticEquals(ctx, normalizedString(types.StringValue("secret"))); equal {
+		t.Fatal("expected values without normalisation to compare exactly")
+	}
+	if _, diags := value.StringSemanticEquals(ctx, types.StringValue("line1\nline2")); !diags.HasError() {
+		t.Fatal("expected an error for an unexpected value type")
+	}
+}
@@ -0,0 +1,90 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"strings"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ function.Function = &parseReferenceFunction{}
+
+// secretReferenceScheme is the scheme of secret references in the format bws://<project>/<key>.
+const secretReferenceScheme = "bws://"
+
+var secretReferenceAttributeTypes = map[string]attr.Type{
+	"project": types.StringType,
+	"key":     types.StringType,
+}
+
+// NewParseReferenceFunction is a helper function to simplify the provider implementation.
+func NewParseReferenceFunction() function.Function {
+	return &parseReferenceFunction{}
+}
+
+// parseReferenceFunction defines the function implementation.
+type parseReferenceFunction struct{}
+
+func (f *parseReferenceFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "parse_reference"
+}
+
+func (f *parseReferenceFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:             "Parses a secret reference",
+		Description:         "Parses a secret reference in the format bws://<project>/<key> and returns an object with the project and the key of the secret. The key may contain further slashes.",
+		MarkdownDescription: "Parses a secret reference in the format `bws://<project>/<key>` and returns an object with the `project` and the `key` of the secret. The key may contain further slashes.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "reference",
+				Description:         "The secret reference in the format bws://<project>/<key>.",
+				MarkdownDescription: "The secret reference in the format `bws://<project>/<key>`.",
+			},
+		},
+		Return: function.ObjectReturn{
+			AttributeTypes: secretReferenceAttributeTypes,
+		},
+	}
+}
+
+func (f *parseReferenceFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var reference string
+	resp.Error = req.Arguments.Get(ctx, &reference)
+	if resp.Error != nil {
+		return
+	}
+
+	project, key, err := parseSecretReference(reference)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+
+	result, diags := types.ObjectValue(secretReferenceAttributeTypes, map[string]attr.Value{
+		"project": types.StringValue(project),
+		"key":     types.StringValue(key),
+	})
+	resp.Error = function.FuncErrorFromDiags(ctx, diags)
+	if resp.Error != nil {
+		return
+	}
+
+	resp.Error = resp.Result.Set(ctx, result)
+}
+
+// parseSecretReference splits a secret reference in the format bws://<project>/<key> into its parts.
+func parseSecretReference(reference string) (project string, key string, err error) {
+	path, found := strings.CutPrefix(reference, secretReferenceScheme)
+	if !found {
+		return "", "", fmt.Errorf("secret reference must start with %q, got: %q", secretReferenceScheme, reference)
+	}
+
+	project, key, found = strings.Cut(path, "/")
+	if !found || project == "" || key == "" {
+		return "", "", fmt.Errorf("secret reference must be in the format bws://<project>/<key>, got: %q", reference)
+	}
+	return project, key, nil
+}
@@ -0,0 +1,82 @@
+package provider
+
+import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"strings"
+	"testing"
+)
+
+func TestParseReferenceFunction(t *testing.T) {
+	tests := map[string]struct {
+		reference string
+		expected  attr.Value
+		err       string
+	}{
+		"valid": {
+			reference: "bws://" + validProjectUUID + "/DATABASE_PASSWORD",
+			expected: types.ObjectValueMust(secretReferenceAttributeTypes, map[string]attr.Value{
+				"project": types.StringValue(validProjectUUID),
+				"key":     types.StringValue("DATABASE_PASSWORD"),
+			}),
+		},
+		"key with slashes": {
+			reference: "bws://" + validProjectUUID + "/payments/database/password",
+			expected: types.ObjectValueMust(secretReferenceAttributeTypes, map[string]attr.Value{
+				"project": types.StringValue(validProjectUUID),
+				"key":     types.StringValue("payments/database/password"),
+			}),
+		},
+		"missing scheme": {
+			reference: validProjectUUID + "/DATABASE_PASSWORD",
+			err:       "secret reference must start with",
+		},
+		"other scheme": {
+			reference: "https://" + validProjectUUID + "/DATABASE_PASSWORD",
+			err:       "secret reference must start with",
+		},
+		"missing key": {
+			reference: "bws://" + validProjectUUID,
+			err:       "secret reference must be in the format",
+		},
+		"empty key": {
+			reference: "bws://" + validProjectUUID + "/",
+			err:       "secret reference must be in the format",
+		},
+		"empty project": {
+			reference: "bws:///DATABASE_PASSWORD",
+			err:       "secret reference must be in the format",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{types.StringValue(test.reference)}),
+			}
+			resp := function.RunResponse{
+				Result: function.NewResultData(types.ObjectUnknown(secretReferenceAttributeTypes)),
+			}
+
+			NewParseReferenceFunction().Run(context.Background(), req, &resp)
+
+			if test.err != "" {
+				if resp.Error == nil || !strings.Contains(resp.Error.Error(), test.err) {
+					t.Fatalf("expected an error containing %q, got: %v", test.err, resp.Error)
+				}
+				if resp.Error.FunctionArgument == nil || *resp.Error.FunctionArgument != 0 {
+					t.Fatalf("expected the error to refer to the reference argument, got: %v", resp.Error.FunctionArgument)
+				}
+				return
+			}
+			if resp.Error != nil {
+				t.Fatalf("unexpected error: %s", resp.Error)
+			}
+			if !resp.Result.Value().Equal(test.expected) {
+				t.Fatalf("expected %s, got %s", test.expected, resp.Result.Value())
+			}
+		})
+	}
+}
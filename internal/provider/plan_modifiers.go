@@ -0,0 +1,124 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ planmodifier.String = &clearWhenUnsetModifier{}
+
+// clearWhenUnsetModifier plans the cleared value of an optional and computed attribute when it is removed from the
+// configuration. Without it, terraform keeps the prior state of computed attributes, so removing e.g. a note from the
+// configuration would silently keep the note in Bitwarden Secrets Manager.
+type clearWhenUnsetModifier struct {
+	clearedValue types.String
+}
+
+func (m clearWhenUnsetModifier) Description(_ context.Context) string {
+	if m.clearedValue.IsNull() {
+		return "If the attribute is not configured, it is cleared and planned as null."
+	}
+	return fmt.Sprintf("If the attribute is not configured, it is cleared and planned as %q.", m.clearedValue.ValueString())
+}
+
+func (m clearWhenUnsetModifier) MarkdownDescription(ctx context.Context) string {
+	return m.Description(ctx)
+}
+
+func (m clearWhenUnsetModifier) PlanModifyString(_ context.Context, req planmodifier.StringRequest, resp *planmodifier.StringResponse) {
+	// Do nothing on resource destroy.
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	// Configured and unknown values are planned as they are.
+	if !req.ConfigValue.IsNull() {
+		return
+	}
+
+	resp.PlanValue = m.clearedValue
+}
+
+func clearWhenUnset(clearedValue types.String) clearWhenUnsetModifier {
+	return clearWhenUnsetModifier{clearedValue: clearedValue}
+}
+
+var _ planmodifier.Bool = &keepStateWhenUnsetModifier{}
+
+// keepStateWhenUnsetModifier plans the prior state of an optional and computed attribute with a default when it is
+// removed from the configuration. The default only applies to new resources, so removing e.g. deletion_protection
+// from the configuration does not silently lift the protection.
+type keepStateWhenUnsetModifier struct{}
+
+func (m keepStateWhenUnsetModifier) Description(_ context.Context) string {
+	return "If the attribute is removed from the configuration, its prior state is kept."
+}
+
+func (m keepStateWhenUnsetModifier) MarkdownDescription(ctx context.Context) string {
+	return m.Description(ctx)
+}
+
+func (m keepStateWhenUnsetModifier) PlanModifyBool(_ context.Context, req planmodifier.BoolRequest, resp *planmodifier.BoolResponse) {
+	// Do nothing on resource creation and destroy.
+	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() {
+		return
+	}
+
+	if !req.ConfigValue.IsNull() || req.StateValue.IsNull() {
+		return
+	}
+
+	resp.PlanValue = req.StateValue
+}
+
+func keepStateWhenUnset() keepStateWhenUnsetModifier {
+	return keepStateWhenUnsetModifier{}
+}
+
+var _ planmodifier.String = &keepNormalizedStateModifier{}
+
+// keepNormalizedStateModifier plans the prior state of the value or note of a secret if the configured value only
+// differs from it in differences ignored by the normalisation of trim_trailing_newline and normalize_line_endings, see
+// normalizedStringValue. Terraform accepts the prior state as planned value of a configured attribute, so e.g. a
+// trailing newline added to a file read via file() does not plan an update.
+type keepNormalizedStateModifier struct{}
+
+func (m keepNormalizedStateModifier) Description(_ context.Context) string {
+	return "If the configured value is semantically equal to its prior state, the prior state is kept."
+}
+
+func (m keepNormalizedStateModifier) MarkdownDescription(ctx context.Context) string {
+	return m.Description(ctx)
+}
+
+func (m keepNormalizedStateModifier) PlanModifyString(ctx context.Context, req planmodifier.StringRequest, resp *planmodifier.StringResponse) {
+	// Do nothing on resource creation and destroy.
+	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() {
+		return
+	}
+
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() || req.StateValue.IsNull() || req.StateValue.IsUnknown() {
+		return
+	}
+
+	var trimTrailingNewline, normalizeLineEndings types.Bool
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("trim_trailing_newline"), &trimTrailingNewline)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("normalize_line_endings"), &normalizeLineEndings)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	normalization := secretNormalization(trimTrailingNewline, normalizeLineEndings)
+	equal, diags := normalizedString(req.ConfigValue).withNormalization(normalization).StringSemanticEquals(ctx, normalizedString(req.StateValue))
+	resp.Diagnostics.Append(diags...)
+	if equal {
+		resp.PlanValue = req.StateValue
+	}
+}
+
+func keepNormalizedState() keepNormalizedStateModifier {
+	return keepNormalizedStateModifier{}
+}
@@ -0,0 +1,107 @@
+package provider
+
+import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"testing"
+)
+
+// planClearWhenUnset runs the modifier the same way terraform does for an optional and computed attribute:
+// unset attributes are planned with their prior state or as unknown before the modifier is applied.
+func planClearWhenUnset(modifier clearWhenUnsetModifier, config types.String, state types.String) types.String {
+	planValue := config
+	if config.IsNull() {
+		planValue = state
+	}
+
+	req := planmodifier.StringRequest{
+		ConfigValue: config,
+		StateValue:  state,
+		PlanValue:   planValue,
+		Plan: tfsdk.Plan{
+			Raw: tftypes.NewValue(tftypes.Object{}, map[string]tftypes.Value{}),
+		},
+	}
+	resp := &planmodifier.StringResponse{PlanValue: req.PlanValue}
+
+	modifier.PlanModifyString(context.Background(), req, resp)
+
+	return resp.PlanValue
+}
+
+func TestClearWhenUnsetNoteTransitions(t *testing.T) {
+	modifier := clearWhenUnset(types.StringValue(""))
+	state := types.StringNull()
+
+	steps := []struct {
+		config   types.String
+		expected types.String
+	}{
+		// set
+		{config: types.StringValue("note"), expected: types.StringValue("note")},
+		// unset clears the note
+		{config: types.StringNull(), expected: types.StringValue("")},
+		// set again
+		{config: types.StringValue("updated note"), expected: types.StringValue("updated note")},
+		// explicit empty string clears the note as well
+		{config: types.StringValue(""), expected: types.StringValue("")},
+	}
+
+	for i, step := range steps {
+		planned := planClearWhenUnset(modifier, step.config, state)
+		if !planned.Equal(step.expected) {
+			t.Fatalf("step %d: expected planned value %s, got %s", i, step.expected, planned)
+		}
+		state = planned
+	}
+}
+
+func TestClearWhenUnsetProjectTransitions(t *testing.T) {
+	modifier := clearWhenUnset(types.StringNull())
+	state := types.StringNull()
+
+	steps := []struct {
+		config   types.String
+		expected types.String
+	}{
+		// set
+		{config: types.StringValue(validProjectUUID), expected: types.StringValue(validProjectUUID)},
+		// unset removes the secret from the project
+		{config: types.StringNull(), expected: types.StringNull()},
+		// set again
+		{config: types.StringValue(validProjectUUID), expected: types.StringValue(validProjectUUID)},
+		// unknown values are kept until they are known
+		{config: types.StringUnknown(), expected: types.StringUnknown()},
+	}
+
+	for i, step := range steps {
+		planned := planClearWhenUnset(modifier, step.config, state)
+		if !planned.Equal(step.expected) {
+			t.Fatalf("step %d: expected planned value %s, got %s", i, step.expected, planned)
+		}
+		state = planned
+	}
+}
+
+func TestClearWhenUnsetIgnoresDestroy(t *testing.T) {
+	modifier := clearWhenUnset(types.StringValue(""))
+
+	req := planmodifier.StringRequest{
+		ConfigValue: types.StringNull(),
+		StateValue:  types.StringValue("note"),
+		PlanValue:   types.StringNull(),
+		Plan: tfsdk.Plan{
+			Raw: tftypes.NewValue(tftypes.Object{}, nil),
+		},
+	}
+	resp := &planmodifier.StringResponse{PlanValue: req.PlanValue}
+
+	modifier.PlanModifyString(context.Background(), req, resp)
+
+	if !resp.PlanValue.IsNull() {
+		t.Fatalf("expected null plan value on destroy, got %s", resp.PlanValue)
+	}
+}
@@ -0,0 +1,431 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"strings"
+)
+
+var (
+	// Ensure provider defined types fully satisfy framework interfaces.
+	_ resource.Resource                   = &projectAccessResource{}
+	_ resource.ResourceWithConfigure      = &projectAccessResource{}
+	_ resource.ResourceWithImportState    = &projectAccessResource{}
+	_ resource.ResourceWithValidateConfig = &projectAccessResource{}
+)
+
+// projectAccessGrantee describes the kind of grantee managed by a projectAccessResource.
+type projectAccessGrantee struct {
+	// typeNameSuffix is appended to the provider type name, e.g. "_project_group_access".
+	typeNameSuffix string
+	// attribute is the name of the attribute holding the ID of the grantee, e.g. "group_id".
+	attribute string
+	// name is the human-readable name of the grantee, e.g. "group".
+	name string
+
+	get    func(ctx context.Context, client projectAccessPoliciesInterface, projectID string, granteeID string) (*accessPolicy, error)
+	set    func(ctx context.Context, client projectAccessPoliciesInterface, projectID string, policy accessPolicy) (*accessPolicy, error)
+	delete func(ctx context.Context, client projectAccessPoliciesInterface, projectID string, granteeID string) error
+}
+
+var (
+	machineAccountGrantee = projectAccessGrantee{
+		typeNameSuffix: "_project_machine_account_access",
+		attribute:      "machine_account_id",
+		name:           "machine account",
+		get: func(ctx context.Context, client projectAccessPoliciesInterface, projectID string, granteeID string) (*accessPolicy, error) {
+			return client.GetMachineAccountAccess(ctx, projectID, granteeID)
+		},
+		set: func(ctx context.Context, client projectAccessPoliciesInterface, projectID string, policy accessPolicy) (*accessPolicy, error) {
+			return client.SetMachineAccountAccess(ctx, projectID, policy)
+		},
+		delete: func(ctx context.Context, client projectAccessPoliciesInterface, projectID string, granteeID string) error {
+			return client.DeleteMachineAccountAccess(ctx, projectID, granteeID)
+		},
+	}
+	groupGrantee = projectAccessGrantee{
+		typeNameSuffix: "_project_group_access",
+		attribute:      "group_id",
+		name:           "group",
+		get: func(ctx context.Context, client projectAccessPoliciesInterface, projectID string, granteeID string) (*accessPolicy, error) {
+			return client.GetGroupAccess(ctx, projectID, granteeID)
+		},
+		set: func(ctx context.Context, client projectAccessPoliciesInterface, projectID string, policy accessPolicy) (*accessPolicy, error) {
+			return client.SetGroupAccess(ctx, projectID, policy)
+		},
+		delete: func(ctx context.Context, client projectAccessPoliciesInterface, projectID string, granteeID string) error {
+			return client.DeleteGroupAccess(ctx, projectID, granteeID)
+		},
+	}
+)
+
+// NewProjectMachineAccountAccessResource is a helper function to simplify the provider implementation.
+func NewProjectMachineAccountAccessResource() resource.Resource {
+	return &projectAccessResource{grantee: machineAccountGrantee}
+}
+
+// NewProjectGroupAccessResource is a helper function to simplify the provider implementation.
+func NewProjectGroupAccessResource() resource.Resource {
+	return &projectAccessResource{grantee: groupGrantee}
+}
+
+// projectAccessResource defines the resource implementation shared by all project access resources.
+type projectAccessResource struct {
+	apiClient func() (bitwardenAPIClient, error)
+	grantee   projectAccessGrantee
+}
+
+// projectAccessModel is the data of a project access resource. The grantee ID is stored in the attribute of the
+// grantee kind, so the model is read and written attribute by attribute.
+type projectAccessModel struct {
+	ID        types.String
+	ProjectID types.String
+	GranteeID types.String
+	Read      types.Bool
+	Write     types.Bool
+
+	Timeouts timeouts.Value
+}
+
+func (r *projectAccessResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + r.grantee.typeNameSuffix
+}
+
+func (r *projectAccessResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description:         fmt.Sprintf("The %s resource grants a %s read or read/write access to a project in Bitwarden Secrets Manager. The used machine account needs permission to manage the access policies of the project.", strings.TrimPrefix(r.grantee.typeNameSuffix, "_"), r.grantee.name),
+		MarkdownDescription: fmt.Sprintf("The `%s` resource grants a %s read or read/write access to a project in Bitwarden Secrets Manager. The used machine account needs permission to manage the access policies of the project.", strings.TrimPrefix(r.grantee.typeNameSuffix, "_"), r.grantee.name),
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description:         fmt.Sprintf("String representation of the ID of the access policy in the format <project_id>/<%s>.", r.grantee.attribute),
+				MarkdownDescription: fmt.Sprintf("String representation of the `ID` of the access policy in the format `<project_id>/<%s>`.", r.grantee.attribute),
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"project_id": schema.StringAttribute{
+				Description:         "String representation of the ID of the project to which access is granted.",
+				MarkdownDescription: "String representation of the `ID` of the project to which access is granted.",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringUUIDValidate(),
+				},
+			},
+			r.grantee.attribute: schema.StringAttribute{
+				Description:         fmt.Sprintf("String representation of the ID of the %s which is granted access.", r.grantee.name),
+				MarkdownDescription: fmt.Sprintf("String representation of the `ID` of the %s which is granted access.", r.grantee.name),
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringUUIDValidate(),
+				},
+			},
+			"read": schema.BoolAttribute{
+				Description:         fmt.Sprintf("Grants the %s read access to the secrets of the project. The provided default is true.", r.grantee.name),
+				MarkdownDescription: fmt.Sprintf("Grants the %s read access to the secrets of the project. The provided default is true.", r.grantee.name),
+				Computed:            true,
+				Optional:            true,
+				Default:             booldefault.StaticBool(true),
+			},
+			"write": schema.BoolAttribute{
+				Description:         fmt.Sprintf("Grants the %s write access to the secrets of the project. Write access requires read access. The provided default is false.", r.grantee.name),
+				MarkdownDescription: fmt.Sprintf("Grants the %s write access to the secrets of the project. Write access requires `read` access. The provided default is false.", r.grantee.name),
+				Computed:            true,
+				Optional:            true,
+				Default:             booldefault.StaticBool(false),
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Update: true,
+				Delete: true,
+			}),
+		},
+	}
+}
+
+func (r *projectAccessResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Add a nil check when handling BitwardenSecretsManagerProviderDataStruct because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	tflog.Info(ctx, "Configuring Project Access Resource")
+	if req.ProviderData == nil {
+		tflog.Debug(ctx, "Skipping Resource Configuration because Provider has not been configured yet.")
+		return
+	}
+
+	providerDataStruct, ok := req.ProviderData.(BitwardenSecretsManagerProviderDataStruct)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected sdk.BitwardenClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	if providerDataStruct.apiClient == nil {
+		resp.Diagnostics.AddError(
+			"Client Not Initialized",
+			"The Bitwarden client was not properly initialized due to a missing Bitwarden API Client.",
+		)
+		return
+	}
+
+	r.apiClient = providerDataStruct.apiClient
+
+	tflog.Info(ctx, "Resource Configured")
+}
+
+func (r *projectAccessResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var read, write types.Bool
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("read"), &read)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("write"), &write)...)
+	if resp.Diagnostics.HasError() || read.IsUnknown() || write.IsUnknown() {
+		return
+	}
+
+	// Unset attributes use the schema defaults: read access without write access.
+	if !read.IsNull() && !read.ValueBool() {
+		if write.ValueBool() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("write"),
+				"Invalid Access Policy",
+				"Write access requires read access. Set read to true.",
+			)
+		} else {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("read"),
+				"Invalid Access Policy",
+				"An access policy must grant at least read access. Remove the resource to revoke access.",
+			)
+		}
+	}
+}
+
+func (r *projectAccessResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	plan, diags := r.getModel(ctx, req.Plan.GetAttribute)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	apiClient := configuredAPIClient(r.apiClient, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, done := withOperationTimeout(ctx, plan.Timeouts.Create, "create", "the project access", &resp.Diagnostics)
+	defer done()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	policy, err := r.grantee.set(ctx, apiClient.ProjectAccessPolicies(), plan.ProjectID.ValueString(), accessPolicy{
+		GranteeID: plan.GranteeID.ValueString(),
+		Read:      plan.Read.ValueBool(),
+		Write:     plan.Write.ValueBool(),
+	})
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Grant Project Access",
+			err.Error(),
+		)
+		return
+	}
+
+	plan.ID = types.StringValue(projectAccessID(plan.ProjectID.ValueString(), policy.GranteeID))
+	plan.Read = types.BoolValue(policy.Read)
+	plan.Write = types.BoolValue(policy.Write)
+
+	resp.Diagnostics.Append(r.setModel(ctx, &resp.State, plan)...)
+}
+
+func (r *projectAccessResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	tflog.Info(ctx, "Reading Project Access Resource")
+
+	state, diags := r.getModel(ctx, req.State.GetAttribute)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	apiClient := configuredAPIClient(r.apiClient, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, done := withOperationTimeout(ctx, state.Timeouts.Read, "read", "the project access", &resp.Diagnostics)
+	defer done()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Imported resources only know their ID.
+	if state.ProjectID.IsNull() || state.GranteeID.IsNull() {
+		projectID, granteeID, err := parseProjectAccessID(state.ID.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Invalid Project Access ID",
+				err.Error(),
+			)
+			return
+		}
+		state.ProjectID = types.StringValue(projectID)
+		state.GranteeID = types.StringValue(granteeID)
+	}
+
+	policy, err := r.grantee.get(ctx, apiClient.ProjectAccessPolicies(), state.ProjectID.ValueString(), state.GranteeID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read Project Access with id: "+state.ID.ValueString(),
+			err.Error(),
+		)
+		return
+	}
+	if policy == nil {
+		tflog.Warn(ctx, "Project access was revoked outside of terraform", map[string]any{"id": state.ID.ValueString()})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	state.Read = types.BoolValue(policy.Read)
+	state.Write = types.BoolValue(policy.Write)
+
+	resp.Diagnostics.Append(r.setModel(ctx, &resp.State, state)...)
+}
+
+func (r *projectAccessResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	plan, diags := r.getModel(ctx, req.Plan.GetAttribute)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	apiClient := configuredAPIClient(r.apiClient, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, done := withOperationTimeout(ctx, plan.Timeouts.Update, "update", "the project access", &resp.Diagnostics)
+	defer done()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	policy, err := r.grantee.set(ctx, apiClient.ProjectAccessPolicies(), plan.ProjectID.ValueString(), accessPolicy{
+		GranteeID: plan.GranteeID.ValueString(),
+		Read:      plan.Read.ValueBool(),
+		Write:     plan.Write.ValueBool(),
+	})
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Update Project Access",
+			err.Error(),
+		)
+		return
+	}
+
+	plan.Read = types.BoolValue(policy.Read)
+	plan.Write = types.BoolValue(policy.Write)
+
+	resp.Diagnostics.Append(r.setModel(ctx, &resp.State, plan)...)
+}
+
+func (r *projectAccessResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	state, diags := r.getModel(ctx, req.State.GetAttribute)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	apiClient := configuredAPIClient(r.apiClient, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, done := withOperationTimeout(ctx, state.Timeouts.Delete, "delete", "the project access", &resp.Diagnostics)
+	defer done()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.grantee.delete(ctx, apiClient.ProjectAccessPolicies(), state.ProjectID.ValueString(), state.GranteeID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Revoke Project Access",
+			err.Error(),
+		)
+	}
+}
+
+func (r *projectAccessResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// Retrieve import ID in the format <project_id>/<grantee_id> and save to id attribute
+	if _, _, err := parseProjectAccessID(req.ID); err != nil {
+		resp.Diagnostics.AddError(
+			"Invalid Import ID",
+			err.Error(),
+		)
+		return
+	}
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+func (r *projectAccessResource) getModel(ctx context.Context, getAttribute func(context.Context, path.Path, interface{}) diag.Diagnostics) (projectAccessModel, diag.Diagnostics) {
+	var model projectAccessModel
+	var diags diag.Diagnostics
+
+	diags.Append(getAttribute(ctx, path.Root("id"), &model.ID)...)
+	diags.Append(getAttribute(ctx, path.Root("project_id"), &model.ProjectID)...)
+	diags.Append(getAttribute(ctx, path.Root(r.grantee.attribute), &model.GranteeID)...)
+	diags.Append(getAttribute(ctx, path.Root("read"), &model.Read)...)
+	diags.Append(getAttribute(ctx, path.Root("write"), &model.Write)...)
+	diags.Append(getAttribute(ctx, path.Root("timeouts"), &model.Timeouts)...)
+
+	return model, diags
+}
+
+func (r *projectAccessResource) setModel(ctx context.Context, state *tfsdk.State, model projectAccessModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	diags.Append(state.SetAttribute(ctx, path.Root("id"), model.ID)...)
+	diags.Append(state.SetAttribute(ctx, path.Root("project_id"), model.ProjectID)...)
+	diags.Append(state.SetAttribute(ctx, path.Root(r.grantee.attribute), model.GranteeID)...)
+	diags.Append(state.SetAttribute(ctx, path.Root("read"), model.Read)...)
+	diags.Append(state.SetAttribute(ctx, path.Root("write"), model.Write)...)
+	diags.Append(state.SetAttribute(ctx, path.Root("timeouts"), model.Timeouts)...)
+
+	return diags
+}
+
+func projectAccessID(projectID string, granteeID string) string {
+	return projectID + "/" + granteeID
+}
+
+// parseProjectAccessID splits an ID in the format <project_id>/<grantee_id> into its parts.
+func parseProjectAccessID(id string) (projectID string, granteeID string, err error) {
+	projectID, granteeID, found := strings.Cut(id, "/")
+	if !found || projectID == "" || granteeID == "" {
+		return "", "", fmt.Errorf("expected an ID in the format <project_id>/<grantee_id>, got: %q", id)
+	}
+	return projectID, granteeID, nil
+}
@@ -4,8 +4,10 @@ import (
 	"context"
 	"fmt"
 	"github.com/bitwarden/sdk-go"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/datasource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
@@ -29,6 +31,8 @@ type projectsDataSource struct {
 // projectsDataSourceModel describes the data source data model.
 type projectsDataSourceModel struct {
 	Projects []projectDataSourceModel `tfsdk:"projects"`
+
+	Timeouts timeouts.Value `tfsdk:"timeouts"`
 }
 
 type projectDataSourceModel struct {
@@ -43,7 +47,7 @@ func (d *projectsDataSource) Metadata(_ context.Context, req datasource.Metadata
 	resp.TypeName = req.ProviderTypeName + "_projects"
 }
 
-func (d *projectsDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+func (d *projectsDataSource) Schema(ctx context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		Description:         "The projects data source fetches all projects accessible by the used machine account.",
 		MarkdownDescription: "The `projects` data source fetches all projects accessible by the used machine account.",
@@ -80,6 +84,9 @@ func (d *projectsDataSource) Schema(_ context.Context, _ datasource.SchemaReques
 				},
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx),
+		},
 	}
 }
 
@@ -127,7 +134,7 @@ func (d *projectsDataSource) Configure(ctx context.Context, req datasource.Confi
 	tflog.Info(ctx, "Datasource Configured")
 }
 
-func (d *projectsDataSource) Read(ctx context.Context, _ datasource.ReadRequest, resp *datasource.ReadResponse) {
+func (d *projectsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
 	tflog.Info(ctx, "Reading Projects Datasource")
 
 	var state projectsDataSourceModel
@@ -140,7 +147,19 @@ func (d *projectsDataSource) Read(ctx context.Context, _ datasource.ReadRequest,
 		return
 	}
 
-	projects, err := d.bitwardenClient.Projects().List(d.organizationId)
+	var readTimeouts timeouts.Value
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("timeouts"), &readTimeouts)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, client, done := withClientTimeout(ctx, d.bitwardenClient, readTimeouts.Read, "read", "the projects data source", &resp.Diagnostics)
+	defer done()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	projects, err := client.Projects().List(d.organizationId)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to List Projects",
@@ -161,6 +180,8 @@ func (d *projectsDataSource) Read(ctx context.Context, _ datasource.ReadRequest,
 		state.Projects = append(state.Projects, projectState)
 	}
 
+	state.Timeouts = readTimeouts
+
 	// Set state
 	diags := resp.State.Set(ctx, &state)
 	resp.Diagnostics.Append(diags...)
@@ -31,7 +31,7 @@ func TestAccDatasourceProjectsListOneProject(t *testing.T) {
 	if err != nil {
 		t.Fatalf("Error creating bitwardenClient: %s", err)
 	}
-	resource.Test(t, resource.TestCase{
+	runProviderTest(t, resource.TestCase{
 		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		PreCheck: func() {
 			project, preCheckErr := bitwardenClient.Projects().Create(organizationId, projectName)
@@ -69,7 +69,7 @@ func TestAccDatasourceProjectsListTwoProject(t *testing.T) {
 	if err != nil {
 		t.Fatalf("Error creating bitwardenClient: %s", err)
 	}
-	resource.Test(t, resource.TestCase{
+	runProviderTest(t, resource.TestCase{
 		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		PreCheck: func() {
 			project, preCheckErr := bitwardenClient.Projects().Create(organizationId, projectName1)
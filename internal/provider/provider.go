@@ -3,7 +3,10 @@ package provider
 import (
 	"context"
 	"github.com/bitwarden/sdk-go"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
 	"github.com/hashicorp/terraform-plugin-framework/function"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
@@ -11,15 +14,17 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"os"
 )
 
 var (
 	// Ensure BitwardenSecretsManagerProvider satisfies various provider interfaces.
-	_         provider.Provider              = &BitwardenSecretsManagerProvider{}
-	_         provider.ProviderWithFunctions = &BitwardenSecretsManagerProvider{}
-	statePath                                = ".bw-provider-state"
+	_         provider.Provider                       = &BitwardenSecretsManagerProvider{}
+	_         provider.ProviderWithFunctions          = &BitwardenSecretsManagerProvider{}
+	_         provider.ProviderWithEphemeralResources = &BitwardenSecretsManagerProvider{}
+	statePath                                         = ".bw-provider-state"
 )
 
 // BitwardenSecretsManagerProvider defines the provider implementation.
@@ -28,6 +33,11 @@ type BitwardenSecretsManagerProvider struct {
 	// provider is built and ran locally, and "test" when running acceptance
 	// testing.
 	version string
+	// newBitwardenClient creates the client of the Bitwarden SDK, tests replace it with an in-memory fake.
+	newBitwardenClient func(apiURL *string, identityURL *string) (sdk.BitwardenClientInterface, error)
+	// newAPIClient creates the client of the parts of the API which are not exposed by the SDK. It is only called
+	// when a resource or data source uses it, see lazyAPIClient.
+	newAPIClient func(apiURL string, identityURL string, accessToken string) (bitwardenAPIClient, error)
 }
 
 // BitwardenSecretsManagerProviderModel describes the provider data model.
@@ -36,6 +46,11 @@ type BitwardenSecretsManagerProviderModel struct {
 	IdentityUrl    types.String `tfsdk:"identity_url"`
 	AccessToken    types.String `tfsdk:"access_token"`
 	OrganizationId types.String `tfsdk:"organization_id"`
+
+	KeyNamingPolicy  types.Object `tfsdk:"key_naming_policy"`
+	EnforceUniqueKey types.String `tfsdk:"enforce_unique_key"`
+	BackupDir        types.String `tfsdk:"backup_dir"`
+	BackupKey        types.String `tfsdk:"backup_key"`
 }
 
 func (p *BitwardenSecretsManagerProvider) Metadata(_ context.Context, _ provider.MetadataRequest, resp *provider.MetadataResponse) {
@@ -46,6 +61,15 @@ func (p *BitwardenSecretsManagerProvider) Metadata(_ context.Context, _ provider
 type BitwardenSecretsManagerProviderDataStruct struct {
 	bitwardenClient sdk.BitwardenClientInterface
 	organizationId  string
+	// apiClient returns the client of the parts of the Bitwarden Secrets Manager API which are not exposed by the SDK.
+	// The client is created on first use, see lazyAPIClient.
+	apiClient func() (bitwardenAPIClient, error)
+	// keyNamingPolicy is nil if no key naming policy is configured.
+	keyNamingPolicy *keyNamingPolicy
+	// enforceUniqueKey is the default unique key mode of the secret resource.
+	enforceUniqueKey string
+	// secretBackup is nil if no backup directory is configured.
+	secretBackup *secretBackup
 }
 
 func (p *BitwardenSecretsManagerProvider) Schema(_ context.Context, _ provider.SchemaRequest, resp *provider.SchemaResponse) {
@@ -95,6 +119,80 @@ func (p *BitwardenSecretsManagerProvider) Schema(_ context.Context, _ provider.S
 					stringUUIDValidate(),
 				},
 			},
+			"enforce_unique_key": schema.StringAttribute{
+				Description: "Default for enforce_unique_key of all secret resources, which configures how existing secrets with the same key in the same project are handled when a secret is created. " +
+					"One of off, error or adopt_existing. The provided default is off.",
+				MarkdownDescription: "Default for `enforce_unique_key` of all `secret` resources, which configures how existing secrets with the same `key` in the same project are handled when a secret is created. " +
+					"One of `off`, `error` or `adopt_existing`. The provided default is `off`.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(uniqueKeyModes...),
+				},
+			},
+			"backup_dir": schema.StringAttribute{
+				Description: "Directory to which an encrypted copy of every secret is written before the secret is deleted by the secret or secrets resource. " +
+					"The copies are encrypted with a key derived from backup_key, a secret is not deleted if its copy cannot be written. " +
+					"By default, no copies are written.",
+				MarkdownDescription: "Directory to which an encrypted copy of every secret is written before the secret is deleted by the `secret` or `secrets` resource. " +
+					"The copies are encrypted with a key derived from `backup_key`, a secret is not deleted if its copy cannot be written. " +
+					"By default, no copies are written.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"backup_key": schema.StringAttribute{
+				Description: "Base64 encoded key of at least 32 bytes, e.g. generated with openssl rand -base64 32, which encrypts the copies written to backup_dir. " +
+					"It does not depend on the access token, so the copies can still be decrypted with the decrypt_secret_backup function after the access token was rotated. " +
+					"Required if backup_dir is set. This configuration value can also be provided via BW_BACKUP_KEY environment variable.",
+				MarkdownDescription: "Base64 encoded key of at least 32 bytes, e.g. generated with `openssl rand -base64 32`, which encrypts the copies written to `backup_dir`. " +
+					"It does not depend on the `access_token`, so the copies can still be decrypted with the `decrypt_secret_backup` function after the access token was rotated. " +
+					"Required if `backup_dir` is set. This configuration value can also be provided via `BW_BACKUP_KEY` environment variable.",
+				Optional:  true,
+				Sensitive: true,
+			},
+			"key_naming_policy": schema.SingleNestedAttribute{
+				Description: "Naming rules which the key of every secret managed by the secret and secrets resources has to satisfy. " +
+					"The policy is only checked at plan time, by terraform plan and terraform apply, which report violations with a suggestion of a normalised key. " +
+					"terraform validate cannot check it, since the provider configuration is not available during validation. " +
+					"Rules which terraform validate should check can be set with key_naming_policy of the resources instead. " +
+					"Unset rules are not checked.",
+				MarkdownDescription: "Naming rules which the `key` of every secret managed by the `secret` and `secrets` resources has to satisfy. " +
+					"The policy is only checked at plan time, by `terraform plan` and `terraform apply`, which report violations with a suggestion of a normalised key. " +
+					"`terraform validate` cannot check it, since the provider configuration is not available during validation. " +
+					"Rules which `terraform validate` should check can be set with `key_naming_policy` of the resources instead. " +
+					"Unset rules are not checked.",
+				Optional: true,
+				Attributes: map[string]schema.Attribute{
+					"pattern": schema.StringAttribute{
+						Description:         "Regular expression in RE2 syntax which keys must match, e.g. ^[A-Z][A-Z0-9_]*$. Anchor the expression to match the whole key.",
+						MarkdownDescription: "Regular expression in [RE2 syntax](https://github.com/google/re2/wiki/Syntax) which keys must match, e.g. `^[A-Z][A-Z0-9_]*$`. Anchor the expression to match the whole key.",
+						Optional:            true,
+					},
+					"max_length": schema.Int64Attribute{
+						Description: "Maximum number of characters of keys.",
+						Optional:    true,
+						Validators: []validator.Int64{
+							int64validator.AtLeast(1),
+						},
+					},
+					"allowed_characters": schema.StringAttribute{
+						Description:         "Characters allowed in keys, written as the content of a regular expression character class, e.g. A-Z0-9_.",
+						MarkdownDescription: "Characters allowed in keys, written as the content of a regular expression character class, e.g. `A-Z0-9_`.",
+						Optional:            true,
+					},
+					"case_style": schema.StringAttribute{
+						Description: "Case style of keys, one of upper_snake (DB_PASSWORD), lower_snake (db_password), kebab (db-password), " +
+							"camel (dbPassword) or pascal (DbPassword).",
+						MarkdownDescription: "Case style of keys, one of `upper_snake` (`DB_PASSWORD`), `lower_snake` (`db_password`), `kebab` (`db-password`), " +
+							"`camel` (`dbPassword`) or `pascal` (`DbPassword`).",
+						Optional: true,
+						Validators: []validator.String{
+							stringvalidator.OneOf(caseStyles...),
+						},
+					},
+				},
+			},
 		},
 	}
 }
@@ -149,10 +247,67 @@ func (p *BitwardenSecretsManagerProvider) Configure(ctx context.Context, req pro
 		)
 	}
 
+	if config.EnforceUniqueKey.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("enforce_unique_key"),
+			"Unknown Unique Key Mode",
+			"The provider cannot check secret keys for uniqueness as there is an unknown configuration value for enforce_unique_key. "+
+				"Either target apply the source of the value first or set the value statically in the configuration.",
+		)
+	}
+
+	if config.BackupDir.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("backup_dir"),
+			"Unknown Backup Directory",
+			"The provider cannot back up deleted secrets as there is an unknown configuration value for backup_dir. "+
+				"Either target apply the source of the value first or set the value statically in the configuration.",
+		)
+	}
+
+	if config.BackupKey.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("backup_key"),
+			"Unknown Backup Key",
+			"The provider cannot back up deleted secrets as there is an unknown configuration value for backup_key. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the BW_BACKUP_KEY environment variable.",
+		)
+	}
+
+	if config.KeyNamingPolicy.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("key_naming_policy"),
+			"Unknown Key Naming Policy",
+			"The provider cannot validate secret keys as there is an unknown configuration value for the key naming policy. "+
+				"Either target apply the source of the value first or set the value statically in the configuration.",
+		)
+	}
+
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	var namingPolicy *keyNamingPolicy
+	if !config.KeyNamingPolicy.IsNull() {
+		var policyConfig keyNamingPolicyModel
+		diags = config.KeyNamingPolicy.As(ctx, &policyConfig, basetypes.ObjectAsOptions{})
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		policy, err := newKeyNamingPolicy(policyConfig)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("key_naming_policy"),
+				"Invalid Key Naming Policy",
+				err.Error(),
+			)
+			return
+		}
+		namingPolicy = policy
+	}
+
 	// Default values to environment variables, but override
 	// with Terraform configuration value if set.
 
@@ -224,6 +379,35 @@ func (p *BitwardenSecretsManagerProvider) Configure(ctx context.Context, req pro
 		return
 	}
 
+	var backup *secretBackup
+	if !config.BackupDir.IsNull() {
+		backupKey := os.Getenv("BW_BACKUP_KEY")
+		if !config.BackupKey.IsNull() {
+			backupKey = config.BackupKey.ValueString()
+		}
+		if backupKey == "" {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("backup_key"),
+				"Missing Backup Key",
+				"The provider cannot back up deleted secrets as there is a missing or empty configuration value for backup_key. "+
+					"Set the backup_key value in the configuration or use the BW_BACKUP_KEY environment variable. "+
+					"If either is already set, ensure the value is not empty.",
+			)
+			return
+		}
+
+		var err error
+		backup, err = newSecretBackup(config.BackupDir.ValueString(), backupKey)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("backup_key"),
+				"Invalid Backup Key",
+				"The provider cannot derive the encryption key of the backup from backup_key: "+err.Error(),
+			)
+			return
+		}
+	}
+
 	ctx = tflog.SetField(ctx, "bitwarden_secrets_manager_api_url", apiUrl)
 	ctx = tflog.SetField(ctx, "bitwarden_secrets_manager_identity_url", identityUrl)
 	ctx = tflog.SetField(ctx, "bitwarden_secrets_manager_access_token", accessToken)
@@ -234,7 +418,11 @@ func (p *BitwardenSecretsManagerProvider) Configure(ctx context.Context, req pro
 	tflog.Debug(ctx, "Creating Bitwarden Secrets Manager Client")
 
 	// Create a new bitwardenClient using the configuration values
-	bitwardenClient, err := sdk.NewBitwardenClient(&apiUrl, &identityUrl)
+	newBitwardenClient := p.newBitwardenClient
+	if newBitwardenClient == nil {
+		newBitwardenClient = sdk.NewBitwardenClient
+	}
+	bitwardenClient, err := newBitwardenClient(&apiUrl, &identityUrl)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to Create Bitwarden Secrets Manager Client",
@@ -247,7 +435,9 @@ func (p *BitwardenSecretsManagerProvider) Configure(ctx context.Context, req pro
 
 	tflog.Debug(ctx, "Bitwarden Secrets Manager Client created")
 
-	err = bitwardenClient.AccessTokenLogin(accessToken, &statePath)
+	// Only the login is bound to the context of the configuration, the client itself outlives it.
+	defer addCancellationDiagnostic(ctx, &resp.Diagnostics, "authentication of the Bitwarden Secrets Manager Client")
+	err = clientWithContext(ctx, bitwardenClient).AccessTokenLogin(accessToken, &statePath)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to Authenticate Bitwarden Secrets Manager Client",
@@ -260,15 +450,29 @@ func (p *BitwardenSecretsManagerProvider) Configure(ctx context.Context, req pro
 
 	tflog.Debug(ctx, "Bitwarden Secrets Manager Client authenticated")
 
+	newAPIClient := p.newAPIClient
+	if newAPIClient == nil {
+		newAPIClient = newBitwardenAPIClient
+	}
+
 	// Make the bitwardenClient available during DataSource and Resource
 	// type Configure methods.
 	providerDataStruct := BitwardenSecretsManagerProviderDataStruct{
-		bitwardenClient,
-		organizationId,
+		bitwardenClient:  bitwardenClient,
+		organizationId:   organizationId,
+		apiClient:        lazyAPIClient(newAPIClient, apiUrl, identityUrl, accessToken),
+		keyNamingPolicy:  namingPolicy,
+		enforceUniqueKey: uniqueKeyOff,
+		secretBackup:     backup,
+	}
+
+	if !config.EnforceUniqueKey.IsNull() {
+		providerDataStruct.enforceUniqueKey = config.EnforceUniqueKey.ValueString()
 	}
 
 	resp.DataSourceData = providerDataStruct
 	resp.ResourceData = providerDataStruct
+	resp.EphemeralResourceData = providerDataStruct
 
 	tflog.Info(ctx, "Configured Bitwarden Secrets Manager Client", map[string]any{"success": true})
 }
@@ -276,6 +480,17 @@ func (p *BitwardenSecretsManagerProvider) Configure(ctx context.Context, req pro
 func (p *BitwardenSecretsManagerProvider) Resources(_ context.Context) []func() resource.Resource {
 	return []func() resource.Resource{
 		NewSecretResource,
+		NewSecretsResource,
+		NewProjectMachineAccountAccessResource,
+		NewProjectGroupAccessResource,
+		NewMachineAccountResource,
+		NewMachineAccountAccessTokenResource,
+	}
+}
+
+func (p *BitwardenSecretsManagerProvider) EphemeralResources(_ context.Context) []func() ephemeral.EphemeralResource {
+	return []func() ephemeral.EphemeralResource{
+		NewMachineAccountAccessTokenEphemeralResource,
 	}
 }
 
@@ -284,11 +499,21 @@ func (p *BitwardenSecretsManagerProvider) DataSources(_ context.Context) []func(
 		NewProjectsDataSource,
 		NewListSecretsDataSource,
 		NewSecretDataSource,
+		NewWhoamiDataSource,
+		NewSyncDataSource,
 	}
 }
 
 func (p *BitwardenSecretsManagerProvider) Functions(_ context.Context) []func() function.Function {
-	return []func() function.Function{}
+	return []func() function.Function{
+		NewParseReferenceFunction,
+		NewIsUUIDFunction,
+		NewGeneratePasswordFunction,
+		NewToDotenvFunction,
+		NewToEnvJSONFunction,
+		NewToK8sSecretYAMLFunction,
+		NewDecryptSecretBackupFunction,
+	}
 }
 
 func New(version string) func() provider.Provider {
@@ -14,7 +14,7 @@ const (
 )
 
 func TestAccProviderExpectErrorOnMissingApiUrlInProviderConfigString(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	runProviderTest(t, resource.TestCase{
 		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		PreCheck:                 preCheckUnsetAllEnvVars,
 		Steps: []resource.TestStep{
@@ -33,7 +33,7 @@ func TestAccProviderExpectErrorOnMissingApiUrlInProviderConfigString(t *testing.
 }
 
 func TestAccProviderExpectErrorOnMissingIdentityUrlInProviderConfigString(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	runProviderTest(t, resource.TestCase{
 		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		PreCheck:                 preCheckUnsetAllEnvVars,
 		Steps: []resource.TestStep{
@@ -52,7 +52,7 @@ func TestAccProviderExpectErrorOnMissingIdentityUrlInProviderConfigString(t *tes
 }
 
 func TestAccProviderExpectErrorOnMissingApiAndIdentityUrlInProviderConfigString1(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	runProviderTest(t, resource.TestCase{
 		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		PreCheck:                 preCheckUnsetAllEnvVars,
 		Steps: []resource.TestStep{
@@ -70,7 +70,7 @@ func TestAccProviderExpectErrorOnMissingApiAndIdentityUrlInProviderConfigString1
 }
 
 func TestAccProviderExpectErrorOnMissingApiAndIdentityUrlInProviderConfigString2(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	runProviderTest(t, resource.TestCase{
 		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		PreCheck:                 preCheckUnsetAllEnvVars,
 		Steps: []resource.TestStep{
@@ -88,7 +88,7 @@ func TestAccProviderExpectErrorOnMissingApiAndIdentityUrlInProviderConfigString2
 }
 
 func TestAccProviderExpectErrorOnMissingAccessTokenInProviderConfigString(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	runProviderTest(t, resource.TestCase{
 		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		PreCheck:                 preCheckUnsetAllEnvVars,
 		Steps: []resource.TestStep{
@@ -107,7 +107,7 @@ func TestAccProviderExpectErrorOnMissingAccessTokenInProviderConfigString(t *tes
 }
 
 func TestAccProviderExpectErrorOnMissingOrganizationIdInProviderConfigString(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	runProviderTest(t, resource.TestCase{
 		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		PreCheck:                 preCheckUnsetAllEnvVars,
 		Steps: []resource.TestStep{
@@ -126,7 +126,7 @@ func TestAccProviderExpectErrorOnMissingOrganizationIdInProviderConfigString(t *
 }
 
 func TestAccProviderExpectErrorOnOrganizationIdNotAValidUUID(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	runProviderTest(t, resource.TestCase{
 		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		PreCheck:                 preCheckUnsetAllEnvVars,
 		Steps: []resource.TestStep{
@@ -157,7 +157,7 @@ func TestAccProviderExpectErrorOnOrganizationIdNotAValidUUID(t *testing.T) {
 }
 
 func TestAccProviderExpectErrorOnMissingApiUrlInEnvVars(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	runProviderTest(t, resource.TestCase{
 		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		PreCheck: func() {
 			preCheckUnsetAllEnvVars()
@@ -186,7 +186,7 @@ func TestAccProviderExpectErrorOnMissingApiUrlInEnvVars(t *testing.T) {
 }
 
 func TestAccProviderExpectErrorOnMissingIdentityUrlInEnvVars(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	runProviderTest(t, resource.TestCase{
 		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		PreCheck: func() {
 			preCheckUnsetAllEnvVars()
@@ -215,7 +215,7 @@ func TestAccProviderExpectErrorOnMissingIdentityUrlInEnvVars(t *testing.T) {
 }
 
 func TestAccProviderExpectErrorOnMissingApiAndIdentityUrlInEnvVars(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	runProviderTest(t, resource.TestCase{
 		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		PreCheck: func() {
 			preCheckUnsetAllEnvVars()
@@ -240,7 +240,7 @@ func TestAccProviderExpectErrorOnMissingApiAndIdentityUrlInEnvVars(t *testing.T)
 }
 
 func TestAccProviderExpectErrorOnMissingAccessTokenInEnvVars(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	runProviderTest(t, resource.TestCase{
 		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		PreCheck: func() {
 			preCheckUnsetAllEnvVars()
@@ -269,7 +269,7 @@ func TestAccProviderExpectErrorOnMissingAccessTokenInEnvVars(t *testing.T) {
 }
 
 func TestAccProviderExpectErrorOnMissingOrganizationIdInEnvVars(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	runProviderTest(t, resource.TestCase{
 		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		PreCheck: func() {
 			preCheckUnsetAllEnvVars()
@@ -298,7 +298,7 @@ func TestAccProviderExpectErrorOnMissingOrganizationIdInEnvVars(t *testing.T) {
 }
 
 func TestAccProviderConfigurationValid(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	runProviderTest(t, resource.TestCase{
 		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		PreCheck:                 preCheckUnsetAllEnvVars,
 		Steps: []resource.TestStep{
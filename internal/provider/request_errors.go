@@ -0,0 +1,74 @@
+package provider
+
+import (
+	"errors"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// requestErrorKind tells how a failed request to Bitwarden Secrets Manager is handled, see classifyRequestError.
+type requestErrorKind int
+
+const (
+	// requestRejected is a request which was rejected by the server and fails again if it is sent again.
+	requestRejected requestErrorKind = iota
+	// requestNotFound is a request for an object which does not exist.
+	requestNotFound
+	// requestRateLimited is a request which was not processed because of the rate limit of the server. It can be
+	// sent again after waiting.
+	requestRateLimited
+	// requestAmbiguous is a request which may have been processed by the server anyway, which is the case for server
+	// errors and requests whose response did not arrive.
+	requestAmbiguous
+)
+
+// apiErrorStatusRegex matches the HTTP status inside the errors of the SDK, e.g.
+// "API error: Received error message from server: [404 Not Found] {...}".
+var apiErrorStatusRegex = regexp.MustCompile(`\[(\d{3}) [^\]]*\]`)
+
+// transportErrorMessages are parts of the errors of the SDK for requests whose response did not arrive.
+var transportErrorMessages = []string{"error sending request", "timed out", "connection reset", "connection closed", "unexpected EOF"}
+
+// classifyRequestError classifies an error of the SDK or of the API client of the provider.
+func classifyRequestError(err error) requestErrorKind {
+	if statusCode, ok := errorHTTPStatus(err); ok {
+		switch {
+		case statusCode == http.StatusNotFound:
+			return requestNotFound
+		case statusCode == http.StatusTooManyRequests:
+			return requestRateLimited
+		case statusCode >= 500:
+			return requestAmbiguous
+		default:
+			return requestRejected
+		}
+	}
+
+	message := err.Error()
+	for _, transportError := range transportErrorMessages {
+		if strings.Contains(message, transportError) {
+			return requestAmbiguous
+		}
+	}
+	return requestRejected
+}
+
+// isNotFound reports whether the server responded to a request with not found.
+func isNotFound(err error) bool {
+	return classifyRequestError(err) == requestNotFound
+}
+
+// errorHTTPStatus returns the HTTP status of the response which caused err, if there was a response.
+func errorHTTPStatus(err error) (int, bool) {
+	var apiErr *apiError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode, true
+	}
+	if match := apiErrorStatusRegex.FindStringSubmatch(err.Error()); match != nil {
+		statusCode, _ := strconv.Atoi(match[1])
+		return statusCode, true
+	}
+	return 0, false
+}
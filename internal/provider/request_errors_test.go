@@ -0,0 +1,77 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestClassifyRequestError(t *testing.T) {
+	tests := map[string]struct {
+		err      error
+		expected requestErrorKind
+	}{
+		"sdk server error": {
+			err:      errors.New("API error: Received error message from server: [500 Internal Server Error] {}"),
+			expected: requestAmbiguous,
+		},
+		"sdk service unavailable": {
+			err:      errors.New("API error: Received error message from server: [503 Service Unavailable] "),
+			expected: requestAmbiguous,
+		},
+		"sdk request not sent": {
+			err:      errors.New("API error: error sending request for url (https://api.bitwarden.com/organizations/x/secrets)"),
+			expected: requestAmbiguous,
+		},
+		"sdk timeout": {
+			err:      errors.New("API error: operation timed out"),
+			expected: requestAmbiguous,
+		},
+		"sdk bad request": {
+			err:      errors.New("API error: Received error message from server: [400 Bad Request] {\"message\":\"The Key is invalid\"}"),
+			expected: requestRejected,
+		},
+		"sdk not found": {
+			err:      errors.New("API error: Received error message from server: [404 Not Found] {}"),
+			expected: requestNotFound,
+		},
+		"sdk rate limited": {
+			err:      errors.New("API error: Received error message from server: [429 Too Many Requests] Slow down!"),
+			expected: requestRateLimited,
+		},
+		"sdk not authenticated": {
+			err:      errors.New("API error: Access token is not authenticated"),
+			expected: requestRejected,
+		},
+		"api not found": {
+			err:      fmt.Errorf("unable to read the machine account: %w", &apiError{StatusCode: http.StatusNotFound, Message: "Not Found"}),
+			expected: requestNotFound,
+		},
+		"api rate limited": {
+			err:      &apiError{StatusCode: http.StatusTooManyRequests, Message: "Too Many Requests"},
+			expected: requestRateLimited,
+		},
+		"api server error": {
+			err:      &apiError{StatusCode: http.StatusBadGateway, Message: "Bad Gateway"},
+			expected: requestAmbiguous,
+		},
+		"api forbidden": {
+			err:      &apiError{StatusCode: http.StatusForbidden, Message: "Forbidden"},
+			expected: requestRejected,
+		},
+		"context": {
+			err:      context.Canceled,
+			expected: requestRejected,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if actual := classifyRequestError(test.err); actual != test.expected {
+				t.Fatalf("expected %q to be classified as %d, got %d", test.err, test.expected, actual)
+			}
+		})
+	}
+}
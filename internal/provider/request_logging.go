@@ -0,0 +1,102 @@
+package provider
+
+import (
+	"context"
+	"github.com/bitwarden/sdk-go"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"regexp"
+	"time"
+)
+
+// requestLogSubsystem is the tflog subsystem of the requests to Bitwarden Secrets Manager. Its level can be set
+// separately from the other logs of the provider with TF_LOG_PROVIDER_BITWARDEN_SM_REQUESTS.
+const requestLogSubsystem = "requests"
+
+// maskedRequestLogFields are the fields of request logs whose values are never written.
+var maskedRequestLogFields = []string{"value", "note", "access_token", "token"}
+
+// maskedRequestLogRegexes match credentials which are masked wherever they appear in request logs, e.g. inside the
+// message of an error: machine account access tokens and bearer tokens.
+var maskedRequestLogRegexes = []*regexp.Regexp{
+	regexp.MustCompile(`0\.[0-9a-fA-F-]{36}\.[^\s:"]+:[A-Za-z0-9+/]+=*`),
+	regexp.MustCompile(`Bearer [A-Za-z0-9._~+/-]+=*`),
+}
+
+// withRequestLogging returns ctx with the request log subsystem, which includes the fields of the RPC, like the
+// resource type, and masks secret values, notes and credentials.
+func withRequestLogging(ctx context.Context) context.Context {
+	ctx = tflog.NewSubsystem(ctx, requestLogSubsystem,
+		tflog.WithLevelFromEnv("TF_LOG_PROVIDER_BITWARDEN_SM", requestLogSubsystem),
+		tflog.WithRootFields(),
+	)
+	ctx = tflog.SubsystemMaskFieldValuesWithFieldKeys(ctx, requestLogSubsystem, maskedRequestLogFields...)
+	ctx = tflog.SubsystemMaskAllFieldValuesRegexes(ctx, requestLogSubsystem, maskedRequestLogRegexes...)
+	ctx = tflog.SubsystemMaskMessageRegexes(ctx, requestLogSubsystem, maskedRequestLogRegexes...)
+	return ctx
+}
+
+// logRequest logs the start of a request at TRACE level and its result, latency and HTTP status at DEBUG level. ctx
+// must contain the request log subsystem, see withRequestLogging.
+func logRequest[T any](ctx context.Context, operation string, fields map[string]any, call func() (T, error)) (T, error) {
+	ctx = tflog.SubsystemSetField(ctx, requestLogSubsystem, "operation", operation)
+	tflog.SubsystemTrace(ctx, requestLogSubsystem, "Sending request to Bitwarden Secrets Manager", fields)
+
+	started := time.Now()
+	value, err := call()
+
+	result := map[string]any{"duration_ms": time.Since(started).Milliseconds()}
+	for name, value := range fields {
+		if name != "value" && name != "note" {
+			result[name] = value
+		}
+	}
+	for name, value := range responseLogFields(value) {
+		result[name] = value
+	}
+	if err != nil {
+		result["error"] = err.Error()
+		if statusCode, ok := errorHTTPStatus(err); ok {
+			result["http_status"] = statusCode
+		}
+		tflog.SubsystemDebug(ctx, requestLogSubsystem, "Request to Bitwarden Secrets Manager failed", result)
+		return value, err
+	}
+	tflog.SubsystemDebug(ctx, requestLogSubsystem, "Request to Bitwarden Secrets Manager succeeded", result)
+	return value, nil
+}
+
+// httpStatus is the HTTP status of a successful response of the Bitwarden Secrets Manager API.
+type httpStatus int
+
+// responseLogFields returns the IDs, counts and status of a response worth logging.
+func responseLogFields(response any) map[string]any {
+	switch response := response.(type) {
+	case httpStatus:
+		return map[string]any{"http_status": int(response)}
+	case *sdk.SecretResponse:
+		if response != nil {
+			fields := map[string]any{"secret_id": response.ID}
+			if response.ProjectID != nil {
+				fields["project_id"] = *response.ProjectID
+			}
+			return fields
+		}
+	case *sdk.ProjectResponse:
+		if response != nil {
+			return map[string]any{"project_id": response.ID}
+		}
+	case *sdk.SecretsResponse:
+		if response != nil {
+			return map[string]any{"count": len(response.Data)}
+		}
+	case *sdk.SecretIdentifiersResponse:
+		if response != nil {
+			return map[string]any{"count": len(response.Data)}
+		}
+	case *sdk.ProjectsResponse:
+		if response != nil {
+			return map[string]any{"count": len(response.Data)}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// requestLogs returns the entries of the request log subsystem written to output.
+func requestLogs(t *testing.T, output *bytes.Buffer) []map[string]any {
+	t.Helper()
+
+	entries, err := tflogtest.MultilineJSONDecode(bytes.NewReader(output.Bytes()))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	var logs []map[string]any
+	for _, entry := range entries {
+		if entry["@module"] == "provider."+requestLogSubsystem {
+			logs = append(logs, entry)
+		}
+	}
+	return logs
+}
+
+// findRequestLog returns the first entry of an operation with the given message.
+func findRequestLog(t *testing.T, logs []map[string]any, operation string, message string) map[string]any {
+	t.Helper()
+
+	for _, entry := range logs {
+		if entry["operation"] == operation && entry["@message"] == message {
+			return entry
+		}
+	}
+	t.Fatalf("expected a log %q of %s, got: %v", message, operation, logs)
+	return nil
+}
+
+func TestRequestLogging(t *testing.T) {
+	var output bytes.Buffer
+	ctx := tflogtest.RootLogger(context.Background(), &output)
+
+	store := newFakeBitwardenStore(fakeOrganizationID)
+	client := clientWithContext(ctx, store.newClient())
+	if err := client.AccessTokenLogin(fakeAccessToken, nil); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	project, err := client.Projects().Create(fakeOrganizationID, "project")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	secret, err := client.Secrets().Create("API_KEY", "secret-value", "secret-note", fakeOrganizationID, []string{project.ID})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, err := client.Secrets().Get("00000000-0000-0000-0000-000000000000"); err == nil {
+		t.Fatal("expected an error for a missing secret")
+	}
+
+	logs := requestLogs(t, &output)
+
+	sent := findRequestLog(t, logs, "Secrets.Create", "Sending request to Bitwarden Secrets Manager")
+	if sent["@level"] != "trace" || sent["key"] != "API_KEY" || sent["value"] != "***" || sent["note"] != "***" {
+		t.Fatalf("unexpected request log: %v", sent)
+	}
+	created := findRequestLog(t, logs, "Secrets.Create", "Request to Bitwarden Secrets Manager succeeded")
+	if created["@level"] != "debug" || created["secret_id"] != secret.ID || created["project_id"] != project.ID {
+		t.Fatalf("unexpected response log: %v", created)
+	}
+	if _, ok := created["duration_ms"]; !ok {
+		t.Fatalf("expected the latency to be logged: %v", created)
+	}
+
+	failed := findRequestLog(t, logs, "Secrets.Get", "Request to Bitwarden Secrets Manager failed")
+	if failed["http_status"] != float64(http.StatusNotFound) || failed["secret_id"] != "00000000-0000-0000-0000-000000000000" {
+		t.Fatalf("unexpected error log: %v", failed)
+	}
+
+	login := findRequestLog(t, logs, "AccessTokenLogin", "Sending request to Bitwarden Secrets Manager")
+	if login["access_token"] != "***" {
+		t.Fatalf("expected the access token to be masked: %v", login)
+	}
+
+	for _, sensitive := range []string{"secret-value", "secret-note", fakeAccessToken} {
+		if strings.Contains(output.String(), sensitive) {
+			t.Fatalf("expected %q to be masked in the logs: %s", sensitive, output.String())
+		}
+	}
+}
+
+func TestRequestLoggingHTTPAPIClient(t *testing.T) {
+	var output bytes.Buffer
+	ctx := tflogtest.RootLogger(context.Background(), &output)
+
+	standIn := newAPIStandIn(t)
+	standIn.handle("GET /forbidden", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusForbidden, map[string]string{"message": "Bearer " + testBearerToken + " is not allowed."})
+	})
+	client := standIn.client()
+	if err := client.do(ctx, http.MethodGet, "/forbidden", nil, nil); err == nil {
+		t.Fatal("expected an error")
+	}
+
+	logs := requestLogs(t, &output)
+	login := findRequestLog(t, logs, "POST /identity/connect/token", "Request to Bitwarden Secrets Manager succeeded")
+	if login["http_status"] != float64(http.StatusOK) {
+		t.Fatalf("unexpected login log: %v", login)
+	}
+	failed := findRequestLog(t, logs, "GET /api/forbidden", "Request to Bitwarden Secrets Manager failed")
+	if failed["http_status"] != float64(http.StatusForbidden) {
+		t.Fatalf("unexpected error log: %v", failed)
+	}
+	if strings.Contains(output.String(), testBearerToken) {
+		t.Fatalf("expected the bearer token to be masked in the logs: %s", output.String())
+	}
+}
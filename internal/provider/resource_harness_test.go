@@ -0,0 +1,329 @@
+package provider
+
+import (
+	"context"
+	"github.com/bitwarden/sdk-go"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"strings"
+	"testing"
+)
+
+// resourceHarness drives a resource of the provider through the plugin protocol, like terraform does during plan,
+// apply and refresh, without a terraform binary. Ephemeral resources are driven with open and close, see ephemeral. The provider is
+// configured with the given Bitwarden client. The RPCs use ctx, see interrupted.
+type resourceHarness struct {
+	t          *testing.T
+	ctx        context.Context
+	server     tfprotov6.ProviderServer
+	typeName   string
+	schema     *tfprotov6.Schema
+	objectType tftypes.Object
+
+	// apiClientsCreated counts the API clients created by the provider, see lazyAPIClient.
+	apiClientsCreated *int
+}
+
+// resourceState is the state of a resource instance as terraform stores it. A null value means the instance does
+// not exist.
+type resourceState struct {
+	value   tftypes.Value
+	private []byte
+}
+
+func newResourceHarness(t *testing.T, typeName string, bitwardenClient sdk.BitwardenClientInterface) *resourceHarness {
+	t.Helper()
+
+	return newResourceHarnessWithConfig(t, typeName, bitwardenClient, nil)
+}
+
+// newResourceHarnessWithConfig is like newResourceHarness, but configures the provider with additional attributes.
+func newResourceHarnessWithConfig(t *testing.T, typeName string, bitwardenClient sdk.BitwardenClientInterface, providerAttributes map[string]tftypes.Value) *resourceHarness {
+	t.Helper()
+
+	ctx := context.Background()
+	apiClientsCreated := 0
+	testProvider := &BitwardenSecretsManagerProvider{
+		version: "test",
+		newBitwardenClient: func(_ *string, _ *string) (sdk.BitwardenClientInterface, error) {
+			return bitwardenClient, nil
+		},
+		newAPIClient: func(apiURL string, identityURL string, accessToken string) (bitwardenAPIClient, error) {
+			apiClientsCreated++
+			return newBitwardenAPIClient(apiURL, identityURL, accessToken)
+		},
+	}
+	server, err := providerserver.NewProtocol6WithError(testProvider)()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	schemaResponse, err := server.GetProviderSchema(ctx, &tfprotov6.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	schema, ok := schemaResponse.ResourceSchemas[typeName]
+	if !ok {
+		t.Fatalf("unknown resource type %s", typeName)
+	}
+
+	providerType := schemaResponse.Provider.ValueType().(tftypes.Object)
+	attributes := map[string]tftypes.Value{
+		"api_url":         tftypes.NewValue(tftypes.String, "https://api.bitwarden.fake"),
+		"identity_url":    tftypes.NewValue(tftypes.String, "https://identity.bitwarden.fake"),
+		"access_token":    tftypes.NewValue(tftypes.String, fakeAccessToken),
+		"organization_id": tftypes.NewValue(tftypes.String, fakeOrganizationID),
+	}
+	for name, value := range providerAttributes {
+		attributes[name] = value
+	}
+	providerConfig := newObjectValue(providerType, attributes)
+	configureResponse, err := server.ConfigureProvider(ctx, &tfprotov6.ConfigureProviderRequest{
+		Config: newDynamicValue(t, providerType, providerConfig),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if diagnosticsHaveError(configureResponse.Diagnostics) {
+		t.Fatalf("unable to configure the provider: %s", diagnosticsString(configureResponse.Diagnostics))
+	}
+
+	return &resourceHarness{
+		t:          t,
+		ctx:        ctx,
+		server:     server,
+		typeName:   typeName,
+		schema:     schema,
+		objectType: schema.ValueType().(tftypes.Object),
+
+		apiClientsCreated: &apiClientsCreated,
+	}
+}
+
+// newObjectValue returns an object of the given type with the given attributes, all other attributes are null.
+func newObjectValue(objectType tftypes.Object, attributes map[string]tftypes.Value) tftypes.Value {
+	values := map[string]tftypes.Value{}
+	for name, attributeType := range objectType.AttributeTypes {
+		values[name] = tftypes.NewValue(attributeType, nil)
+	}
+	for name, value := range attributes {
+		values[name] = value
+	}
+	return tftypes.NewValue(objectType, values)
+}
+
+func newDynamicValue(t *testing.T, valueType tftypes.Type, value tftypes.Value) *tfprotov6.DynamicValue {
+	t.Helper()
+
+	dynamicValue, err := tfprotov6.NewDynamicValue(valueType, value)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	return &dynamicValue
+}
+
+func diagnosticsHaveError(diagnostics []*tfprotov6.Diagnostic) bool {
+	for _, diagnostic := range diagnostics {
+		if diagnostic.Severity == tfprotov6.DiagnosticSeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+func diagnosticsString(diagnostics []*tfprotov6.Diagnostic) string {
+	messages := make([]string, 0, len(diagnostics))
+	for _, diagnostic := range diagnostics {
+		messages = append(messages, diagnostic.Summary+": "+diagnostic.Detail)
+	}
+	return strings.Join(messages, "\n")
+}
+
+// nullState returns the state of a resource instance which does not exist yet.
+func (h *resourceHarness) nullState() *resourceState {
+	return &resourceState{value: tftypes.NewValue(h.objectType, nil)}
+}
+
+// attribute returns the value of a top-level attribute of a state.
+func (h *resourceHarness) attribute(state *resourceState, name string) tftypes.Value {
+	h.t.Helper()
+
+	if state.value.IsNull() {
+		h.t.Fatalf("unable to read %s of a null state", name)
+	}
+	var attributes map[string]tftypes.Value
+	if err := state.value.As(&attributes); err != nil {
+		h.t.Fatalf("unexpected error: %s", err)
+	}
+	return attributes[name]
+}
+
+// stringAttribute returns the value of a top-level string attribute of a state.
+func (h *resourceHarness) stringAttribute(state *resourceState, name string) string {
+	h.t.Helper()
+
+	var value string
+	if err := h.attribute(state, name).As(&value); err != nil {
+		h.t.Fatalf("unexpected error: %s", err)
+	}
+	return value
+}
+
+// proposedNewState merges the configuration with the prior state like terraform does: computed attributes which are
+// not configured keep their prior value.
+func (h *resourceHarness) proposedNewState(config tftypes.Value, prior tftypes.Value) tftypes.Value {
+	if config.IsNull() || prior.IsNull() {
+		return config
+	}
+
+	var configAttributes, priorAttributes map[string]tftypes.Value
+	if err := config.As(&configAttributes); err != nil {
+		h.t.Fatalf("unexpected error: %s", err)
+	}
+	if err := prior.As(&priorAttributes); err != nil {
+		h.t.Fatalf("unexpected error: %s", err)
+	}
+
+	// As returns the attributes of config itself, so they are copied before the prior values are filled in.
+	proposedAttributes := make(map[string]tftypes.Value, len(configAttributes))
+	for name, value := range configAttributes {
+		proposedAttributes[name] = value
+	}
+	for _, attribute := range h.schema.Block.Attributes {
+		if attribute.Computed && configAttributes[attribute.Name].IsNull() {
+			proposedAttributes[attribute.Name] = priorAttributes[attribute.Name]
+		}
+	}
+	return tftypes.NewValue(h.objectType, proposedAttributes)
+}
+
+// apply plans and applies the configuration with the given attributes, all other attributes are null. It returns the
+// new state and the diagnostics of the plan, if it failed, or of the apply.
+func (h *resourceHarness) apply(attributes map[string]tftypes.Value, prior *resourceState) (*resourceState, []*tfprotov6.Diagnostic) {
+	h.t.Helper()
+
+	return h.applyConfig(newObjectValue(h.objectType, attributes), prior)
+}
+
+// destroy plans and applies the deletion of a resource instance.
+func (h *resourceHarness) destroy(prior *resourceState) (*resourceState, []*tfprotov6.Diagnostic) {
+	h.t.Helper()
+
+	return h.applyConfig(tftypes.NewValue(h.objectType, nil), prior)
+}
+
+// validate validates the configuration with the given attributes, all other attributes are null, like terraform
+// validate does.
+func (h *resourceHarness) validate(attributes map[string]tftypes.Value) []*tfprotov6.Diagnostic {
+	h.t.Helper()
+
+	validateResponse, err := h.server.ValidateResourceConfig(h.ctx, &tfprotov6.ValidateResourceConfigRequest{
+		TypeName: h.typeName,
+		Config:   newDynamicValue(h.t, h.objectType, newObjectValue(h.objectType, attributes)),
+	})
+	if err != nil {
+		h.t.Fatalf("unexpected error: %s", err)
+	}
+	return validateResponse.Diagnostics
+}
+
+// plan plans the configuration with the given attributes, all other attributes are null, without applying it. It
+// returns the planned state, which equals the prior state if the plan is empty, and the diagnostics of the plan.
+func (h *resourceHarness) plan(attributes map[string]tftypes.Value, prior *resourceState) (tftypes.Value, []*tfprotov6.Diagnostic) {
+	h.t.Helper()
+
+	planResponse := h.planConfig(newObjectValue(h.objectType, attributes), prior)
+	if diagnosticsHaveError(planResponse.Diagnostics) {
+		return prior.value, planResponse.Diagnostics
+	}
+	planned, err := planResponse.PlannedState.Unmarshal(h.objectType)
+	if err != nil {
+		h.t.Fatalf("unexpected error: %s", err)
+	}
+	return planned, planResponse.Diagnostics
+}
+
+func (h *resourceHarness) planConfig(config tftypes.Value, prior *resourceState) *tfprotov6.PlanResourceChangeResponse {
+	h.t.Helper()
+
+	planResponse, err := h.server.PlanResourceChange(h.ctx, &tfprotov6.PlanResourceChangeRequest{
+		TypeName:         h.typeName,
+		PriorState:       newDynamicValue(h.t, h.objectType, prior.value),
+		ProposedNewState: newDynamicValue(h.t, h.objectType, h.proposedNewState(config, prior.value)),
+		Config:           newDynamicValue(h.t, h.objectType, config),
+		PriorPrivate:     prior.private,
+	})
+	if err != nil {
+		h.t.Fatalf("unexpected error: %s", err)
+	}
+	return planResponse
+}
+
+func (h *resourceHarness) applyConfig(config tftypes.Value, prior *resourceState) (*resourceState, []*tfprotov6.Diagnostic) {
+	h.t.Helper()
+
+	ctx := h.ctx
+	planResponse := h.planConfig(config, prior)
+	if diagnosticsHaveError(planResponse.Diagnostics) {
+		return prior, planResponse.Diagnostics
+	}
+
+	applyResponse, err := h.server.ApplyResourceChange(ctx, &tfprotov6.ApplyResourceChangeRequest{
+		TypeName:       h.typeName,
+		PriorState:     newDynamicValue(h.t, h.objectType, prior.value),
+		PlannedState:   planResponse.PlannedState,
+		Config:         newDynamicValue(h.t, h.objectType, config),
+		PlannedPrivate: planResponse.PlannedPrivate,
+	})
+	if err != nil {
+		h.t.Fatalf("unexpected error: %s", err)
+	}
+
+	return h.newState(applyResponse.NewState, applyResponse.Private), applyResponse.Diagnostics
+}
+
+// read refreshes the state of a resource instance.
+func (h *resourceHarness) read(prior *resourceState) (*resourceState, []*tfprotov6.Diagnostic) {
+	h.t.Helper()
+
+	readResponse, err := h.server.ReadResource(h.ctx, &tfprotov6.ReadResourceRequest{
+		TypeName:     h.typeName,
+		CurrentState: newDynamicValue(h.t, h.objectType, prior.value),
+		Private:      prior.private,
+	})
+	if err != nil {
+		h.t.Fatalf("unexpected error: %s", err)
+	}
+	if diagnosticsHaveError(readResponse.Diagnostics) {
+		// Terraform keeps the prior state if the refresh fails.
+		return prior, readResponse.Diagnostics
+	}
+
+	return h.newState(readResponse.NewState, readResponse.Private), readResponse.Diagnostics
+}
+
+// newState decodes the state returned by the provider. Like terraform, unknown values of a failed apply are stored as
+// null.
+func (h *resourceHarness) newState(dynamicValue *tfprotov6.DynamicValue, private []byte) *resourceState {
+	h.t.Helper()
+
+	if dynamicValue == nil {
+		return h.nullState()
+	}
+	value, err := dynamicValue.Unmarshal(h.objectType)
+	if err != nil {
+		h.t.Fatalf("unexpected error: %s", err)
+	}
+
+	value, err = tftypes.Transform(value, func(_ *tftypes.AttributePath, v tftypes.Value) (tftypes.Value, error) {
+		if !v.IsKnown() {
+			return tftypes.NewValue(v.Type(), nil), nil
+		}
+		return v, nil
+	})
+	if err != nil {
+		h.t.Fatalf("unexpected error: %s", err)
+	}
+	return &resourceState{value: value, private: private}
+}
@@ -0,0 +1,155 @@
+package provider
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/bitwarden/sdk-go"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// secretBackup writes an encrypted copy of every secret to a directory before the secret is deleted. The copies are
+// encrypted with a key derived from the configured backup key, which does not change with the access token, so they
+// can be decrypted after the access token was rotated, see decryptSecretBackup and the decrypt_secret_backup function.
+type secretBackup struct {
+	dir string
+	key symmetricKey
+}
+
+// secretBackupFile is the content of a backup file. Only the identifiers of the secret are stored in plain text.
+type secretBackupFile struct {
+	ID             string  `json:"id"`
+	OrganizationID string  `json:"organization_id"`
+	ProjectID      *string `json:"project_id"`
+	DeletedAt      string  `json:"deleted_at"`
+	// Payload is an EncString of type 2 of the JSON encoded secretBackupPayload.
+	Payload string `json:"payload"`
+}
+
+// secretBackupPayload is the encrypted part of a backup file.
+type secretBackupPayload struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+	Note  string `json:"note"`
+}
+
+// minSecretBackupKeyLength is the minimum length of the decoded backup key in bytes.
+const minSecretBackupKeyLength = 32
+
+func newSecretBackup(dir string, backupKey string) (*secretBackup, error) {
+	key, err := secretBackupKey(backupKey)
+	if err != nil {
+		return nil, err
+	}
+	return &secretBackup{dir: dir, key: key}, nil
+}
+
+// secretBackupKey derives the key of backup files from the base64 encoded backup key. A separate key is derived, so
+// backup files cannot be mistaken for other data encrypted with the backup key.
+func secretBackupKey(backupKey string) (symmetricKey, error) {
+	secret, err := base64.StdEncoding.DecodeString(backupKey)
+	if err != nil {
+		return symmetricKey{}, errors.New("the backup key must be base64 encoded")
+	}
+	if len(secret) < minSecretBackupKeyLength {
+		return symmetricKey{}, fmt.Errorf("the backup key must be at least %d bytes long, got %d bytes", minSecretBackupKeyLength, len(secret))
+	}
+	return deriveShareableKey(secret, "secretbackup", "sm-secret-backup"), nil
+}
+
+// write stores a backup file for every secret. The file of a secret is named after its ID and the time of the backup,
+// so earlier backups of the same secret are kept.
+func (b *secretBackup) write(secrets []sdk.SecretResponse) error {
+	if err := os.MkdirAll(b.dir, 0o700); err != nil {
+		return fmt.Errorf("unable to create the backup directory: %w", err)
+	}
+
+	now := time.Now().UTC()
+	for _, secret := range secrets {
+		payload, err := json.Marshal(secretBackupPayload{Key: secret.Key, Value: secret.Value, Note: secret.Note})
+		if err != nil {
+			return err
+		}
+		encrypted, err := encryptString(b.key, payload)
+		if err != nil {
+			return fmt.Errorf("unable to encrypt the backup of secret %s: %w", secret.ID, err)
+		}
+
+		content, err := json.MarshalIndent(secretBackupFile{
+			ID:             secret.ID,
+			OrganizationID: secret.OrganizationID,
+			ProjectID:      secret.ProjectID,
+			DeletedAt:      now.Format(time.RFC3339Nano),
+			Payload:        encrypted,
+		}, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		name := fmt.Sprintf("%s-%s.json", secret.ID, now.Format("20060102T150405.000000000Z"))
+		if err := os.WriteFile(filepath.Join(b.dir, name), content, 0o600); err != nil {
+			return fmt.Errorf("unable to write the backup of secret %s: %w", secret.ID, err)
+		}
+	}
+	return nil
+}
+
+// decryptSecretBackup decrypts the payload of a backup file with the backup key which was used to write it.
+func decryptSecretBackup(backupKey string, backupFile secretBackupFile) (*secretBackupPayload, error) {
+	key, err := secretBackupKey(backupKey)
+	if err != nil {
+		return nil, err
+	}
+	decrypted, err := decryptString(key, backupFile.Payload)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decrypt the backup of secret %s: %w", backupFile.ID, err)
+	}
+
+	var payload secretBackupPayload
+	if err := json.Unmarshal(decrypted, &payload); err != nil {
+		return nil, fmt.Errorf("unable to decode the backup of secret %s: %w", backupFile.ID, err)
+	}
+	return &payload, nil
+}
+
+// backupSecrets writes a backup of the given secrets, secrets which do not exist anymore are skipped. The backup is
+// skipped entirely if backup is nil.
+func backupSecrets(bitwardenClient sdk.BitwardenClientInterface, backup *secretBackup, ids []string) error {
+	if backup == nil {
+		return nil
+	}
+
+	for _, batch := range batchIDs(ids, secretsBatchSize) {
+		response, err := bitwardenClient.Secrets().GetByIDS(batch)
+		if err == nil {
+			if response == nil {
+				continue
+			}
+			if err := backup.write(response.Data); err != nil {
+				return err
+			}
+			continue
+		}
+		if !isNotFound(err) {
+			return fmt.Errorf("unable to read the secrets to back up: %w", err)
+		}
+
+		// The server rejects a whole batch if one of its secrets does not exist.
+		for _, id := range batch {
+			secret, err := bitwardenClient.Secrets().Get(id)
+			if err != nil {
+				if isNotFound(err) {
+					continue
+				}
+				return fmt.Errorf("unable to read the secret %s to back up: %w", id, err)
+			}
+			if err := backup.write([]sdk.SecretResponse{*secret}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
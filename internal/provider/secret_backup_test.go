@@ -0,0 +1,216 @@
+package provider
+
+import (
+	"encoding/json"
+	"github.com/bitwarden/sdk-go"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// testBackupKey is the backup_key of the provider in the tests, 32 bytes encoded in base64.
+const testBackupKey = "AAECAwQFBgcICQoLDA0ODxAREhMUFRYXGBkaGxwdHh8="
+
+// readSecretBackups decodes all backup files of a directory.
+func readSecretBackups(t *testing.T, dir string) []secretBackupFile {
+	t.Helper()
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	backups := make([]secretBackupFile, 0, len(entries))
+	for _, entry := range entries {
+		content, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		var backup secretBackupFile
+		if err := json.Unmarshal(content, &backup); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		backups = append(backups, backup)
+	}
+	return backups
+}
+
+func TestSecretBackup(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "backups")
+	backup, err := newSecretBackup(dir, testBackupKey)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	projectID := "4ba2c6d4-1c3e-4a0f-8bde-13c1b0b1e1f7"
+	secret := sdk.SecretResponse{ID: "secret-id", OrganizationID: fakeOrganizationID, ProjectID: &projectID, Key: "API_KEY", Value: "super-secret-value", Note: "note"}
+	if err := backup.write([]sdk.SecretResponse{secret}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(entries) != 1 || !strings.HasPrefix(entries[0].Name(), "secret-id-") {
+		t.Fatalf("expected a single backup file of the secret, got %v", entries)
+	}
+	info, err := entries[0].Info()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Fatalf("expected the backup file to be readable by its owner only, got %s", info.Mode().Perm())
+	}
+	content, err := os.ReadFile(filepath.Join(dir, entries[0].Name()))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if strings.Contains(string(content), secret.Value) || strings.Contains(string(content), secret.Key) {
+		t.Fatalf("expected the key and value to be encrypted, got %s", content)
+	}
+
+	backups := readSecretBackups(t, dir)
+	if backups[0].ID != secret.ID || *backups[0].ProjectID != projectID || backups[0].OrganizationID != fakeOrganizationID {
+		t.Fatalf("unexpected backup identifiers: %+v", backups[0])
+	}
+	payload, err := decryptSecretBackup(testBackupKey, backups[0])
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if *payload != (secretBackupPayload{Key: secret.Key, Value: secret.Value, Note: secret.Note}) {
+		t.Fatalf("unexpected backup payload: %+v", payload)
+	}
+
+	// Another backup key cannot decrypt the backup.
+	otherBackupKey := "Hx4dHBsaGRgXFhUUExIREA8ODQwLCgkIBwYFBAMCAQA="
+	if _, err := decryptSecretBackup(otherBackupKey, backups[0]); err == nil {
+		t.Fatal("expected an error for another backup key")
+	}
+}
+
+func TestSecretBackupInvalidBackupKey(t *testing.T) {
+	tests := map[string]string{
+		"not base64": "not base64!",
+		"too short":  "AAECAwQFBgcICQoLDA0ODw==",
+	}
+
+	for name, backupKey := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := newSecretBackup(t.TempDir(), backupKey); err == nil {
+				t.Fatalf("expected an error for %q", backupKey)
+			}
+		})
+	}
+}
+
+func TestProviderBackupKey(t *testing.T) {
+	tests := map[string]struct {
+		backupKey tftypes.Value
+		env       string
+		expected  string
+	}{
+		"missing": {
+			backupKey: tftypes.NewValue(tftypes.String, nil),
+			expected:  "Missing Backup Key",
+		},
+		"invalid": {
+			backupKey: tftypes.NewValue(tftypes.String, "AAECAwQFBgcICQoLDA0ODw=="),
+			expected:  "Invalid Backup Key",
+		},
+		"invalid environment variable": {
+			backupKey: tftypes.NewValue(tftypes.String, nil),
+			env:       "AAECAwQFBgcICQoLDA0ODw==",
+			expected:  "Invalid Backup Key",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("BW_BACKUP_KEY", test.env)
+			config := standInProviderConfig("https://api.bitwarden.fake", "https://identity.bitwarden.fake", fakeAccessToken)
+			config["backup_dir"] = tftypes.NewValue(tftypes.String, t.TempDir())
+			config["backup_key"] = test.backupKey
+
+			response := configureProvider(t, config)
+			if !response.Diagnostics.HasError() || response.Diagnostics.Errors()[0].Summary() != test.expected {
+				t.Fatalf("expected an error %q, got: %v", test.expected, response.Diagnostics)
+			}
+		})
+	}
+}
+
+func TestSecretResourceBackup(t *testing.T) {
+	dir := t.TempDir()
+	store := newFakeBitwardenStore(fakeOrganizationID)
+	harness := newResourceHarnessWithConfig(t, "bitwarden-sm_secret", store.newClient(), map[string]tftypes.Value{
+		"backup_dir": tftypes.NewValue(tftypes.String, dir),
+		"backup_key": tftypes.NewValue(tftypes.String, testBackupKey),
+	})
+
+	state, diagnostics := harness.apply(secretConfig("value"), harness.nullState())
+	expectDiagnosticError(t, diagnostics, "")
+	id := harness.stringAttribute(state, "id")
+
+	_, diagnostics = harness.destroy(state)
+	expectDiagnosticError(t, diagnostics, "")
+
+	backups := readSecretBackups(t, dir)
+	if len(backups) != 1 || backups[0].ID != id {
+		t.Fatalf("expected a backup of secret %s, got %+v", id, backups)
+	}
+	payload, err := decryptSecretBackup(testBackupKey, backups[0])
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if payload.Key != "API_KEY" || payload.Value != "value" {
+		t.Fatalf("unexpected backup payload: %+v", payload)
+	}
+}
+
+func TestSecretResourceBackupFailureKeepsSecret(t *testing.T) {
+	// A file in place of the backup directory makes every backup fail.
+	dir := filepath.Join(t.TempDir(), "backups")
+	if err := os.WriteFile(dir, nil, 0o600); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	store := newFakeBitwardenStore(fakeOrganizationID)
+	harness := newResourceHarnessWithConfig(t, "bitwarden-sm_secret", store.newClient(), map[string]tftypes.Value{
+		"backup_dir": tftypes.NewValue(tftypes.String, dir),
+		"backup_key": tftypes.NewValue(tftypes.String, testBackupKey),
+	})
+
+	state, diagnostics := harness.apply(secretConfig("value"), harness.nullState())
+	expectDiagnosticError(t, diagnostics, "")
+
+	_, diagnostics = harness.destroy(state)
+	expectDiagnosticError(t, diagnostics, "unable to create the backup directory")
+	if stored := len(storedValues(store)); stored != 1 {
+		t.Fatalf("expected the secret to be kept, got %d stored secrets", stored)
+	}
+}
+
+func TestBackupSecretsSkipsMissingSecrets(t *testing.T) {
+	store := newFakeBitwardenStore(fakeOrganizationID)
+	client := store.newClient()
+	if err := client.AccessTokenLogin(fakeAccessToken, nil); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	secret, err := client.Secrets().Create("API_KEY", "value", "", fakeOrganizationID, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	dir := t.TempDir()
+	backup, err := newSecretBackup(dir, testBackupKey)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := backupSecrets(client, backup, []string{secret.ID, "d1b6f0f3-9f5c-4c3b-a1c4-7c0e5ab3c2a1"}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if backups := readSecretBackups(t, dir); len(backups) != 1 || backups[0].ID != secret.ID {
+		t.Fatalf("expected a backup of the existing secret only, got %+v", backups)
+	}
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/hashicorp/terraform-plugin-framework/types"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/datasource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
@@ -30,21 +31,24 @@ type secretDataSource struct {
 }
 
 type secretDataSourceModel struct {
-	ID             types.String `tfsdk:"id"`
-	Key            types.String `tfsdk:"key"`
-	Value          types.String `tfsdk:"value"`
-	Note           types.String `tfsdk:"note"`
-	ProjectID      types.String `tfsdk:"project_id"`
-	OrganizationID types.String `tfsdk:"organization_id"`
-	CreationDate   types.String `tfsdk:"creation_date"`
-	RevisionDate   types.String `tfsdk:"revision_date"`
+	ID             types.String  `tfsdk:"id"`
+	Key            types.String  `tfsdk:"key"`
+	Value          types.String  `tfsdk:"value"`
+	ValueJSON      types.Dynamic `tfsdk:"value_json"`
+	Note           types.String  `tfsdk:"note"`
+	ProjectID      types.String  `tfsdk:"project_id"`
+	OrganizationID types.String  `tfsdk:"organization_id"`
+	CreationDate   types.String  `tfsdk:"creation_date"`
+	RevisionDate   types.String  `tfsdk:"revision_date"`
+
+	Timeouts timeouts.Value `tfsdk:"timeouts"`
 }
 
 func (s *secretDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
 	resp.TypeName = req.ProviderTypeName + "_secret"
 }
 
-func (s *secretDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+func (s *secretDataSource) Schema(ctx context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		Description:         "The secret data source fetches a particular secret from Bitwarden Secrets Manager based on a given ID.",
 		MarkdownDescription: "The `secret` data source fetches a particular secret from Bitwarden Secrets Manager based on a given `ID`.",
@@ -68,6 +72,12 @@ func (s *secretDataSource) Schema(_ context.Context, _ datasource.SchemaRequest,
 				Computed:            true,
 				Sensitive:           true,
 			},
+			"value_json": schema.DynamicAttribute{
+				Description:         "Structured representation of the value of the secret. JSON objects are decoded into objects and JSON arrays into tuples, so nested fields can be accessed without jsondecode(). All other values, including JSON scalars like 123 or true, are returned as string. This attribute is sensitive.",
+				MarkdownDescription: "Structured representation of the `value` of the secret. JSON objects are decoded into objects and JSON arrays into tuples, so nested fields can be accessed without `jsondecode()`. All other values, including JSON scalars like `123` or `true`, are returned as string. This attribute is sensitive.",
+				Computed:            true,
+				Sensitive:           true,
+			},
 			"note": schema.StringAttribute{
 				Description:         "String representation of the note of the secret inside Bitwarden Secrets Manager.",
 				MarkdownDescription: "String representation of the `note` of the secret inside Bitwarden Secrets Manager.",
@@ -92,6 +102,9 @@ func (s *secretDataSource) Schema(_ context.Context, _ datasource.SchemaRequest,
 				Computed:    true,
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx),
+		},
 	}
 }
 
@@ -157,7 +170,13 @@ func (s *secretDataSource) Read(ctx context.Context, req datasource.ReadRequest,
 		return
 	}
 
-	secret, err := s.bitwardenClient.Secrets().Get(state.ID.ValueString())
+	ctx, client, done := withClientTimeout(ctx, s.bitwardenClient, state.Timeouts.Read, "read", "the secret data source", &resp.Diagnostics)
+	defer done()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	secret, err := client.Secrets().Get(state.ID.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to Read Secret with id: "+state.ID.ValueString(),
@@ -169,11 +188,13 @@ func (s *secretDataSource) Read(ctx context.Context, req datasource.ReadRequest,
 	state.Key = types.StringValue(secret.Key)
 	state.Value = types.StringValue(secret.Value)
 	state.Note = types.StringValue(secret.Note)
-	state.ProjectID = types.StringValue(*secret.ProjectID)
+	state.ProjectID = types.StringPointerValue(secret.ProjectID)
 	state.OrganizationID = types.StringValue(secret.OrganizationID)
 	state.CreationDate = types.StringValue(secret.CreationDate.String())
 	state.RevisionDate = types.StringValue(secret.RevisionDate.String())
 
+	state.ValueJSON = decodeSecretJSONValue(secret.Value)
+
 	// Set state
 	diags = resp.State.Set(ctx, &state)
 	resp.Diagnostics.Append(diags...)
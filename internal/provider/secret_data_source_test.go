@@ -13,7 +13,7 @@ const (
 )
 
 func TestAccDatasourceSecretExpectErrorOnMissingSecretId(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	runProviderTest(t, resource.TestCase{
 		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
@@ -26,7 +26,7 @@ func TestAccDatasourceSecretExpectErrorOnMissingSecretId(t *testing.T) {
 }
 
 func TestAccDatasourceSecretExpectErrorOnInvalidSecretId(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	runProviderTest(t, resource.TestCase{
 		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
@@ -77,7 +77,7 @@ func TestAccDatasourceSecretVerifySecretData(t *testing.T) {
 	}
 	secretId = secret.ID
 
-	resource.Test(t, resource.TestCase{
+	runProviderTest(t, resource.TestCase{
 		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
@@ -107,3 +107,62 @@ func TestAccDatasourceSecretVerifySecretData(t *testing.T) {
 		},
 	})
 }
+
+func TestAccDatasourceSecretVerifyJSONSecretData(t *testing.T) {
+	var secretId, projectId string
+	secretKey := "Test-Secret-" + generateRandomString()
+	secretValue := `{"user": "admin", "port": 5432, "nested": {"token": "abc"}}`
+	projectName := "Test-Project-" + generateRandomString()
+	bitwardenClient, organizationId, err := newBitwardenClient()
+
+	if err != nil {
+		t.Fatalf("Error creating bitwardenClient: %s", err)
+	}
+
+	project, preCheckError := bitwardenClient.Projects().Create(organizationId, projectName)
+	if preCheckError != nil {
+		t.Fatal("Error creating test project for provider validation.")
+	}
+	projectId = project.ID
+
+	secret, preCheckError := bitwardenClient.Secrets().Create(
+		secretKey,
+		secretValue,
+		"",
+		organizationId,
+		[]string{projectId},
+	)
+	if preCheckError != nil {
+		t.Fatal("Error creating test secret for provider validation.")
+	}
+	secretId = secret.ID
+
+	runProviderTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: buildProviderConfigFromEnvFile(t) + `
+                            data "bitwarden-sm_secret" "secret" {
+                                id ="` + secretId + `"
+                            }`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.bitwarden-sm_secret.secret", "value", secretValue),
+					resource.TestCheckResourceAttr("data.bitwarden-sm_secret.secret", "value_json.user", "admin"),
+					resource.TestCheckResourceAttr("data.bitwarden-sm_secret.secret", "value_json.port", "5432"),
+					resource.TestCheckResourceAttr("data.bitwarden-sm_secret.secret", "value_json.nested.token", "abc"),
+				),
+			},
+		},
+		CheckDestroy: func(state *terraform.State) error {
+			_, cleanUpErr := bitwardenClient.Secrets().Delete([]string{secretId})
+			if cleanUpErr != nil {
+				t.Fatalf("Error cleaning up test secret: %s", cleanUpErr)
+			}
+			_, cleanUpErr = bitwardenClient.Projects().Delete([]string{projectId})
+			if cleanUpErr != nil {
+				t.Fatalf("Error cleaning up test project: %s", cleanUpErr)
+			}
+			return nil
+		},
+	})
+}
@@ -0,0 +1,129 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"github.com/bitwarden/sdk-go"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"strings"
+)
+
+// secretDeleteError is the failed deletion of a single secret of a bulk deletion.
+type secretDeleteError struct {
+	id      string
+	message string
+}
+
+func (e *secretDeleteError) Error() string {
+	return fmt.Sprintf("The secret with id %s could not be deleted: %s", e.id, e.message)
+}
+
+// deleteSecrets deletes the given secrets in batches of secretsBatchSize. Secrets which do not exist anymore count as
+// deleted. If backup is not nil, every batch is backed up before it is deleted and not deleted if the backup fails.
+// The failed deletions of single secrets are joined into the returned error, see addDeleteDiagnostics.
+func deleteSecrets(bitwardenClient sdk.BitwardenClientInterface, backup *secretBackup, ids []string) error {
+	var errs []error
+	for _, batch := range batchIDs(ids, secretsBatchSize) {
+		if err := backupSecrets(bitwardenClient, backup, batch); err != nil {
+			return errors.Join(append(errs, err)...)
+		}
+
+		response, err := bitwardenClient.Secrets().Delete(batch)
+		if err != nil {
+			if !isNotFound(err) {
+				return errors.Join(append(errs, err)...)
+			}
+			if len(batch) == 1 {
+				continue
+			}
+
+			// The server rejects a whole batch if one of its secrets does not exist, so the secrets of the batch are
+			// deleted one by one to tell the missing secrets apart from the others.
+			for _, id := range batch {
+				err := deleteSecret(bitwardenClient, id)
+				if isContextError(err) {
+					return errors.Join(append(errs, err)...)
+				}
+				if err != nil {
+					errs = append(errs, err)
+				}
+			}
+			continue
+		}
+		errs = append(errs, secretDeleteErrors(response)...)
+	}
+	return errors.Join(errs...)
+}
+
+// deleteSecret deletes a single secret of a batch rejected by the server. Errors of the context are returned as they
+// are, so the caller stops instead of trying the remaining secrets.
+func deleteSecret(bitwardenClient sdk.BitwardenClientInterface, id string) error {
+	response, err := bitwardenClient.Secrets().Delete([]string{id})
+	if err != nil {
+		if isNotFound(err) {
+			return nil
+		}
+		if isContextError(err) {
+			return err
+		}
+		return &secretDeleteError{id: id, message: err.Error()}
+	}
+	return errors.Join(secretDeleteErrors(response)...)
+}
+
+// secretDeleteErrors returns the failed deletions of a delete response. The response does not necessarily contain a
+// result for every secret, secrets without a result count as deleted.
+func secretDeleteErrors(response *sdk.SecretsDeleteResponse) []error {
+	if response == nil {
+		return nil
+	}
+
+	var errs []error
+	for _, result := range response.Data {
+		if result.Error == nil || isNotFoundMessage(*result.Error) {
+			continue
+		}
+		errs = append(errs, &secretDeleteError{id: result.ID, message: *result.Error})
+	}
+	return errs
+}
+
+func isNotFoundMessage(message string) bool {
+	return strings.Contains(strings.ToLower(message), "not found")
+}
+
+// failedSecretIDs returns the IDs of the secrets whose deletion failed. It reports false if the error is not only
+// made up of failed deletions of single secrets, in which case it is unknown which secrets were deleted.
+func failedSecretIDs(err error) (map[string]bool, bool) {
+	failed := map[string]bool{}
+	for _, err := range splitErrors(err) {
+		var deleteErr *secretDeleteError
+		if !errors.As(err, &deleteErr) {
+			return nil, false
+		}
+		failed[deleteErr.id] = true
+	}
+	return failed, true
+}
+
+// addDeleteDiagnostics adds an error diagnostic for every failed deletion of a single secret and for every other error.
+func addDeleteDiagnostics(diags *diag.Diagnostics, summary string, err error) {
+	for _, err := range splitErrors(err) {
+		diags.AddError(summary, err.Error())
+	}
+}
+
+// splitErrors returns the errors joined by errors.Join.
+func splitErrors(err error) []error {
+	if err == nil {
+		return nil
+	}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		var errs []error
+		for _, err := range joined.Unwrap() {
+			errs = append(errs, splitErrors(err)...)
+		}
+		return errs
+	}
+	return []error{err}
+}
@@ -0,0 +1,232 @@
+package provider
+
+import (
+	"errors"
+	"github.com/bitwarden/sdk-go"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"net/http"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+// scriptedDeleteClient wraps a Bitwarden client and answers delete requests of secrets with the given function.
+type scriptedDeleteClient struct {
+	sdk.BitwardenClientInterface
+
+	mu      sync.Mutex
+	respond func(ids []string) (*sdk.SecretsDeleteResponse, error)
+	calls   [][]string
+}
+
+func (c *scriptedDeleteClient) Secrets() sdk.SecretsInterface {
+	return &scriptedDeleteSecrets{SecretsInterface: c.BitwardenClientInterface.Secrets(), client: c}
+}
+
+type scriptedDeleteSecrets struct {
+	sdk.SecretsInterface
+	client *scriptedDeleteClient
+}
+
+func (s *scriptedDeleteSecrets) Delete(secretIDs []string) (*sdk.SecretsDeleteResponse, error) {
+	s.client.mu.Lock()
+	s.client.calls = append(s.client.calls, secretIDs)
+	s.client.mu.Unlock()
+
+	return s.client.respond(secretIDs)
+}
+
+func deleteResult(id string, message string) sdk.SecretDeleteResponse {
+	if message == "" {
+		return sdk.SecretDeleteResponse{ID: id}
+	}
+	return sdk.SecretDeleteResponse{ID: id, Error: &message}
+}
+
+func TestDeleteSecrets(t *testing.T) {
+	notFound := fakeAPIError(http.StatusNotFound, `{"message":"Resource not found.","object":"error"}`)
+
+	tests := map[string]struct {
+		respond func(ids []string) (*sdk.SecretsDeleteResponse, error)
+		// failed are the IDs of the secrets whose deletion is reported as failed, nil if the error is not made up of
+		// failed deletions of single secrets.
+		failed      map[string]bool
+		diagnostics int
+		calls       int
+	}{
+		"all deleted": {
+			respond: func(ids []string) (*sdk.SecretsDeleteResponse, error) {
+				return &sdk.SecretsDeleteResponse{Data: []sdk.SecretDeleteResponse{deleteResult(ids[0], ""), deleteResult(ids[1], "")}}, nil
+			},
+			failed: map[string]bool{},
+			calls:  1,
+		},
+		"empty response": {
+			respond: func(_ []string) (*sdk.SecretsDeleteResponse, error) {
+				return &sdk.SecretsDeleteResponse{}, nil
+			},
+			failed: map[string]bool{},
+			calls:  1,
+		},
+		"nil response": {
+			respond: func(_ []string) (*sdk.SecretsDeleteResponse, error) {
+				return nil, nil
+			},
+			failed: map[string]bool{},
+			calls:  1,
+		},
+		"missing secret reported per id": {
+			respond: func(ids []string) (*sdk.SecretsDeleteResponse, error) {
+				return &sdk.SecretsDeleteResponse{Data: []sdk.SecretDeleteResponse{deleteResult(ids[0], "Secret not found."), deleteResult(ids[1], "")}}, nil
+			},
+			failed: map[string]bool{},
+			calls:  1,
+		},
+		"partial errors": {
+			respond: func(ids []string) (*sdk.SecretsDeleteResponse, error) {
+				return &sdk.SecretsDeleteResponse{Data: []sdk.SecretDeleteResponse{deleteResult(ids[0], "access denied"), deleteResult(ids[1], "access denied")}}, nil
+			},
+			failed:      map[string]bool{"id-1": true, "id-2": true},
+			diagnostics: 2,
+			calls:       1,
+		},
+		"batch rejected as not found": {
+			respond: func(ids []string) (*sdk.SecretsDeleteResponse, error) {
+				if len(ids) > 1 || ids[0] == "id-1" {
+					return nil, notFound
+				}
+				return &sdk.SecretsDeleteResponse{Data: []sdk.SecretDeleteResponse{deleteResult(ids[0], "")}}, nil
+			},
+			failed: map[string]bool{},
+			calls:  3,
+		},
+		"batch rejected as not found with partial errors": {
+			respond: func(ids []string) (*sdk.SecretsDeleteResponse, error) {
+				switch {
+				case len(ids) > 1:
+					return nil, notFound
+				case ids[0] == "id-1":
+					return nil, fakeAPIError(http.StatusInternalServerError, `{"message":"An error has occurred.","object":"error"}`)
+				}
+				return &sdk.SecretsDeleteResponse{Data: []sdk.SecretDeleteResponse{deleteResult(ids[0], "access denied")}}, nil
+			},
+			failed:      map[string]bool{"id-1": true, "id-2": true},
+			diagnostics: 2,
+			calls:       3,
+		},
+		"request failed": {
+			respond: func(_ []string) (*sdk.SecretsDeleteResponse, error) {
+				return nil, errFaultTimeout
+			},
+			diagnostics: 1,
+			calls:       1,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			client := &scriptedDeleteClient{
+				BitwardenClientInterface: newFakeBitwardenStore(fakeOrganizationID).newClient(),
+				respond:                  test.respond,
+			}
+
+			err := deleteSecrets(client, nil, []string{"id-1", "id-2"})
+
+			failed, known := failedSecretIDs(err)
+			if known != (test.failed != nil) || !reflect.DeepEqual(failed, test.failed) {
+				t.Fatalf("expected failed secrets %v, got %v (%v)", test.failed, failed, err)
+			}
+			var diagnostics diag.Diagnostics
+			addDeleteDiagnostics(&diagnostics, "Unable to Delete Secrets", err)
+			if diagnostics.ErrorsCount() != test.diagnostics {
+				t.Fatalf("expected %d diagnostics, got %v", test.diagnostics, diagnostics)
+			}
+			if len(client.calls) != test.calls {
+				t.Fatalf("expected %d delete calls, got %v", test.calls, client.calls)
+			}
+		})
+	}
+}
+
+func TestDeleteSecretsBatches(t *testing.T) {
+	ids := make([]string, secretsBatchSize+1)
+	for i := range ids {
+		ids[i] = "id"
+	}
+	client := &scriptedDeleteClient{
+		BitwardenClientInterface: newFakeBitwardenStore(fakeOrganizationID).newClient(),
+		respond: func(ids []string) (*sdk.SecretsDeleteResponse, error) {
+			return &sdk.SecretsDeleteResponse{Data: []sdk.SecretDeleteResponse{deleteResult(ids[0], "access denied")}}, nil
+		},
+	}
+
+	err := deleteSecrets(client, nil, ids)
+	if len(client.calls) != 2 {
+		t.Fatalf("expected 2 delete calls, got %d", len(client.calls))
+	}
+	if errs := splitErrors(err); len(errs) != 2 {
+		t.Fatalf("expected an error per batch, got %v", errs)
+	}
+}
+
+func TestSplitErrors(t *testing.T) {
+	first, second, third := errors.New("first"), errors.New("second"), errors.New("third")
+
+	if errs := splitErrors(nil); len(errs) != 0 {
+		t.Fatalf("expected no errors, got %v", errs)
+	}
+	if errs := splitErrors(first); !reflect.DeepEqual(errs, []error{first}) {
+		t.Fatalf("expected the error itself, got %v", errs)
+	}
+	errs := splitErrors(errors.Join(first, errors.Join(second, third)))
+	if !reflect.DeepEqual(errs, []error{first, second, third}) {
+		t.Fatalf("expected the joined errors, got %v", errs)
+	}
+}
+
+func TestSecretResourceDeleteResponses(t *testing.T) {
+	tests := map[string]struct {
+		respond  func(ids []string) (*sdk.SecretsDeleteResponse, error)
+		expected string
+	}{
+		"empty response": {
+			respond: func(_ []string) (*sdk.SecretsDeleteResponse, error) {
+				return &sdk.SecretsDeleteResponse{Data: []sdk.SecretDeleteResponse{}}, nil
+			},
+		},
+		"not found": {
+			respond: func(_ []string) (*sdk.SecretsDeleteResponse, error) {
+				return nil, fakeAPIError(http.StatusNotFound, `{"message":"Resource not found.","object":"error"}`)
+			},
+		},
+		"secret not found": {
+			respond: func(ids []string) (*sdk.SecretsDeleteResponse, error) {
+				return &sdk.SecretsDeleteResponse{Data: []sdk.SecretDeleteResponse{deleteResult(ids[0], "Secret not found.")}}, nil
+			},
+		},
+		"access denied": {
+			respond: func(ids []string) (*sdk.SecretsDeleteResponse, error) {
+				return &sdk.SecretsDeleteResponse{Data: []sdk.SecretDeleteResponse{deleteResult(ids[0], "access denied")}}, nil
+			},
+			expected: "could not be deleted: access denied",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			client := &scriptedDeleteClient{
+				BitwardenClientInterface: newFakeBitwardenStore(fakeOrganizationID).newClient(),
+				respond:                  test.respond,
+			}
+			harness := newResourceHarness(t, "bitwarden-sm_secret", client)
+			state, diagnostics := harness.apply(secretConfig("value"), harness.nullState())
+			expectDiagnosticError(t, diagnostics, "")
+
+			state, diagnostics = harness.destroy(state)
+			expectDiagnosticError(t, diagnostics, test.expected)
+			if test.expected == "" && !state.value.IsNull() {
+				t.Fatal("expected the destroy to remove the state")
+			}
+		})
+	}
+}
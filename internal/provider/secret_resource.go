@@ -3,13 +3,16 @@ package provider
 import (
 	"fmt"
 	"github.com/bitwarden/sdk-go"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
@@ -22,6 +25,18 @@ var (
 	_ resource.Resource                = &secretResource{}
 	_ resource.ResourceWithConfigure   = &secretResource{}
 	_ resource.ResourceWithImportState = &secretResource{}
+	_ resource.ResourceWithModifyPlan  = &secretResource{}
+)
+
+const (
+	// Default values of the secret generator attributes.
+	defaultAvoidAmbiguous = false
+	defaultLength         = 64
+	defaultLowercase      = true
+	defaultMinimum        = 1
+	defaultNumbers        = true
+	defaultSpecial        = false
+	defaultUppercase      = true
 )
 
 // NewSecretResource is a helper function to simplify the provider implementation.
@@ -33,37 +48,56 @@ func NewSecretResource() resource.Resource {
 type secretResource struct {
 	bitwardenClient sdk.BitwardenClientInterface
 	organizationId  string
+	keyNamingPolicy *keyNamingPolicy
+	// enforceUniqueKey is the unique key mode of the provider, used if the resource does not configure one.
+	enforceUniqueKey string
+	// secretBackup is nil if deleted secrets are not backed up.
+	secretBackup *secretBackup
 }
 
 type secretResourceModel struct {
-	ID             types.String `tfsdk:"id"`
-	Key            types.String `tfsdk:"key"`
-	Value          types.String `tfsdk:"value"`
-	Note           types.String `tfsdk:"note"`
-	ProjectID      types.String `tfsdk:"project_id"`
-	OrganizationID types.String `tfsdk:"organization_id"`
-	CreationDate   types.String `tfsdk:"creation_date"`
-	RevisionDate   types.String `tfsdk:"revision_date"`
-	AvoidAmbiguous types.Bool   `tfsdk:"avoid_ambiguous"`
-	Length         types.Int64  `tfsdk:"length"`
-	Lowercase      types.Bool   `tfsdk:"lowercase"`
-	MinLowercase   types.Int64  `tfsdk:"min_lowercase"`
-	MinNumber      types.Int64  `tfsdk:"min_number"`
-	MinSpecial     types.Int64  `tfsdk:"min_special"`
-	MinUppercase   types.Int64  `tfsdk:"min_uppercase"`
-	Numbers        types.Bool   `tfsdk:"numbers"`
-	Special        types.Bool   `tfsdk:"special"`
-	Uppercase      types.Bool   `tfsdk:"uppercase"`
+	ID             types.String          `tfsdk:"id"`
+	Key            types.String          `tfsdk:"key"`
+	Value          normalizedStringValue `tfsdk:"value"`
+	ValueJSON      types.Dynamic         `tfsdk:"value_json"`
+	Note           normalizedStringValue `tfsdk:"note"`
+	ProjectID      types.String          `tfsdk:"project_id"`
+	OrganizationID types.String          `tfsdk:"organization_id"`
+	CreationDate   types.String          `tfsdk:"creation_date"`
+	RevisionDate   types.String          `tfsdk:"revision_date"`
+	AvoidAmbiguous types.Bool            `tfsdk:"avoid_ambiguous"`
+	Length         types.Int64           `tfsdk:"length"`
+	Lowercase      types.Bool            `tfsdk:"lowercase"`
+	MinLowercase   types.Int64           `tfsdk:"min_lowercase"`
+	MinNumber      types.Int64           `tfsdk:"min_number"`
+	MinSpecial     types.Int64           `tfsdk:"min_special"`
+	MinUppercase   types.Int64           `tfsdk:"min_uppercase"`
+	Numbers        types.Bool            `tfsdk:"numbers"`
+	Special        types.Bool            `tfsdk:"special"`
+	Uppercase      types.Bool            `tfsdk:"uppercase"`
+
+	SpecialCharacters types.String `tfsdk:"special_characters"`
+	ExcludeCharacters types.String `tfsdk:"exclude_characters"`
+
+	DriftPolicy        types.String `tfsdk:"drift_policy"`
+	EnforceUniqueKey   types.String `tfsdk:"enforce_unique_key"`
+	KeyNamingPolicy    types.Object `tfsdk:"key_naming_policy"`
+	DeletionProtection types.Bool   `tfsdk:"deletion_protection"`
+
+	TrimTrailingNewline  types.Bool `tfsdk:"trim_trailing_newline"`
+	NormalizeLineEndings types.Bool `tfsdk:"normalize_line_endings"`
+
+	Timeouts timeouts.Value `tfsdk:"timeouts"`
 }
 
 func (s *secretResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
 	resp.TypeName = req.ProviderTypeName + "_secret"
 }
 
-func (s *secretResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+func (s *secretResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
-		Description:         "The secret resource manages secrets in Bitwarden Secrets Manager.",
-		MarkdownDescription: "The `secret` resource manages secrets in Bitwarden Secrets Manager.",
+		Description:         "The secret resource manages secrets in Bitwarden Secrets Manager. Creating a secret takes two requests: the create request appends a line marking the request to the note, so a secret created by a request whose response was lost is recognised when the request is retried, and an update removes the line again. If that update fails, the line stays in the note and the secret is replaced by the next apply.",
+		MarkdownDescription: "The `secret` resource manages secrets in Bitwarden Secrets Manager. Creating a secret takes two requests: the create request appends a line marking the request to the note, so a secret created by a request whose response was lost is recognised when the request is retried, and an update removes the line again. If that update fails, the line stays in the note and the secret is replaced by the next apply.",
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
 				Description:         "String representation of the ID of the secret inside Bitwarden Secrets Manager.",
@@ -74,28 +108,55 @@ func (s *secretResource) Schema(_ context.Context, _ resource.SchemaRequest, res
 				},
 			},
 			"key": schema.StringAttribute{
-				Description:         "String representation of the key of the secret. Inside Bitwarden Secrets Manager this is called \"name\".",
-				MarkdownDescription: "String representation of the `key` of the secret. Inside Bitwarden Secrets Manager this is called \"name\".",
+				Description:         "String representation of the key of the secret. Inside Bitwarden Secrets Manager this is called \"name\". It must satisfy the key_naming_policy of the resource or, if unset, of the provider, if configured.",
+				MarkdownDescription: "String representation of the `key` of the secret. Inside Bitwarden Secrets Manager this is called \"name\". It must satisfy the `key_naming_policy` of the resource or, if unset, of the provider, if configured.",
 				Required:            true,
+				Validators: []validator.String{
+					resourceKeyNamingPolicyValidate(),
+				},
 			},
 			"value": schema.StringAttribute{
-				Description:         "String representation of the value of the secret inside Bitwarden Secrets Manager. This attribute is sensitive. The Dynamic Secrets feature enables compatibility with secret value changes in Bitwarden Secrets Manager without changes to the terraform plan.",
-				MarkdownDescription: "String representation of the `value` of the secret inside Bitwarden Secrets Manager. This attribute is sensitive. The Dynamic Secrets feature enables compatibility with secret `value` changes in Bitwarden Secrets Manager without changes to the terraform plan.",
+				Description:         "String representation of the value of the secret inside Bitwarden Secrets Manager. This attribute is sensitive. The Dynamic Secrets feature enables compatibility with secret value changes in Bitwarden Secrets Manager without changes to the terraform plan. Differences in a trailing newline and CRLF line endings are not considered a change, see trim_trailing_newline and normalize_line_endings.",
+				MarkdownDescription: "String representation of the `value` of the secret inside Bitwarden Secrets Manager. This attribute is sensitive. The Dynamic Secrets feature enables compatibility with secret `value` changes in Bitwarden Secrets Manager without changes to the terraform plan. Differences in a trailing newline and CRLF line endings are not considered a change, see `trim_trailing_newline` and `normalize_line_endings`.",
+				CustomType:          normalizedStringType{},
 				Computed:            true,
 				Optional:            true,
 				Sensitive:           true,
+				Validators: []validator.String{
+					stringvalidator.ConflictsWith(path.MatchRoot("value_json")),
+				},
+				PlanModifiers: []planmodifier.String{
+					keepNormalizedState(),
+				},
+			},
+			"value_json": schema.DynamicAttribute{
+				Description:         "Structured value of the secret, e.g. an object or a list, which is stored as normalised JSON document inside Bitwarden Secrets Manager. A string containing a JSON document, e.g. the result of jsonencode() or file(), is validated and accepted as well. Whitespace and key order differences between the configuration and Bitwarden Secrets Manager do not cause a diff. This attribute is sensitive and conflicts with value.",
+				MarkdownDescription: "Structured value of the secret, e.g. an object or a list, which is stored as normalised JSON document inside Bitwarden Secrets Manager. A string containing a JSON document, e.g. the result of `jsonencode()` or `file()`, is validated and accepted as well. Whitespace and key order differences between the configuration and Bitwarden Secrets Manager do not cause a diff. This attribute is sensitive and conflicts with `value`.",
+				Optional:            true,
+				Sensitive:           true,
+				Validators: []validator.Dynamic{
+					jsonValueValidate(),
+				},
 			},
 			"note": schema.StringAttribute{
-				Description:         "String representation of the note of the secret inside Bitwarden Secrets Manager.",
-				MarkdownDescription: "String representation of the `note` of the secret inside Bitwarden Secrets Manager.",
+				Description:         "String representation of the note of the secret inside Bitwarden Secrets Manager. Removing the note from the configuration clears it. Differences in a trailing newline and CRLF line endings are not considered a change, see trim_trailing_newline and normalize_line_endings. While the secret is created, the note ends with a line marking the create request, which is removed as soon as the secret exists.",
+				MarkdownDescription: "String representation of the `note` of the secret inside Bitwarden Secrets Manager. Removing the `note` from the configuration clears it. Differences in a trailing newline and CRLF line endings are not considered a change, see `trim_trailing_newline` and `normalize_line_endings`. While the secret is created, the note ends with a line marking the create request, which is removed as soon as the secret exists.",
+				CustomType:          normalizedStringType{},
 				Computed:            true,
 				Optional:            true,
+				PlanModifiers: []planmodifier.String{
+					clearWhenUnset(types.StringValue("")),
+					keepNormalizedState(),
+				},
 			},
 			"project_id": schema.StringAttribute{
 				Description:         "String representation of the ID of the project to which the secrets belongs. If the used machine account has no read access to this project, access will not be granted.",
-				MarkdownDescription: "String representation of the `ID` of the project to which the secret belongs. If the used machine account has no read access to this project, access will not be granted.",
+				MarkdownDescription: "String representation of the `ID` of the project to which the secret belongs. If the used machine account has no read access to this project, access will not be granted. Removing the project_id from the configuration removes the secret from its project.",
 				Computed:            true,
 				Optional:            true,
+				PlanModifiers: []planmodifier.String{
+					clearWhenUnset(types.StringNull()),
+				},
 			},
 			"organization_id": schema.StringAttribute{
 				Description:         "String representation of the ID of the organization to which the secrets belongs.",
@@ -121,13 +182,13 @@ func (s *secretResource) Schema(_ context.Context, _ resource.SchemaRequest, res
 				MarkdownDescription: "Ignored if value is provided explicitly or secret is updated dynamically in Bitwarden Secrets Manager. When set to true, the generated secret will not contain ambiguous characters. The ambiguous characters are: `I`, `O`, `l`, `0`, `1`. The provided default is false. ",
 				Computed:            true,
 				Optional:            true,
-				Default:             booldefault.StaticBool(false),
+				Default:             booldefault.StaticBool(defaultAvoidAmbiguous),
 			},
 			"length": schema.Int64Attribute{
 				Description: "Ignored if value is provided explicitly or secret is updated dynamically in Bitwarden Secrets Manager. The length of the generated secret. Note that the length of the value must be greater than the sum of all the minimums. The provided default length is 64.",
 				Computed:    true,
 				Optional:    true,
-				Default:     int64default.StaticInt64(64),
+				Default:     int64default.StaticInt64(defaultLength),
 				Validators: []validator.Int64{
 					int64validator.AtLeastSumOf(path.Expressions{
 						path.MatchRoot("min_lowercase"),
@@ -135,6 +196,7 @@ func (s *secretResource) Schema(_ context.Context, _ resource.SchemaRequest, res
 						path.MatchRoot("min_number"),
 						path.MatchRoot("min_special"),
 					}...),
+					generatorOptionsValidate(),
 				},
 			},
 			"lowercase": schema.BoolAttribute{
@@ -142,16 +204,16 @@ func (s *secretResource) Schema(_ context.Context, _ resource.SchemaRequest, res
 				MarkdownDescription: "Ignored if value is provided explicitly or secret is updated dynamically in Bitwarden Secrets Manager. Configures the secret generator to include lowercase characters `(a-z)`.  The provided default is true.",
 				Computed:            true,
 				Optional:            true,
-				Default:             booldefault.StaticBool(true),
+				Default:             booldefault.StaticBool(defaultLowercase),
 			},
 			"min_lowercase": schema.Int64Attribute{
-				Description:         "Ignored if value is provided explicitly or secret is updated dynamically in Bitwarden Secrets Manager. Configures the minimum number of lowercase characters in the generated secret. When set, the value must be between 1 and 9. This value is ignored if lowercase is false.",
-				MarkdownDescription: "Ignored if value is provided explicitly or secret is updated dynamically in Bitwarden Secrets Manager. Configures the minimum number of lowercase characters in the generated secret. When set, the value must be between 1 and 9. This value is ignored if `lowercase` is false.",
+				Description:         "Ignored if value is provided explicitly or secret is updated dynamically in Bitwarden Secrets Manager. Configures the minimum number of lowercase characters in the generated secret. When set, the value must be at least 1. This value is ignored if lowercase is false.",
+				MarkdownDescription: "Ignored if value is provided explicitly or secret is updated dynamically in Bitwarden Secrets Manager. Configures the minimum number of lowercase characters in the generated secret. When set, the value must be at least 1. This value is ignored if `lowercase` is false.",
 				Computed:            true,
 				Optional:            true,
-				Default:             int64default.StaticInt64(1),
+				Default:             int64default.StaticInt64(defaultMinimum),
 				Validators: []validator.Int64{
-					int64validator.Between(1, 9),
+					int64validator.AtLeast(1),
 				},
 			},
 			"uppercase": schema.BoolAttribute{
@@ -159,16 +221,16 @@ func (s *secretResource) Schema(_ context.Context, _ resource.SchemaRequest, res
 				MarkdownDescription: "Ignored if value is provided explicitly or secret is updated dynamically in Bitwarden Secrets Manager. Configures the secret generator to include uppercase characters `(A-Z)`. The provided default is true.",
 				Computed:            true,
 				Optional:            true,
-				Default:             booldefault.StaticBool(true),
+				Default:             booldefault.StaticBool(defaultUppercase),
 			},
 			"min_uppercase": schema.Int64Attribute{
-				Description:         "Ignored if value is provided explicitly or secret is updated dynamically in Bitwarden Secrets Manager. Configures the minimum number of uppercase characters in the generated secret. When set, the value must be between 1 and 9. This value is ignored if uppercase is false.",
-				MarkdownDescription: "Ignored if value is provided explicitly or secret is updated dynamically in Bitwarden Secrets Manager. Configures the minimum number of uppercase characters in the generated secret. When set, the value must be between 1 and 9. This value is ignored if `uppercase` is false.",
+				Description:         "Ignored if value is provided explicitly or secret is updated dynamically in Bitwarden Secrets Manager. Configures the minimum number of uppercase characters in the generated secret. When set, the value must be at least 1. This value is ignored if uppercase is false.",
+				MarkdownDescription: "Ignored if value is provided explicitly or secret is updated dynamically in Bitwarden Secrets Manager. Configures the minimum number of uppercase characters in the generated secret. When set, the value must be at least 1. This value is ignored if `uppercase` is false.",
 				Computed:            true,
 				Optional:            true,
-				Default:             int64default.StaticInt64(1),
+				Default:             int64default.StaticInt64(defaultMinimum),
 				Validators: []validator.Int64{
-					int64validator.Between(1, 9),
+					int64validator.AtLeast(1),
 				},
 			},
 			"numbers": schema.BoolAttribute{
@@ -176,35 +238,103 @@ func (s *secretResource) Schema(_ context.Context, _ resource.SchemaRequest, res
 				MarkdownDescription: "Ignored if value is provided explicitly or secret is updated dynamically in Bitwarden Secrets Manager. Configures the secret generator to include numbers `(0-9)`. The provided default is true.",
 				Computed:            true,
 				Optional:            true,
-				Default:             booldefault.StaticBool(true),
+				Default:             booldefault.StaticBool(defaultNumbers),
 			},
 			"min_number": schema.Int64Attribute{
-				Description:         "Ignored if value is provided explicitly or secret is updated dynamically in Bitwarden Secrets Manager. Configures the minimum number of numbers in the generated secret. When set, the value must be between 1 and 9. This value is ignored if numbers is false.",
-				MarkdownDescription: "Ignored if value is provided explicitly or secret is updated dynamically in Bitwarden Secrets Manager. Configures the minimum number of numbers in the generated secret. When set, the value must be between 1 and 9. This value is ignored if `numbers` is false.",
+				Description:         "Ignored if value is provided explicitly or secret is updated dynamically in Bitwarden Secrets Manager. Configures the minimum number of numbers in the generated secret. When set, the value must be at least 1. This value is ignored if numbers is false.",
+				MarkdownDescription: "Ignored if value is provided explicitly or secret is updated dynamically in Bitwarden Secrets Manager. Configures the minimum number of numbers in the generated secret. When set, the value must be at least 1. This value is ignored if `numbers` is false.",
 				Computed:            true,
 				Optional:            true,
-				Default:             int64default.StaticInt64(1),
+				Default:             int64default.StaticInt64(defaultMinimum),
 				Validators: []validator.Int64{
-					int64validator.Between(1, 9),
+					int64validator.AtLeast(1),
 				},
 			},
 			"special": schema.BoolAttribute{
-				Description:         "Ignored if value is provided explicitly or secret is updated dynamically in Bitwarden Secrets Manager. Configures the secret generator to include special characters: ! @ # $ % ^ & *.",
-				MarkdownDescription: "Ignored if value is provided explicitly or secret is updated dynamically in Bitwarden Secrets Manager. Configures the secret generator to include special characters: `!` `@` `#` `$` `%` `^` `&` `*`.",
+				Description:         "Ignored if value is provided explicitly or secret is updated dynamically in Bitwarden Secrets Manager. Configures the secret generator to include special characters. The used characters can be configured with special_characters and default to: ! @ # $ % ^ & *.",
+				MarkdownDescription: "Ignored if value is provided explicitly or secret is updated dynamically in Bitwarden Secrets Manager. Configures the secret generator to include special characters. The used characters can be configured with `special_characters` and default to: `!` `@` `#` `$` `%` `^` `&` `*`.",
 				Computed:            true,
 				Optional:            true,
-				Default:             booldefault.StaticBool(false),
+				Default:             booldefault.StaticBool(defaultSpecial),
 			},
 			"min_special": schema.Int64Attribute{
-				Description:         "Ignored if value is provided explicitly or secret is updated dynamically in Bitwarden Secrets Manager. Configures the minimum number of special characters in the generated secret. When set, the value must be between 1 and 9. This value is ignored if special is false.",
-				MarkdownDescription: "Ignored if value is provided explicitly or secret is updated dynamically in Bitwarden Secrets Manager. Configures the minimum number of special characters in the generated secret. When set, the value must be between 1 and 9. This value is ignored if `special` is false.",
+				Description:         "Ignored if value is provided explicitly or secret is updated dynamically in Bitwarden Secrets Manager. Configures the minimum number of special characters in the generated secret. When set, the value must be at least 1. This value is ignored if special is false.",
+				MarkdownDescription: "Ignored if value is provided explicitly or secret is updated dynamically in Bitwarden Secrets Manager. Configures the minimum number of special characters in the generated secret. When set, the value must be at least 1. This value is ignored if `special` is false.",
 				Computed:            true,
 				Optional:            true,
-				Default:             int64default.StaticInt64(1),
+				Default:             int64default.StaticInt64(defaultMinimum),
 				Validators: []validator.Int64{
-					int64validator.Between(1, 9),
+					int64validator.AtLeast(1),
+				},
+			},
+			"special_characters": schema.StringAttribute{
+				Description:         "Ignored if value is provided explicitly or secret is updated dynamically in Bitwarden Secrets Manager. Configures the set of special characters used by the secret generator when special is true. The provided default is !@#$%^&*.",
+				MarkdownDescription: "Ignored if value is provided explicitly or secret is updated dynamically in Bitwarden Secrets Manager. Configures the set of special characters used by the secret generator when `special` is true. The provided default is `!@#$%^&*`.",
+				Computed:            true,
+				Optional:            true,
+				Default:             stringdefault.StaticString(defaultSpecialCharacters),
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"exclude_characters": schema.StringAttribute{
+				Description:         "Ignored if value is provided explicitly or secret is updated dynamically in Bitwarden Secrets Manager. Characters that must never appear in the generated secret, e.g. characters which are not allowed inside a connection string. The provided default is an empty string.",
+				MarkdownDescription: "Ignored if value is provided explicitly or secret is updated dynamically in Bitwarden Secrets Manager. Characters that must never appear in the generated secret, e.g. characters which are not allowed inside a connection string. The provided default is an empty string.",
+				Computed:            true,
+				Optional:            true,
+				Default:             stringdefault.StaticString(""),
+			},
+			"drift_policy": schema.StringAttribute{
+				Description:         "Configures how changes of the secret value outside of terraform are handled. With adopt, the changed value is imported into the state (Dynamic Secrets). With revert, an update is planned which writes the configured or previously generated value back. With error, the plan fails naming the secret and its revision date. The secret value is never shown. The provided default is adopt.",
+				MarkdownDescription: "Configures how changes of the secret `value` outside of terraform are handled. With `adopt`, the changed value is imported into the state (Dynamic Secrets). With `revert`, an update is planned which writes the configured or previously generated value back. With `error`, the plan fails naming the secret and its `revision_date`. The secret value is never shown. The provided default is `adopt`.",
+				Computed:            true,
+				Optional:            true,
+				Default:             stringdefault.StaticString(driftPolicyAdopt),
+				Validators: []validator.String{
+					stringvalidator.OneOf(driftPolicyAdopt, driftPolicyRevert, driftPolicyError),
+				},
+			},
+			"enforce_unique_key": schema.StringAttribute{
+				Description:         "Configures how existing secrets with the same key in the same project are handled when the secret is created. With off, a duplicate is created. With error, the plan fails naming the ID of the existing secret. With adopt_existing, the existing secret is taken over and updated with the configuration instead of creating a duplicate, a generated value is not applied to it. Overrides enforce_unique_key of the provider, which defaults to off.",
+				MarkdownDescription: "Configures how existing secrets with the same `key` in the same project are handled when the secret is created. With `off`, a duplicate is created. With `error`, the plan fails naming the `ID` of the existing secret. With `adopt_existing`, the existing secret is taken over and updated with the configuration instead of creating a duplicate, a generated value is not applied to it. Overrides `enforce_unique_key` of the provider, which defaults to `off`.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(uniqueKeyModes...),
 				},
 			},
+			"key_naming_policy": resourceKeyNamingPolicyAttribute("the key of the secret"),
+			"trim_trailing_newline": schema.BoolAttribute{
+				Description:         "Whether a single trailing newline, e.g. added by file(), is ignored when comparing the configured value and note with Bitwarden Secrets Manager. The provided default is true.",
+				MarkdownDescription: "Whether a single trailing newline, e.g. added by `file()`, is ignored when comparing the configured `value` and `note` with Bitwarden Secrets Manager. The provided default is true.",
+				Computed:            true,
+				Optional:            true,
+				Default:             booldefault.StaticBool(true),
+			},
+			"normalize_line_endings": schema.BoolAttribute{
+				Description:         "Whether CRLF line endings are treated as LF when comparing the configured value and note with Bitwarden Secrets Manager. The provided default is true.",
+				MarkdownDescription: "Whether CRLF line endings are treated as LF when comparing the configured `value` and `note` with Bitwarden Secrets Manager. The provided default is true.",
+				Computed:            true,
+				Optional:            true,
+				Default:             booldefault.StaticBool(true),
+			},
+			"deletion_protection": schema.BoolAttribute{
+				Description:         "When set to true, the secret cannot be deleted, neither by destroying nor by replacing the resource. To delete a protected secret, set deletion_protection to false and apply the configuration first. Removing deletion_protection from the configuration keeps its current value. The provided default is false.",
+				MarkdownDescription: "When set to true, the secret cannot be deleted, neither by destroying nor by replacing the resource. To delete a protected secret, set `deletion_protection` to false and apply the configuration first. Removing `deletion_protection` from the configuration keeps its current value. The provided default is false.",
+				Computed:            true,
+				Optional:            true,
+				Default:             booldefault.StaticBool(false),
+				PlanModifiers: []planmodifier.Bool{
+					keepStateWhenUnset(),
+				},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Update: true,
+				Delete: true,
+			}),
 		},
 	}
 }
@@ -249,6 +379,9 @@ func (s *secretResource) Configure(ctx context.Context, req resource.ConfigureRe
 
 	s.bitwardenClient = client
 	s.organizationId = organizationId
+	s.keyNamingPolicy = providerDataStruct.keyNamingPolicy
+	s.enforceUniqueKey = providerDataStruct.enforceUniqueKey
+	s.secretBackup = providerDataStruct.secretBackup
 
 	tflog.Info(ctx, "Resource Configured")
 }
@@ -270,9 +403,48 @@ func (s *secretResource) Create(ctx context.Context, req resource.CreateRequest,
 		return
 	}
 
+	ctx, client, done := withClientTimeout(ctx, s.bitwardenClient, plan.Timeouts.Create, "create", "the secret", &resp.Diagnostics)
+	defer done()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The check is repeated, since the key may have been unknown during plan or the secret created in the meantime.
+	var adopted *sdk.SecretResponse
+	if mode := s.uniqueKeyMode(&plan); mode != uniqueKeyOff {
+		existing, err := findSecretsWithKey(client, s.organizationId, plan.Key.ValueString(), plan.ProjectID.ValueStringPointer())
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to Check for Existing Secrets",
+				err.Error(),
+			)
+			return
+		}
+
+		adopted, diags = checkUniqueKey(mode, plan.Key.ValueString(), existing)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
 	var value string
-	if plan.Value.IsUnknown() {
-		generatedValue, err := createSecretValue(&plan, s.bitwardenClient)
+	if !plan.ValueJSON.IsNull() {
+		jsonValue, err := encodeJSONValue(ctx, plan.ValueJSON)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("value_json"),
+				"Invalid JSON secret value",
+				err.Error(),
+			)
+			return
+		}
+		value = jsonValue
+	} else if plan.Value.IsUnknown() && adopted != nil {
+		// An adopted secret keeps its value, unless the value is configured explicitly.
+		value = adopted.Value
+	} else if plan.Value.IsUnknown() {
+		generatedValue, err := createSecretValue(&plan, client)
 		if err != nil {
 			resp.Diagnostics.AddError(
 				"Error generating secret value",
@@ -285,27 +457,55 @@ func (s *secretResource) Create(ctx context.Context, req resource.CreateRequest,
 		value = plan.Value.ValueString()
 	}
 
-	secret, err := s.bitwardenClient.Secrets().Create(
-		plan.Key.ValueString(),
-		value,
-		plan.Note.ValueString(),
-		s.organizationId,
-		[]string{plan.ProjectID.ValueString()},
-	)
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Unable to Create Secret",
-			err.Error(),
+	var secret *sdk.SecretResponse
+	var err error
+	if adopted != nil {
+		tflog.Info(ctx, "Adopting existing secret", map[string]any{"id": adopted.ID})
+		secret, err = client.Secrets().Update(
+			adopted.ID,
+			plan.Key.ValueString(),
+			value,
+			plan.Note.ValueString(),
+			s.organizationId,
+			projectIDs(plan.ProjectID),
 		)
-		return
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to Adopt Secret with id: "+adopted.ID,
+				err.Error(),
+			)
+			return
+		}
+	} else {
+		secret, err = createSecretIdempotently(
+			ctx,
+			s.bitwardenClient,
+			plan.Key.ValueString(),
+			value,
+			plan.Note.ValueString(),
+			s.organizationId,
+			projectIDs(plan.ProjectID),
+		)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to Create Secret",
+				err.Error(),
+			)
+			// A secret whose note still contains the marker of the create request is saved, so Terraform replaces it.
+			if secret == nil {
+				return
+			}
+		}
 	}
 
+	normalization := secretNormalization(plan.TrimTrailingNewline, plan.NormalizeLineEndings)
 	var state secretResourceModel
 	state.ID = types.StringValue(secret.ID)
 	state.Key = types.StringValue(secret.Key)
-	state.Value = types.StringValue(secret.Value)
-	state.Note = types.StringValue(secret.Note)
-	state.ProjectID = types.StringValue(*secret.ProjectID)
+	state.Value = normalizedString(normalization.normalizedValue(plan.Value.StringValue, secret.Value))
+	state.ValueJSON = plan.ValueJSON
+	state.Note = normalizedString(normalization.normalizedValue(plan.Note.StringValue, secret.Note))
+	state.ProjectID = projectIDValue(secret.ProjectID, plan.ProjectID)
 	state.OrganizationID = types.StringValue(secret.OrganizationID)
 	state.CreationDate = types.StringValue(secret.CreationDate.String())
 	state.RevisionDate = types.StringValue(secret.RevisionDate.String())
@@ -319,6 +519,15 @@ func (s *secretResource) Create(ctx context.Context, req resource.CreateRequest,
 	state.Numbers = plan.Numbers
 	state.Special = plan.Special
 	state.Uppercase = plan.Uppercase
+	state.SpecialCharacters = plan.SpecialCharacters
+	state.ExcludeCharacters = plan.ExcludeCharacters
+	state.DriftPolicy = plan.DriftPolicy
+	state.EnforceUniqueKey = plan.EnforceUniqueKey
+	state.KeyNamingPolicy = plan.KeyNamingPolicy
+	state.DeletionProtection = plan.DeletionProtection
+	state.TrimTrailingNewline = plan.TrimTrailingNewline
+	state.NormalizeLineEndings = plan.NormalizeLineEndings
+	state.Timeouts = plan.Timeouts
 
 	// Set state to fully populated data
 	diags = resp.State.Set(ctx, state)
@@ -346,7 +555,13 @@ func (s *secretResource) Read(ctx context.Context, req resource.ReadRequest, res
 		return
 	}
 
-	secret, err := s.bitwardenClient.Secrets().Get(state.ID.ValueString())
+	ctx, client, done := withClientTimeout(ctx, s.bitwardenClient, state.Timeouts.Read, "read", "the secret", &resp.Diagnostics)
+	defer done()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	secret, err := client.Secrets().Get(state.ID.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to Read Secret with id: "+state.ID.ValueString(),
@@ -355,14 +570,54 @@ func (s *secretResource) Read(ctx context.Context, req resource.ReadRequest, res
 		return
 	}
 
+	// Imported secrets start with the schema defaults.
+	if state.TrimTrailingNewline.IsNull() {
+		state.TrimTrailingNewline = types.BoolValue(true)
+	}
+	if state.NormalizeLineEndings.IsNull() {
+		state.NormalizeLineEndings = types.BoolValue(true)
+	}
+	normalization := secretNormalization(state.TrimTrailingNewline, state.NormalizeLineEndings)
+
+	// Secrets which must not adopt value changes keep the value of the state, the drift is reported by ModifyPlan.
+	drifted := driftPolicy(&state) != driftPolicyAdopt && secretValueDrifted(&state, secret.Value)
+	if drifted {
+		tflog.Warn(ctx, "Secret value was changed outside of terraform", map[string]any{
+			"id":            state.ID.ValueString(),
+			"revision_date": secret.RevisionDate.String(),
+			"drift_policy":  state.DriftPolicy.ValueString(),
+		})
+		diags = resp.Private.SetKey(ctx, valueDriftPrivateStateKey, encodeValueDrift(valueDrift{RevisionDate: secret.RevisionDate.String()}))
+	} else {
+		state.Value = normalizedString(normalization.normalizedValue(state.Value.StringValue, secret.Value))
+		diags = resp.Private.SetKey(ctx, valueDriftPrivateStateKey, nil)
+	}
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	state.Key = types.StringValue(secret.Key)
-	state.Value = types.StringValue(secret.Value)
-	state.Note = types.StringValue(secret.Note)
-	state.ProjectID = types.StringValue(*secret.ProjectID)
+	state.Note = normalizedString(normalization.normalizedValue(state.Note.StringValue, secret.Note))
+	state.ProjectID = projectIDValue(secret.ProjectID, state.ProjectID)
+	// Imported secrets start unprotected, like the schema default.
+	if state.DeletionProtection.IsNull() {
+		state.DeletionProtection = types.BoolValue(false)
+	}
 	state.OrganizationID = types.StringValue(secret.OrganizationID)
 	state.CreationDate = types.StringValue(secret.CreationDate.String())
 	state.RevisionDate = types.StringValue(secret.RevisionDate.String())
 
+	// Only secrets managed via value_json are decoded, all other secrets keep value_json unset.
+	if !state.ValueJSON.IsNull() && !drifted {
+		valueJSON, err := refreshJSONValue(ctx, state.ValueJSON, secret.Value)
+		if err != nil {
+			tflog.Warn(ctx, "Secret value in Bitwarden Secrets Manager is no longer a valid JSON document", map[string]any{"id": state.ID.ValueString()})
+			valueJSON = types.DynamicValue(types.StringValue(secret.Value))
+		}
+		state.ValueJSON = valueJSON
+	}
+
 	// Set state
 	diags = resp.State.Set(ctx, &state)
 	resp.Diagnostics.Append(diags...)
@@ -395,14 +650,37 @@ func (s *secretResource) Update(ctx context.Context, req resource.UpdateRequest,
 		return
 	}
 
+	ctx, client, done := withClientTimeout(ctx, s.bitwardenClient, plan.Timeouts.Update, "update", "the secret", &resp.Diagnostics)
+	defer done()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	drift, diags := getValueDrift(ctx, req.Private)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	key := plan.Key.ValueString()
 	if key == "" {
 		key = state.Key.ValueString()
 	}
 	value := plan.Value.ValueString()
-	if value == "" {
+	if !plan.ValueJSON.IsNull() {
+		jsonValue, err := encodeJSONValue(ctx, plan.ValueJSON)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("value_json"),
+				"Invalid JSON secret value",
+				err.Error(),
+			)
+			return
+		}
+		value = jsonValue
+	} else if value == "" {
 		if newGeneratorConfig(&plan, &state) {
-			generatedValue, err := createSecretValue(&plan, s.bitwardenClient)
+			generatedValue, err := createSecretValue(&plan, client)
 			if err != nil {
 				resp.Diagnostics.AddError(
 					"Error generating secret value",
@@ -411,26 +689,29 @@ func (s *secretResource) Update(ctx context.Context, req resource.UpdateRequest,
 				return
 			}
 			value = generatedValue
+		} else if drift != nil && driftPolicy(&plan) == driftPolicyAdopt {
+			// The drift policy was changed to adopt, so the value changed outside of terraform is kept.
+			remoteSecret, err := client.Secrets().Get(state.ID.ValueString())
+			if err != nil {
+				resp.Diagnostics.AddError(
+					"Unable to Read Secret with id: "+state.ID.ValueString(),
+					err.Error(),
+				)
+				return
+			}
+			value = remoteSecret.Value
 		} else {
 			value = state.Value.ValueString()
 		}
 	}
-	note := plan.Note.ValueString()
-	if note == "" {
-		note = state.Note.ValueString()
-	}
-	projectID := plan.ProjectID.ValueString()
-	if projectID == "" {
-		projectID = state.ProjectID.ValueString()
-	}
-
-	secret, err := s.bitwardenClient.Secrets().Update(
+	// A null or empty note and project_id clear the respective field, see clearWhenUnset.
+	secret, err := client.Secrets().Update(
 		state.ID.ValueString(),
 		key,
 		value,
-		note,
+		plan.Note.ValueString(),
 		state.OrganizationID.ValueString(),
-		[]string{projectID},
+		projectIDs(plan.ProjectID),
 	)
 	if err != nil {
 		resp.Diagnostics.AddError(
@@ -440,10 +721,12 @@ func (s *secretResource) Update(ctx context.Context, req resource.UpdateRequest,
 		return
 	}
 
+	normalization := secretNormalization(plan.TrimTrailingNewline, plan.NormalizeLineEndings)
 	state.Key = types.StringValue(secret.Key)
-	state.Value = types.StringValue(secret.Value)
-	state.Note = types.StringValue(secret.Note)
-	state.ProjectID = types.StringValue(*secret.ProjectID)
+	state.Value = normalizedString(normalization.normalizedValue(plan.Value.StringValue, secret.Value))
+	state.ValueJSON = plan.ValueJSON
+	state.Note = normalizedString(normalization.normalizedValue(plan.Note.StringValue, secret.Note))
+	state.ProjectID = projectIDValue(secret.ProjectID, plan.ProjectID)
 	state.OrganizationID = types.StringValue(secret.OrganizationID)
 	state.CreationDate = types.StringValue(secret.CreationDate.String())
 	state.RevisionDate = types.StringValue(secret.RevisionDate.String())
@@ -457,6 +740,22 @@ func (s *secretResource) Update(ctx context.Context, req resource.UpdateRequest,
 	state.Numbers = plan.Numbers
 	state.Special = plan.Special
 	state.Uppercase = plan.Uppercase
+	state.SpecialCharacters = plan.SpecialCharacters
+	state.ExcludeCharacters = plan.ExcludeCharacters
+	state.DriftPolicy = plan.DriftPolicy
+	state.EnforceUniqueKey = plan.EnforceUniqueKey
+	state.KeyNamingPolicy = plan.KeyNamingPolicy
+	state.DeletionProtection = plan.DeletionProtection
+	state.TrimTrailingNewline = plan.TrimTrailingNewline
+	state.NormalizeLineEndings = plan.NormalizeLineEndings
+	state.Timeouts = plan.Timeouts
+
+	// The value in Bitwarden Secrets Manager matches the state again.
+	diags = resp.Private.SetKey(ctx, valueDriftPrivateStateKey, nil)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
 	// Set state to fully populated data
 	diags = resp.State.Set(ctx, state)
@@ -482,20 +781,169 @@ func (s *secretResource) Delete(ctx context.Context, req resource.DeleteRequest,
 		return
 	}
 
-	secretDeleteResponse, err := s.bitwardenClient.Secrets().Delete([]string{plan.ID.ValueString()})
+	ctx, client, done := withClientTimeout(ctx, s.bitwardenClient, plan.Timeouts.Delete, "delete", "the secret", &resp.Diagnostics)
+	defer done()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if plan.DeletionProtection.ValueBool() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("deletion_protection"),
+			"Deletion Protection Enabled",
+			fmt.Sprintf("The secret %s with the id %s is protected against deletion. ", plan.Key.ValueString(), plan.ID.ValueString())+
+				"Set deletion_protection to false and apply the configuration before destroying or replacing it.",
+		)
+		return
+	}
+
+	// A secret which was already deleted outside of terraform counts as deleted.
+	if err := deleteSecrets(client, s.secretBackup, []string{plan.ID.ValueString()}); err != nil {
+		addDeleteDiagnostics(&resp.Diagnostics, "Unable to Delete Secret", err)
+	}
+}
+
+func (s *secretResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to plan for the deletion of a secret.
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var key types.String
+	var policyConfig types.Object
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("key"), &key)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("key_naming_policy"), &policyConfig)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	policy, diags := effectiveKeyNamingPolicy(ctx, policyConfig, s.keyNamingPolicy)
+	resp.Diagnostics.Append(diags...)
+	resp.Diagnostics.Append(checkKeyNamingPolicy(ctx, policy, path.Root("key"), key)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Existing secrets with the same key are only relevant for the creation of secrets.
+	if req.State.Raw.IsNull() {
+		s.modifyCreatePlan(ctx, req, resp)
+		return
+	}
+
+	// A configured value or note which keepNormalizedState planned unchanged leaves the revision date as the only
+	// change, which is planned unchanged as well.
+	var revisionDate types.String
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("revision_date"), &revisionDate)...)
+	unchanged := resp.Plan
+	resp.Diagnostics.Append(unchanged.SetAttribute(ctx, path.Root("revision_date"), revisionDate)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if unchanged.Raw.Equal(req.State.Raw) {
+		resp.Plan = unchanged
+	}
+
+	// Value drift is only relevant for updates of existing secrets.
+
+	drift, diags := getValueDrift(ctx, req.Private)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() || drift == nil {
+		return
+	}
+
+	var plan secretResourceModel
+	diags = req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var state secretResourceModel
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	switch driftPolicy(&plan) {
+	case driftPolicyError:
+		resp.Diagnostics.AddAttributeError(
+			path.Root("value"),
+			"Secret Value Changed Outside of Terraform",
+			fmt.Sprintf("The value of the secret %q with id %s was changed outside of terraform at revision date %s. "+
+				"Set drift_policy to \"revert\" to restore the configured value or to \"adopt\" to accept the change.",
+				state.Key.ValueString(), state.ID.ValueString(), drift.RevisionDate),
+		)
+	case driftPolicyRevert:
+		var configValue types.String
+		diags = req.Config.GetAttribute(ctx, path.Root("value"), &configValue)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		// Unconfigured values are computed and would otherwise be planned as unknown, so the value of the state,
+		// which still holds the value terraform wrote, is planned explicitly.
+		if configValue.IsNull() && plan.ValueJSON.IsNull() && !newGeneratorConfig(&plan, &state) {
+			plan.Value = state.Value
+		}
+		// The state already matches the configuration, an unknown revision date forces the update.
+		plan.RevisionDate = types.StringUnknown()
+
+		diags = resp.Plan.Set(ctx, &plan)
+		resp.Diagnostics.Append(diags...)
+	}
+}
+
+// modifyCreatePlan checks for existing secrets with the planned key according to the unique key mode. A secret
+// to adopt is announced with a warning.
+func (s *secretResource) modifyCreatePlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	var plan secretResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Without a configured provider or with unknown values the check is left to Create.
+	mode := s.uniqueKeyMode(&plan)
+	if s.bitwardenClient == nil || mode == uniqueKeyOff || plan.EnforceUniqueKey.IsUnknown() || plan.Key.IsUnknown() || plan.ProjectID.IsUnknown() {
+		return
+	}
+
+	defer addCancellationDiagnostic(ctx, &resp.Diagnostics, "plan of the secret")
+	existing, err := findSecretsWithKey(clientWithContext(ctx, s.bitwardenClient), s.organizationId, plan.Key.ValueString(), plan.ProjectID.ValueStringPointer())
 	if err != nil {
 		resp.Diagnostics.AddError(
-			"Unable to Delete Secret",
+			"Unable to Check for Existing Secrets",
 			err.Error(),
 		)
 		return
 	}
-	if secretDeleteResponse.Data[0].Error != nil {
-		resp.Diagnostics.AddError(
-			"Error deleting Secret",
-			*secretDeleteResponse.Data[0].Error,
-		)
+
+	adopted, diags := checkUniqueKey(mode, plan.Key.ValueString(), existing)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() || adopted == nil {
+		return
+	}
+
+	resp.Diagnostics.AddAttributeWarning(
+		path.Root("key"),
+		"Existing Secret Will Be Adopted",
+		fmt.Sprintf("The key %q is already used by the secret with id %s in the same project. "+
+			"The existing secret will be taken over by terraform and updated with the configuration instead of creating a duplicate.",
+			plan.Key.ValueString(), adopted.ID),
+	)
+}
+
+// uniqueKeyMode returns the unique key mode of the resource, falling back to the mode of the provider.
+func (s *secretResource) uniqueKeyMode(model *secretResourceModel) string {
+	if !model.EnforceUniqueKey.IsNull() && !model.EnforceUniqueKey.IsUnknown() {
+		return model.EnforceUniqueKey.ValueString()
+	}
+	if s.enforceUniqueKey != "" {
+		return s.enforceUniqueKey
 	}
+	return uniqueKeyOff
 }
 
 func (s *secretResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
@@ -504,22 +952,30 @@ func (s *secretResource) ImportState(ctx context.Context, req resource.ImportSta
 }
 
 func createSecretValue(config *secretResourceModel, bitwardenClient sdk.BitwardenClientInterface) (string, error) {
-	minLowercase := config.MinLowercase.ValueInt64()
-	minNumber := config.MinNumber.ValueInt64()
-	minSpecial := config.MinSpecial.ValueInt64()
-	minUppercase := config.MinUppercase.ValueInt64()
+	options := newPasswordGeneratorOptions(config)
+
+	// The SDK generator neither supports custom special characters, excluded characters nor minimums above 9.
+	// In these cases the secret value is generated inside the provider.
+	if !options.supportedBySDK() {
+		return generatePassword(options)
+	}
+
+	minLowercase := options.MinLowercase
+	minNumber := options.MinNumber
+	minSpecial := options.MinSpecial
+	minUppercase := options.MinUppercase
 
 	request := sdk.PasswordGeneratorRequest{
-		AvoidAmbiguous: config.AvoidAmbiguous.ValueBool(),
-		Length:         config.Length.ValueInt64(),
-		Lowercase:      config.Lowercase.ValueBool(),
+		AvoidAmbiguous: options.AvoidAmbiguous,
+		Length:         options.Length,
+		Lowercase:      options.Lowercase,
 		MinLowercase:   &minLowercase,
 		MinNumber:      &minNumber,
 		MinSpecial:     &minSpecial,
 		MinUppercase:   &minUppercase,
-		Numbers:        config.Numbers.ValueBool(),
-		Special:        config.Special.ValueBool(),
-		Uppercase:      config.Uppercase.ValueBool(),
+		Numbers:        options.Numbers,
+		Special:        options.Special,
+		Uppercase:      options.Uppercase,
 	}
 
 	password, err := bitwardenClient.Generators().GeneratePassword(request)
@@ -530,6 +986,79 @@ func createSecretValue(config *secretResourceModel, bitwardenClient sdk.Bitwarde
 	return *password, nil
 }
 
+func newPasswordGeneratorOptions(config *secretResourceModel) passwordGeneratorOptions {
+	return passwordGeneratorOptions{
+		AvoidAmbiguous:    config.AvoidAmbiguous.ValueBool(),
+		Length:            config.Length.ValueInt64(),
+		Lowercase:         config.Lowercase.ValueBool(),
+		MinLowercase:      config.MinLowercase.ValueInt64(),
+		Uppercase:         config.Uppercase.ValueBool(),
+		MinUppercase:      config.MinUppercase.ValueInt64(),
+		Numbers:           config.Numbers.ValueBool(),
+		MinNumber:         config.MinNumber.ValueInt64(),
+		Special:           config.Special.ValueBool(),
+		MinSpecial:        config.MinSpecial.ValueInt64(),
+		SpecialCharacters: config.SpecialCharacters.ValueString(),
+		ExcludeCharacters: config.ExcludeCharacters.ValueString(),
+	}
+}
+
+// applyGeneratorDefaults sets the schema defaults for all generator attributes which are null in the given config.
+func applyGeneratorDefaults(config *secretResourceModel) {
+	if config.AvoidAmbiguous.IsNull() {
+		config.AvoidAmbiguous = types.BoolValue(defaultAvoidAmbiguous)
+	}
+	if config.Length.IsNull() {
+		config.Length = types.Int64Value(defaultLength)
+	}
+	if config.Lowercase.IsNull() {
+		config.Lowercase = types.BoolValue(defaultLowercase)
+	}
+	if config.MinLowercase.IsNull() {
+		config.MinLowercase = types.Int64Value(defaultMinimum)
+	}
+	if config.Uppercase.IsNull() {
+		config.Uppercase = types.BoolValue(defaultUppercase)
+	}
+	if config.MinUppercase.IsNull() {
+		config.MinUppercase = types.Int64Value(defaultMinimum)
+	}
+	if config.Numbers.IsNull() {
+		config.Numbers = types.BoolValue(defaultNumbers)
+	}
+	if config.MinNumber.IsNull() {
+		config.MinNumber = types.Int64Value(defaultMinimum)
+	}
+	if config.Special.IsNull() {
+		config.Special = types.BoolValue(defaultSpecial)
+	}
+	if config.MinSpecial.IsNull() {
+		config.MinSpecial = types.Int64Value(defaultMinimum)
+	}
+	if config.SpecialCharacters.IsNull() {
+		config.SpecialCharacters = types.StringValue(defaultSpecialCharacters)
+	}
+	if config.ExcludeCharacters.IsNull() {
+		config.ExcludeCharacters = types.StringValue("")
+	}
+}
+
+// hasUnknownGeneratorConfig reports whether any of the generator attributes is not yet known.
+func hasUnknownGeneratorConfig(config *secretResourceModel) bool {
+	return config.AvoidAmbiguous.IsUnknown() ||
+		config.Length.IsUnknown() ||
+		config.Lowercase.IsUnknown() ||
+		config.MinLowercase.IsUnknown() ||
+		config.Uppercase.IsUnknown() ||
+		config.MinUppercase.IsUnknown() ||
+		config.Numbers.IsUnknown() ||
+		config.MinNumber.IsUnknown() ||
+		config.Special.IsUnknown() ||
+		config.MinSpecial.IsUnknown() ||
+		config.SpecialCharacters.IsUnknown() ||
+		config.ExcludeCharacters.IsUnknown()
+}
+
 func newGeneratorConfig(plan *secretResourceModel, state *secretResourceModel) bool {
 	// Compare all relevant generator configuration attributes between plan and state
 	return plan.AvoidAmbiguous.ValueBool() != state.AvoidAmbiguous.ValueBool() ||
@@ -541,5 +1070,29 @@ func newGeneratorConfig(plan *secretResourceModel, state *secretResourceModel) b
 		plan.MinUppercase.ValueInt64() != state.MinUppercase.ValueInt64() ||
 		plan.Numbers.ValueBool() != state.Numbers.ValueBool() ||
 		plan.Special.ValueBool() != state.Special.ValueBool() ||
-		plan.Uppercase.ValueBool() != state.Uppercase.ValueBool()
+		plan.Uppercase.ValueBool() != state.Uppercase.ValueBool() ||
+		plan.SpecialCharacters.ValueString() != state.SpecialCharacters.ValueString() ||
+		plan.ExcludeCharacters.ValueString() != state.ExcludeCharacters.ValueString()
+}
+
+// projectIDs converts the planned project_id into the list of project IDs expected by the SDK.
+// A null or empty project_id results in an empty list, which removes the secret from its project.
+func projectIDs(projectID types.String) []string {
+	if projectID.IsNull() || projectID.IsUnknown() || projectID.ValueString() == "" {
+		return []string{}
+	}
+	return []string{projectID.ValueString()}
+}
+
+// projectIDValue converts the project ID returned by the SDK into its state representation. Secrets without
+// project keep the representation of the current value, so that an explicitly configured empty string is not
+// replaced by null.
+func projectIDValue(projectID *string, current types.String) types.String {
+	if projectID == nil || *projectID == "" {
+		if !current.IsUnknown() && current.ValueString() == "" {
+			return current
+		}
+		return types.StringNull()
+	}
+	return types.StringValue(*projectID)
 }
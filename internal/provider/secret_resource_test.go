@@ -3,6 +3,7 @@ package provider
 import (
 	"fmt"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 	"github.com/hashicorp/terraform-plugin-testing/terraform"
 	"regexp"
@@ -12,7 +13,7 @@ import (
 )
 
 func TestAccResourceSecretExpectErrorOnMissingKey(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	runProviderTest(t, resource.TestCase{
 		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
@@ -26,6 +27,96 @@ func TestAccResourceSecretExpectErrorOnMissingKey(t *testing.T) {
 	})
 }
 
+func TestAccResourceSecretKeyNamingPolicy(t *testing.T) {
+	keyNamingPolicy := `
+            key_naming_policy = {
+                allowed_characters = "A-Z0-9_"
+                case_style         = "upper_snake"
+                max_length         = 32
+            }`
+
+	runProviderTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: buildProviderConfigFromEnvFileWithAttributes(t, keyNamingPolicy) + `
+                       resource "bitwarden-sm_secret" "test" {
+                                key = "Db Password"
+                            }`,
+				ExpectError: regexp.MustCompile(`(?s)Secret Key Violates Naming Policy.*Use "DB_PASSWORD" instead`),
+			},
+			{
+				Config: buildProviderConfigFromEnvFileWithAttributes(t, `
+                    key_naming_policy = {
+                        pattern = "^[A-Z"
+                    }`) + `
+                       resource "bitwarden-sm_secret" "test" {
+                                key = "DB_PASSWORD"
+                            }`,
+				ExpectError: regexp.MustCompile("Invalid Key Naming Policy"),
+			},
+		},
+	})
+}
+
+func TestAccResourceSecretEnforceUniqueKey(t *testing.T) {
+	secretKey := "Test-Secret-" + generateRandomString()
+	existingValue := generateRandomString()
+	projectName := "Test-Project-" + generateRandomString()
+
+	bitwardenClient, organizationId, err := newBitwardenClient()
+	if err != nil {
+		t.Fatalf("Error creating bitwardenClient: %s", err)
+	}
+
+	project, preCheckError := bitwardenClient.Projects().Create(organizationId, projectName)
+	if preCheckError != nil {
+		t.Fatal("Error creating test project for provider validation.")
+	}
+
+	existing, preCheckError := bitwardenClient.Secrets().Create(secretKey, existingValue, "", organizationId, []string{project.ID})
+	if preCheckError != nil {
+		t.Fatal("Error creating existing test secret for provider validation.")
+	}
+
+	configError := SecretResourceConfig{}
+	configError.key = types.StringValue(secretKey)
+	configError.projectId = types.StringValue(project.ID)
+	configError.enforceUniqueKey = types.StringValue(uniqueKeyError)
+
+	configAdopt := configError
+	configAdopt.enforceUniqueKey = types.StringValue(uniqueKeyAdoptExisting)
+
+	runProviderTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      buildProviderConfigFromEnvFile(t) + buildSecretResourceConfig(configError),
+				ExpectError: regexp.MustCompile("(?s)Secret Key Already Exists.*" + existing.ID),
+			},
+			{
+				// The provider-wide mode applies to resources which do not configure one.
+				Config:      buildProviderConfigFromEnvFileWithAttributes(t, `enforce_unique_key = "error"`) + buildSecretResourceConfig(SecretResourceConfig{key: configError.key, projectId: configError.projectId}),
+				ExpectError: regexp.MustCompile("Secret Key Already Exists"),
+			},
+			{
+				Config: buildProviderConfigFromEnvFile(t) + buildSecretResourceConfig(configAdopt),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("bitwarden-sm_secret.test", "id", existing.ID),
+					resource.TestCheckResourceAttr("bitwarden-sm_secret.test", "value", existingValue),
+				),
+			},
+		},
+		CheckDestroy: func(state *terraform.State) error {
+			_, cleanUpErr := bitwardenClient.Projects().Delete([]string{project.ID})
+			if cleanUpErr != nil {
+				t.Fatalf("Error cleaning up test project: %s", cleanUpErr.Error())
+			}
+			return nil
+		},
+	})
+}
+
 func TestAccResourceSecretCreateSecretWithExplicitValue(t *testing.T) {
 	secretKey := "Test-Secret-" + generateRandomString()
 	secretValue := generateRandomString()
@@ -49,7 +140,7 @@ func TestAccResourceSecretCreateSecretWithExplicitValue(t *testing.T) {
 	config.note = types.StringValue(secretNote)
 	config.projectId = types.StringValue(project.ID)
 
-	resource.Test(t, resource.TestCase{
+	runProviderTest(t, resource.TestCase{
 		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
@@ -95,7 +186,7 @@ func TestAccResourceSecretCreateSecretWithDefaultGeneratorConfig(t *testing.T) {
 	config.note = types.StringValue(secretNote)
 	config.projectId = types.StringValue(project.ID)
 
-	resource.Test(t, resource.TestCase{
+	runProviderTest(t, resource.TestCase{
 		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
@@ -166,7 +257,7 @@ func TestAccResourceSecretCreateSecretWithCustomGeneratorConfig(t *testing.T) {
 	config.minUppercase = types.Int64Value(9)
 	config.minNumber = types.Int64Value(9)
 
-	resource.Test(t, resource.TestCase{
+	runProviderTest(t, resource.TestCase{
 		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
@@ -257,7 +348,7 @@ func TestAccResourceSecretCreateSecretWithInvalidGeneratorConfigExpectError(t *t
 	config.key = types.StringValue(secretKey)
 	config.note = types.StringValue(secretNote)
 	config.projectId = types.StringValue(project.ID)
-	config.minLowercase = types.Int64Value(10)
+	config.minLowercase = types.Int64Value(0)
 
 	config2 := SecretResourceConfig{}
 	config2.key = types.StringValue(secretKey)
@@ -268,19 +359,196 @@ func TestAccResourceSecretCreateSecretWithInvalidGeneratorConfigExpectError(t *t
 	config2.minNumber = types.Int64Value(9)
 	config2.length = types.Int64Value(26)
 
-	resource.Test(t, resource.TestCase{
+	config3 := SecretResourceConfig{}
+	config3.key = types.StringValue(secretKey)
+	config3.note = types.StringValue(secretNote)
+	config3.projectId = types.StringValue(project.ID)
+	config3.excludeCharacters = types.StringValue("0123456789")
+
+	runProviderTest(t, resource.TestCase{
 		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config: buildProviderConfigFromEnvFile(t) +
 					buildSecretResourceConfig(config),
-				ExpectError: regexp.MustCompile("Attribute min_lowercase value must be between 1 and 9, got: 10"),
+				ExpectError: regexp.MustCompile("Attribute min_lowercase value must be at least 1, got: 0"),
 			},
 			{
 				Config: buildProviderConfigFromEnvFile(t) +
 					buildSecretResourceConfig(config2),
 				ExpectError: regexp.MustCompile("Attribute length value must be at least sum of min_lowercase"),
 			},
+			{
+				Config: buildProviderConfigFromEnvFile(t) +
+					buildSecretResourceConfig(config3),
+				ExpectError: regexp.MustCompile("the numbers character class is enabled but all of its characters are\\s+excluded"),
+			},
+		},
+		CheckDestroy: func(state *terraform.State) error {
+			_, cleanUpErr := bitwardenClient.Projects().Delete([]string{project.ID})
+			if cleanUpErr != nil {
+				t.Fatalf("Error cleaning up test project: %s", cleanUpErr.Error())
+			}
+			return nil
+		},
+	})
+}
+
+func TestAccResourceSecretCreateSecretWithCustomCharacterSets(t *testing.T) {
+	secretKey := "Test-Secret-" + generateRandomString()
+	projectName := "Test-Project-" + generateRandomString()
+
+	bitwardenClient, organizationId, err := newBitwardenClient()
+
+	if err != nil {
+		t.Fatalf("Error creating bitwardenClient: %s", err)
+	}
+
+	project, preCheckError := bitwardenClient.Projects().Create(organizationId, projectName)
+	if preCheckError != nil {
+		t.Fatal("Error creating test project for provider validation.")
+	}
+
+	config := SecretResourceConfig{}
+	config.key = types.StringValue(secretKey)
+	config.projectId = types.StringValue(project.ID)
+	config.length = types.Int64Value(48)
+	config.special = types.BoolValue(true)
+	config.minSpecial = types.Int64Value(12)
+	config.minNumber = types.Int64Value(12)
+	config.specialCharacters = types.StringValue("-_.~@#")
+	config.excludeCharacters = types.StringValue("@#")
+
+	runProviderTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: buildProviderConfigFromEnvFile(t) +
+					buildSecretResourceConfig(config),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("bitwarden-sm_secret.test", "special_characters", "-_.~@#"),
+					resource.TestCheckResourceAttr("bitwarden-sm_secret.test", "exclude_characters", "@#"),
+					func(s *terraform.State) error {
+						rs, ok := s.RootModule().Resources["bitwarden-sm_secret.test"]
+						if !ok {
+							return fmt.Errorf("not found: %s", "bitwarden-sm_secret.test")
+						}
+
+						value := rs.Primary.Attributes["value"]
+						if int64(len(value)) != config.length.ValueInt64() {
+							return fmt.Errorf("length: %d does not match custom generator config: %d", len(value), config.length.ValueInt64())
+						}
+						if strings.ContainsAny(value, "@#") {
+							return fmt.Errorf("generated value contains excluded characters")
+						}
+
+						specialCount, digitCount := int64(0), int64(0)
+						for _, char := range value {
+							if strings.ContainsRune("-_.~", char) {
+								specialCount++
+							} else if unicode.IsDigit(char) {
+								digitCount++
+							}
+						}
+						if config.minSpecial.ValueInt64() > specialCount {
+							return fmt.Errorf("specialCount: %d does not match custom generator config: minSpecial = %d", specialCount, config.minSpecial.ValueInt64())
+						}
+						if config.minNumber.ValueInt64() > digitCount {
+							return fmt.Errorf("digitCount: %d does not match custom generator config: minNumber = %d", digitCount, config.minNumber.ValueInt64())
+						}
+
+						return nil
+					},
+				),
+			},
+		},
+		CheckDestroy: func(state *terraform.State) error {
+			_, cleanUpErr := bitwardenClient.Projects().Delete([]string{project.ID})
+			if cleanUpErr != nil {
+				t.Fatalf("Error cleaning up test project: %s", cleanUpErr.Error())
+			}
+			return nil
+		},
+	})
+}
+
+func TestAccResourceSecretCreateSecretWithJSONValue(t *testing.T) {
+	secretKey := "Test-Secret-" + generateRandomString()
+	secretNote := generateRandomString()
+	projectName := "Test-Project-" + generateRandomString()
+
+	bitwardenClient, organizationId, err := newBitwardenClient()
+
+	if err != nil {
+		t.Fatalf("Error creating bitwardenClient: %s", err)
+	}
+
+	project, preCheckError := bitwardenClient.Projects().Create(organizationId, projectName)
+	if preCheckError != nil {
+		t.Fatal("Error creating test project for provider validation.")
+	}
+
+	config := SecretResourceConfig{}
+	config.key = types.StringValue(secretKey)
+	config.note = types.StringValue(secretNote)
+	config.projectId = types.StringValue(project.ID)
+	config.valueJSON = types.StringValue(`{ user = "admin", port = 5432, hosts = ["a", "b"] }`)
+
+	invalidConfig := config
+	invalidConfig.valueJSON = types.StringValue(`"not a json document"`)
+
+	conflictingConfig := config
+	conflictingConfig.value = types.StringValue("value")
+
+	runProviderTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: buildProviderConfigFromEnvFile(t) +
+					buildSecretResourceConfig(invalidConfig),
+				ExpectError: regexp.MustCompile("string attribute not a valid JSON document"),
+			},
+			{
+				Config: buildProviderConfigFromEnvFile(t) +
+					buildSecretResourceConfig(conflictingConfig),
+				ExpectError: regexp.MustCompile("Invalid Attribute Combination"),
+			},
+			{
+				Config: buildProviderConfigFromEnvFile(t) +
+					buildSecretResourceConfig(config),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("bitwarden-sm_secret.test", "key", secretKey),
+					resource.TestCheckResourceAttr("bitwarden-sm_secret.test", "value", `{"hosts":["a","b"],"port":5432,"user":"admin"}`),
+					resource.TestCheckResourceAttr("bitwarden-sm_secret.test", "value_json.user", "admin"),
+
+					// Reformat the stored JSON document outside terraform without changing its content.
+					func(s *terraform.State) error {
+						rs, ok := s.RootModule().Resources["bitwarden-sm_secret.test"]
+						if !ok {
+							return fmt.Errorf("not found: %s", "bitwarden-sm_secret.test")
+						}
+						_, updateErr := bitwardenClient.Secrets().Update(
+							rs.Primary.ID,
+							secretKey,
+							"{\n  \"user\": \"admin\",\n  \"hosts\": [\"a\", \"b\"],\n  \"port\": 5432\n}\n",
+							secretNote,
+							organizationId,
+							[]string{project.ID},
+						)
+						if updateErr != nil {
+							return fmt.Errorf("unable to Update Secret: %s", updateErr.Error())
+						}
+						return nil
+					},
+				),
+			},
+			{
+				// Whitespace and key order differences must not produce a plan.
+				Config: buildProviderConfigFromEnvFile(t) +
+					buildSecretResourceConfig(config),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: false,
+			},
 		},
 		CheckDestroy: func(state *terraform.State) error {
 			_, cleanUpErr := bitwardenClient.Projects().Delete([]string{project.ID})
@@ -336,7 +604,7 @@ func TestAccResourceSecretUpdateSecretWithExplicitValue(t *testing.T) {
 	config5 := config4
 	config5.projectId = types.StringValue(updatedProject.ID)
 
-	resource.Test(t, resource.TestCase{
+	runProviderTest(t, resource.TestCase{
 		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
@@ -433,7 +701,7 @@ func TestAccResourceSecretDeleteSecretWithExplicitValue(t *testing.T) {
 	config.note = types.StringValue(secretNote)
 	config.projectId = types.StringValue(project.ID)
 
-	resource.Test(t, resource.TestCase{
+	runProviderTest(t, resource.TestCase{
 		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
@@ -517,7 +785,7 @@ func TestAccResourceSecretImportSecret(t *testing.T) {
 
 	secretId := secret.ID
 
-	resource.Test(t, resource.TestCase{
+	runProviderTest(t, resource.TestCase{
 		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
@@ -580,7 +848,7 @@ func TestAccResourceSecretDynamicSecret(t *testing.T) {
 	config.note = types.StringValue(secretNote)
 	config.projectId = types.StringValue(project.ID)
 
-	resource.Test(t, resource.TestCase{
+	runProviderTest(t, resource.TestCase{
 		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
@@ -644,3 +912,364 @@ func TestAccResourceSecretDynamicSecret(t *testing.T) {
 		},
 	})
 }
+
+// This acceptance test validates the drift_policy attribute: a secret value changed outside terraform is reverted,
+// fails the plan or is adopted depending on the configured policy.
+func TestAccResourceSecretDriftPolicy(t *testing.T) {
+	secretKey := "Test-Secret-" + generateRandomString()
+	driftedSecretValue := generateRandomString()
+	projectName := "Test-Project-" + generateRandomString()
+
+	bitwardenClient, organizationId, err := newBitwardenClient()
+	if err != nil {
+		t.Fatalf("Error creating bitwardenClient: %s", err)
+	}
+
+	project, preCheckError := bitwardenClient.Projects().Create(organizationId, projectName)
+	if preCheckError != nil {
+		t.Fatal("Error creating test project for provider validation.")
+	}
+
+	config := SecretResourceConfig{}
+	config.key = types.StringValue(secretKey)
+	config.projectId = types.StringValue(project.ID)
+	config.driftPolicy = types.StringValue(driftPolicyRevert)
+
+	configError := config
+	configError.driftPolicy = types.StringValue(driftPolicyError)
+
+	configAdopt := config
+	configAdopt.driftPolicy = types.StringValue(driftPolicyAdopt)
+
+	var generatedSecretValue string
+
+	// changeSecretValue updates the secret value outside terraform.
+	changeSecretValue := func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources["bitwarden-sm_secret.test"]
+		if !ok {
+			return fmt.Errorf("not found: %s", "bitwarden-sm_secret.test")
+		}
+		_, updateErr := bitwardenClient.Secrets().Update(
+			rs.Primary.ID,
+			secretKey,
+			driftedSecretValue,
+			"",
+			organizationId,
+			[]string{project.ID},
+		)
+		if updateErr != nil {
+			return fmt.Errorf("unable to Update Secret: %s", updateErr.Error())
+		}
+		return nil
+	}
+
+	runProviderTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: buildProviderConfigFromEnvFile(t) +
+					buildSecretResourceConfig(config),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("bitwarden-sm_secret.test", "drift_policy", driftPolicyRevert),
+					func(s *terraform.State) error {
+						generatedSecretValue = s.RootModule().Resources["bitwarden-sm_secret.test"].Primary.Attributes["value"]
+						return nil
+					},
+					changeSecretValue,
+				),
+			},
+			{
+				// The drifted value is reverted to the generated value, both in the state and in Bitwarden Secrets Manager.
+				Config: buildProviderConfigFromEnvFile(t) +
+					buildSecretResourceConfig(config),
+				Check: resource.ComposeTestCheckFunc(
+					func(s *terraform.State) error {
+						rs := s.RootModule().Resources["bitwarden-sm_secret.test"]
+						if rs.Primary.Attributes["value"] != generatedSecretValue {
+							return fmt.Errorf("expected the generated secret value to be restored in the state")
+						}
+						secret, getErr := bitwardenClient.Secrets().Get(rs.Primary.ID)
+						if getErr != nil {
+							return fmt.Errorf("unable to Read Secret: %s", getErr.Error())
+						}
+						if secret.Value != generatedSecretValue {
+							return fmt.Errorf("expected the generated secret value to be restored in Bitwarden Secrets Manager")
+						}
+						return nil
+					},
+					changeSecretValue,
+				),
+			},
+			{
+				Config: buildProviderConfigFromEnvFile(t) +
+					buildSecretResourceConfig(configError),
+				ExpectError: regexp.MustCompile("Secret Value Changed Outside of Terraform"),
+			},
+			{
+				Config: buildProviderConfigFromEnvFile(t) +
+					buildSecretResourceConfig(configAdopt),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("bitwarden-sm_secret.test", "drift_policy", driftPolicyAdopt),
+					resource.TestCheckResourceAttr("bitwarden-sm_secret.test", "value", driftedSecretValue),
+				),
+			},
+		},
+		CheckDestroy: func(state *terraform.State) error {
+			_, cleanUpErr := bitwardenClient.Projects().Delete([]string{project.ID})
+			if cleanUpErr != nil {
+				t.Fatalf("Error cleaning up test project: %s", cleanUpErr.Error())
+			}
+			return nil
+		},
+	})
+}
+
+// This acceptance test validates that removing the note or the project from the configuration clears them in
+// Bitwarden Secrets Manager and that setting them again afterward restores them.
+func TestAccResourceSecretClearNoteAndProject(t *testing.T) {
+	secretKey := "Test-Secret-" + generateRandomString()
+	secretValue := generateRandomString()
+	secretNote := generateRandomString()
+	projectName := "Test-Project-" + generateRandomString()
+
+	bitwardenClient, organizationId, err := newBitwardenClient()
+	if err != nil {
+		t.Fatalf("Error creating bitwardenClient: %s", err)
+	}
+
+	project, preCheckError := bitwardenClient.Projects().Create(organizationId, projectName)
+	if preCheckError != nil {
+		t.Fatal("Error creating test project for provider validation.")
+	}
+
+	config := SecretResourceConfig{}
+	config.key = types.StringValue(secretKey)
+	config.value = types.StringValue(secretValue)
+	config.note = types.StringValue(secretNote)
+	config.projectId = types.StringValue(project.ID)
+
+	configWithoutNote := config
+	configWithoutNote.note = types.StringNull()
+
+	configWithoutProject := config
+	configWithoutProject.projectId = types.StringNull()
+
+	runProviderTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: buildProviderConfigFromEnvFile(t) +
+					buildSecretResourceConfig(config),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("bitwarden-sm_secret.test", "note", secretNote),
+					resource.TestCheckResourceAttr("bitwarden-sm_secret.test", "project_id", project.ID),
+				),
+			},
+			{
+				Config: buildProviderConfigFromEnvFile(t) +
+					buildSecretResourceConfig(configWithoutNote),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("bitwarden-sm_secret.test", "note", ""),
+					resource.TestCheckResourceAttr("bitwarden-sm_secret.test", "project_id", project.ID),
+				),
+			},
+			{
+				Config: buildProviderConfigFromEnvFile(t) +
+					buildSecretResourceConfig(config),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("bitwarden-sm_secret.test", "note", secretNote),
+					resource.TestCheckResourceAttr("bitwarden-sm_secret.test", "project_id", project.ID),
+				),
+			},
+			{
+				Config: buildProviderConfigFromEnvFile(t) +
+					buildSecretResourceConfig(configWithoutProject),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("bitwarden-sm_secret.test", "note", secretNote),
+					resource.TestCheckNoResourceAttr("bitwarden-sm_secret.test", "project_id"),
+				),
+			},
+			{
+				Config: buildProviderConfigFromEnvFile(t) +
+					buildSecretResourceConfig(config),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("bitwarden-sm_secret.test", "note", secretNote),
+					resource.TestCheckResourceAttr("bitwarden-sm_secret.test", "project_id", project.ID),
+				),
+			},
+		},
+		CheckDestroy: func(state *terraform.State) error {
+			_, cleanUpErr := bitwardenClient.Projects().Delete([]string{project.ID})
+			if cleanUpErr != nil {
+				t.Fatalf("Error cleaning up test project: %s", cleanUpErr.Error())
+			}
+			return nil
+		},
+	})
+}
+
+func TestProjectIDs(t *testing.T) {
+	if ids := projectIDs(types.StringNull()); len(ids) != 0 {
+		t.Fatalf("expected no project IDs for null project_id, got %v", ids)
+	}
+	if ids := projectIDs(types.StringValue("")); len(ids) != 0 {
+		t.Fatalf("expected no project IDs for empty project_id, got %v", ids)
+	}
+	if ids := projectIDs(types.StringValue(validProjectUUID)); len(ids) != 1 || ids[0] != validProjectUUID {
+		t.Fatalf("expected [%s], got %v", validProjectUUID, ids)
+	}
+}
+
+func TestProjectIDValue(t *testing.T) {
+	projectID := validProjectUUID
+	emptyProjectID := ""
+
+	if value := projectIDValue(&projectID, types.StringNull()); !value.Equal(types.StringValue(validProjectUUID)) {
+		t.Fatalf("expected %s, got %s", validProjectUUID, value)
+	}
+	if value := projectIDValue(nil, types.StringValue(validProjectUUID)); !value.IsNull() {
+		t.Fatalf("expected null for secret without project, got %s", value)
+	}
+	if value := projectIDValue(&emptyProjectID, types.StringNull()); !value.IsNull() {
+		t.Fatalf("expected null for secret without project, got %s", value)
+	}
+	if value := projectIDValue(nil, types.StringValue("")); !value.Equal(types.StringValue("")) {
+		t.Fatalf("expected configured empty string to be kept, got %s", value)
+	}
+}
+
+func TestSecretResourceDeletionProtection(t *testing.T) {
+	store := newFakeBitwardenStore(fakeOrganizationID)
+	harness := newResourceHarness(t, "bitwarden-sm_secret", store.newClient())
+
+	protected := secretConfig("value")
+	protected["deletion_protection"] = tftypes.NewValue(tftypes.Bool, true)
+	state, diagnostics := harness.apply(protected, harness.nullState())
+	expectDiagnosticError(t, diagnostics, "")
+
+	state, diagnostics = harness.destroy(state)
+	expectDiagnosticError(t, diagnostics, "Deletion Protection Enabled")
+	if stored := len(storedValues(store)); stored != 1 {
+		t.Fatalf("expected the protected secret to be kept, got %d stored secrets", stored)
+	}
+
+	// Removing deletion_protection from the configuration keeps the protection instead of planning the default.
+	state, diagnostics = harness.apply(secretConfig("value"), state)
+	expectDiagnosticError(t, diagnostics, "")
+	state, diagnostics = harness.destroy(state)
+	expectDiagnosticError(t, diagnostics, "Deletion Protection Enabled")
+
+	unprotected := secretConfig("value")
+	unprotected["deletion_protection"] = tftypes.NewValue(tftypes.Bool, false)
+	state, diagnostics = harness.apply(unprotected, state)
+	expectDiagnosticError(t, diagnostics, "")
+	state, diagnostics = harness.destroy(state)
+	expectDiagnosticError(t, diagnostics, "")
+	if !state.value.IsNull() {
+		t.Fatal("expected the destroy to remove the state")
+	}
+	if stored := len(storedValues(store)); stored != 0 {
+		t.Fatalf("expected no stored secrets, got %d", stored)
+	}
+}
+
+func TestSecretResourceNormalization(t *testing.T) {
+	testCases := map[string]struct {
+		trimTrailingNewline tftypes.Value
+		expectedValue       string
+	}{
+		"trailing newline ignored by default": {
+			trimTrailingNewline: tftypes.NewValue(tftypes.Bool, nil),
+			expectedValue:       "value\n",
+		},
+		"trailing newline detected without trimming": {
+			trimTrailingNewline: tftypes.NewValue(tftypes.Bool, false),
+			expectedValue:       "value",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			store := newFakeBitwardenStore(fakeOrganizationID)
+			harness := newResourceHarness(t, "bitwarden-sm_secret", store.newClient())
+
+			config := secretConfig("value\n")
+			config["trim_trailing_newline"] = testCase.trimTrailingNewline
+			config["drift_policy"] = tftypes.NewValue(tftypes.String, driftPolicyAdopt)
+			state, diagnostics := harness.apply(config, harness.nullState())
+			expectDiagnosticError(t, diagnostics, "")
+
+			// The trailing newline is removed outside of terraform.
+			id := harness.stringAttribute(state, "id")
+			changed := store.secrets[id]
+			changed.Value = "value"
+			store.secrets[id] = changed
+
+			state, diagnostics = harness.read(state)
+			expectDiagnosticError(t, diagnostics, "")
+			if value := harness.stringAttribute(state, "value"); value != testCase.expectedValue {
+				t.Fatalf("expected the value %q in the state, got %q", testCase.expectedValue, value)
+			}
+		})
+	}
+}
+
+func TestSecretResourceNormalizationPlan(t *testing.T) {
+	testCases := map[string]struct {
+		attributes  map[string]tftypes.Value
+		value       string
+		note        string
+		expectEmpty bool
+	}{
+		"trailing newline added to the value": {
+			value:       "line1\nline2\n",
+			note:        "note",
+			expectEmpty: true,
+		},
+		"CRLF line endings in value and note": {
+			value:       "line1\r\nline2",
+			note:        "note\r\n",
+			expectEmpty: true,
+		},
+		"changed value": {
+			value:       "line1\nline3",
+			note:        "note",
+			expectEmpty: false,
+		},
+		"trailing newline without trimming": {
+			attributes:  map[string]tftypes.Value{"trim_trailing_newline": tftypes.NewValue(tftypes.Bool, false)},
+			value:       "line1\nline2\n",
+			note:        "note",
+			expectEmpty: false,
+		},
+		"CRLF without line ending normalisation": {
+			attributes:  map[string]tftypes.Value{"normalize_line_endings": tftypes.NewValue(tftypes.Bool, false)},
+			value:       "line1\r\nline2",
+			note:        "note",
+			expectEmpty: false,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			store := newFakeBitwardenStore(fakeOrganizationID)
+			harness := newResourceHarness(t, "bitwarden-sm_secret", store.newClient())
+
+			config := secretConfig("line1\nline2")
+			config["note"] = tftypes.NewValue(tftypes.String, "note")
+			for name, value := range testCase.attributes {
+				config[name] = value
+			}
+			state, diagnostics := harness.apply(config, harness.nullState())
+			expectDiagnosticError(t, diagnostics, "")
+
+			config["value"] = tftypes.NewValue(tftypes.String, testCase.value)
+			config["note"] = tftypes.NewValue(tftypes.String, testCase.note)
+			planned, diagnostics := harness.plan(config, state)
+			expectDiagnosticError(t, diagnostics, "")
+			if empty := planned.Equal(state.value); empty != testCase.expectEmpty {
+				t.Fatalf("expected an empty plan: %t, got the planned state %s", testCase.expectEmpty, planned)
+			}
+		})
+	}
+}
@@ -0,0 +1,642 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/bitwarden/sdk-go"
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// standInOrganizationID is the organization of the access token accepted by apiStandIn.
+const standInOrganizationID = "3b2c3d6e-9b3f-4a7e-8f3a-b2a600f0c1f5"
+
+type standInProject struct {
+	ID             string    `json:"id"`
+	OrganizationID string    `json:"organizationId"`
+	Name           string    `json:"name"`
+	CreationDate   time.Time `json:"creationDate"`
+	RevisionDate   time.Time `json:"revisionDate"`
+	Read           bool      `json:"read"`
+	Write          bool      `json:"write"`
+	Object         string    `json:"object"`
+}
+
+type standInProjectReference struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type standInSecret struct {
+	ID             string                    `json:"id"`
+	OrganizationID string                    `json:"organizationId"`
+	Key            string                    `json:"key"`
+	Value          string                    `json:"value"`
+	Note           string                    `json:"note"`
+	CreationDate   time.Time                 `json:"creationDate"`
+	RevisionDate   time.Time                 `json:"revisionDate"`
+	Projects       []standInProjectReference `json:"projects"`
+	Read           bool                      `json:"read"`
+	Write          bool                      `json:"write"`
+	Object         string                    `json:"object"`
+}
+
+type standInSecretRequest struct {
+	Key        string   `json:"key"`
+	Value      string   `json:"value"`
+	Note       string   `json:"note"`
+	ProjectIDs []string `json:"projectIds"`
+}
+
+// secretsManagerStandIn stores projects and secrets in memory and serves the endpoints used by the Bitwarden SDK, so
+// the real SDK client and the Configure method of the provider can run against it. Names, keys, values and notes are
+// stored encrypted with the organization key, just like the API stores them.
+type secretsManagerStandIn struct {
+	*apiStandIn
+
+	mu       sync.Mutex
+	projects map[string]standInProject
+	secrets  map[string]standInSecret
+}
+
+func newSecretsManagerStandIn(t *testing.T) *secretsManagerStandIn {
+	standIn := &secretsManagerStandIn{
+		apiStandIn: newAPIStandIn(t),
+		projects:   map[string]standInProject{},
+		secrets:    map[string]standInSecret{},
+	}
+
+	standIn.handle("POST /organizations/{orgId}/projects", func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			Name string `json:"name"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil || request.Name == "" {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"message": "The Name field is required."})
+			return
+		}
+		standIn.mu.Lock()
+		defer standIn.mu.Unlock()
+		if !standIn.isOrganization(w, r) {
+			return
+		}
+		now := time.Now().UTC()
+		project := standInProject{
+			ID:             uuid.NewString(),
+			OrganizationID: r.PathValue("orgId"),
+			Name:           request.Name,
+			CreationDate:   now,
+			RevisionDate:   now,
+			Read:           true,
+			Write:          true,
+			Object:         "project",
+		}
+		standIn.projects[project.ID] = project
+		writeJSON(w, http.StatusOK, project)
+	})
+	standIn.handle("GET /organizations/{orgId}/projects", func(w http.ResponseWriter, r *http.Request) {
+		standIn.mu.Lock()
+		defer standIn.mu.Unlock()
+		if !standIn.isOrganization(w, r) {
+			return
+		}
+		projects := []standInProject{}
+		for _, id := range sortedKeys(standIn.projects) {
+			projects = append(projects, standIn.projects[id])
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"data": projects, "object": "list"})
+	})
+	standIn.handle("GET /projects/{id}", func(w http.ResponseWriter, r *http.Request) {
+		standIn.mu.Lock()
+		defer standIn.mu.Unlock()
+		project, ok := standIn.projects[r.PathValue("id")]
+		if !ok {
+			writeStandInError(w, http.StatusNotFound)
+			return
+		}
+		writeJSON(w, http.StatusOK, project)
+	})
+	standIn.handle("PUT /projects/{id}", func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			Name string `json:"name"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil || request.Name == "" {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"message": "The Name field is required."})
+			return
+		}
+		standIn.mu.Lock()
+		defer standIn.mu.Unlock()
+		project, ok := standIn.projects[r.PathValue("id")]
+		if !ok {
+			writeStandInError(w, http.StatusNotFound)
+			return
+		}
+		project.Name = request.Name
+		project.RevisionDate = time.Now().UTC()
+		standIn.projects[project.ID] = project
+		writeJSON(w, http.StatusOK, project)
+	})
+	standIn.handle("POST /projects/delete", func(w http.ResponseWriter, r *http.Request) {
+		var ids []string
+		if err := json.NewDecoder(r.Body).Decode(&ids); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		standIn.mu.Lock()
+		defer standIn.mu.Unlock()
+		results := []map[string]any{}
+		for _, id := range ids {
+			if _, ok := standIn.projects[id]; !ok {
+				results = append(results, map[string]any{"id": id, "error": "access denied"})
+				continue
+			}
+			delete(standIn.projects, id)
+			for secretID, secret := range standIn.secrets {
+				if len(secret.Projects) > 0 && secret.Projects[0].ID == id {
+					secret.Projects = []standInProjectReference{}
+					standIn.secrets[secretID] = secret
+				}
+			}
+			results = append(results, map[string]any{"id": id, "error": nil})
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"data": results, "object": "list"})
+	})
+
+	standIn.handle("POST /organizations/{orgId}/secrets", func(w http.ResponseWriter, r *http.Request) {
+		standIn.mu.Lock()
+		defer standIn.mu.Unlock()
+		if !standIn.isOrganization(w, r) {
+			return
+		}
+		request, projects, ok := standIn.decodeSecretRequest(w, r)
+		if !ok {
+			return
+		}
+		now := time.Now().UTC()
+		secret := standInSecret{
+			ID:             uuid.NewString(),
+			OrganizationID: r.PathValue("orgId"),
+			Key:            request.Key,
+			Value:          request.Value,
+			Note:           request.Note,
+			CreationDate:   now,
+			RevisionDate:   now,
+			Projects:       projects,
+			Read:           true,
+			Write:          true,
+			Object:         "secret",
+		}
+		standIn.secrets[secret.ID] = secret
+		writeJSON(w, http.StatusOK, secret)
+	})
+	standIn.handle("GET /organizations/{orgId}/secrets", func(w http.ResponseWriter, r *http.Request) {
+		standIn.mu.Lock()
+		defer standIn.mu.Unlock()
+		if !standIn.isOrganization(w, r) {
+			return
+		}
+		secrets := []map[string]any{}
+		for _, id := range sortedKeys(standIn.secrets) {
+			secret := standIn.secrets[id]
+			secrets = append(secrets, map[string]any{
+				"id":             secret.ID,
+				"organizationId": secret.OrganizationID,
+				"key":            secret.Key,
+				"creationDate":   secret.CreationDate,
+				"revisionDate":   secret.RevisionDate,
+				"projects":       secret.Projects,
+				"read":           secret.Read,
+				"write":          secret.Write,
+			})
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"secrets": secrets, "projects": []any{}, "object": "SecretsWithProjectsList"})
+	})
+	standIn.handle("GET /organizations/{orgId}/secrets/sync", func(w http.ResponseWriter, r *http.Request) {
+		standIn.mu.Lock()
+		defer standIn.mu.Unlock()
+		if !standIn.isOrganization(w, r) {
+			return
+		}
+		hasChanges := true
+		if lastSyncedDate := r.URL.Query().Get("lastSyncedDate"); lastSyncedDate != "" {
+			since, err := time.Parse(time.RFC3339Nano, lastSyncedDate)
+			if err != nil {
+				writeJSON(w, http.StatusBadRequest, map[string]string{"message": "The value is not a valid date."})
+				return
+			}
+			hasChanges = false
+			for _, secret := range standIn.secrets {
+				hasChanges = hasChanges || secret.RevisionDate.After(since)
+			}
+		}
+		if !hasChanges {
+			writeJSON(w, http.StatusOK, map[string]any{"hasChanges": false, "secrets": nil, "object": "secretsSync"})
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"hasChanges": true, "secrets": map[string]any{"data": standIn.sortedSecrets(), "object": "list"}, "object": "secretsSync"})
+	})
+	standIn.handle("GET /secrets/{id}", func(w http.ResponseWriter, r *http.Request) {
+		standIn.mu.Lock()
+		defer standIn.mu.Unlock()
+		secret, ok := standIn.secrets[r.PathValue("id")]
+		if !ok {
+			writeStandInError(w, http.StatusNotFound)
+			return
+		}
+		writeJSON(w, http.StatusOK, secret)
+	})
+	standIn.handle("POST /secrets/get-by-ids", func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			IDs []string `json:"ids"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		standIn.mu.Lock()
+		defer standIn.mu.Unlock()
+		secrets := []standInSecret{}
+		for _, id := range request.IDs {
+			secret, ok := standIn.secrets[id]
+			if !ok {
+				writeStandInError(w, http.StatusNotFound)
+				return
+			}
+			secrets = append(secrets, secret)
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"data": secrets, "object": "list"})
+	})
+	standIn.handle("PUT /secrets/{id}", func(w http.ResponseWriter, r *http.Request) {
+		standIn.mu.Lock()
+		defer standIn.mu.Unlock()
+		secret, ok := standIn.secrets[r.PathValue("id")]
+		if !ok {
+			writeStandInError(w, http.StatusNotFound)
+			return
+		}
+		request, projects, ok := standIn.decodeSecretRequest(w, r)
+		if !ok {
+			return
+		}
+		secret.Key = request.Key
+		secret.Value = request.Value
+		secret.Note = request.Note
+		secret.Projects = projects
+		secret.RevisionDate = time.Now().UTC()
+		standIn.secrets[secret.ID] = secret
+		writeJSON(w, http.StatusOK, secret)
+	})
+	standIn.handle("POST /secrets/delete", func(w http.ResponseWriter, r *http.Request) {
+		var ids []string
+		if err := json.NewDecoder(r.Body).Decode(&ids); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		standIn.mu.Lock()
+		defer standIn.mu.Unlock()
+		results := []map[string]any{}
+		for _, id := range ids {
+			if _, ok := standIn.secrets[id]; !ok {
+				results = append(results, map[string]any{"id": id, "error": "access denied"})
+				continue
+			}
+			delete(standIn.secrets, id)
+			results = append(results, map[string]any{"id": id, "error": nil})
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"data": results, "object": "list"})
+	})
+
+	return standIn
+}
+
+// isOrganization responds with not found if the request is not for the organization of the access token.
+func (s *secretsManagerStandIn) isOrganization(w http.ResponseWriter, r *http.Request) bool {
+	if r.PathValue("orgId") != standInOrganizationID {
+		writeStandInError(w, http.StatusNotFound)
+		return false
+	}
+	return true
+}
+
+// decodeSecretRequest decodes the body of a secret create or update request and resolves its project. The caller
+// must hold the lock of the stand-in.
+func (s *secretsManagerStandIn) decodeSecretRequest(w http.ResponseWriter, r *http.Request) (standInSecretRequest, []standInProjectReference, bool) {
+	var request standInSecretRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil || request.Key == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"message": "The Key field is required."})
+		return request, nil, false
+	}
+	if len(request.ProjectIDs) > 1 {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"message": "Only one project can be assigned to a secret."})
+		return request, nil, false
+	}
+
+	projects := []standInProjectReference{}
+	for _, id := range request.ProjectIDs {
+		project, ok := s.projects[id]
+		if !ok {
+			writeStandInError(w, http.StatusNotFound)
+			return request, nil, false
+		}
+		projects = append(projects, standInProjectReference{ID: project.ID, Name: project.Name})
+	}
+	return request, projects, true
+}
+
+// sortedSecrets returns all secrets in the order of their creation. The caller must hold the lock of the stand-in.
+func (s *secretsManagerStandIn) sortedSecrets() []standInSecret {
+	secrets := make([]standInSecret, 0, len(s.secrets))
+	for _, id := range sortedKeys(s.secrets) {
+		secrets = append(secrets, s.secrets[id])
+	}
+	sort.SliceStable(secrets, func(i, j int) bool {
+		return secrets[i].CreationDate.Before(secrets[j].CreationDate)
+	})
+	return secrets
+}
+
+// decrypt returns the plaintext of a value stored by the stand-in.
+func (s *secretsManagerStandIn) decrypt(t *testing.T, encString string) string {
+	t.Helper()
+
+	plaintext, err := decryptString(s.orgKey, encString)
+	if err != nil {
+		t.Fatalf("unable to decrypt %q: %s", encString, err)
+	}
+	return string(plaintext)
+}
+
+// urls returns the API and identity URL of the stand-in.
+func (s *secretsManagerStandIn) urls() (string, string) {
+	return s.server.URL + "/api", s.server.URL + "/identity"
+}
+
+// sdkClient returns a client of the Bitwarden SDK which is logged in at the stand-in.
+func (s *secretsManagerStandIn) sdkClient(t *testing.T) sdk.BitwardenClientInterface {
+	t.Helper()
+
+	apiUrl, identityUrl := s.urls()
+	client, err := sdk.NewBitwardenClient(&apiUrl, &identityUrl)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	t.Cleanup(client.Close)
+
+	if err := client.AccessTokenLogin(testAPIAccessToken, nil); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	return client
+}
+
+// configureProvider runs Configure of the provider with the real Bitwarden SDK and the given configuration values,
+// all other attributes are null. The SDK writes its state file to the working directory, so the test runs in a
+// temporary directory.
+func configureProvider(t *testing.T, values map[string]tftypes.Value) provider.ConfigureResponse {
+	t.Helper()
+
+	workingDir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	t.Cleanup(func() {
+		_ = os.Chdir(workingDir)
+	})
+
+	ctx := context.Background()
+	p := &BitwardenSecretsManagerProvider{version: "test"}
+	schemaResponse := provider.SchemaResponse{}
+	p.Schema(ctx, provider.SchemaRequest{}, &schemaResponse)
+
+	objectType := schemaResponse.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	attributes := map[string]tftypes.Value{}
+	for name, attributeType := range objectType.AttributeTypes {
+		attributes[name] = tftypes.NewValue(attributeType, nil)
+	}
+	for name, value := range values {
+		attributes[name] = value
+	}
+
+	response := provider.ConfigureResponse{}
+	p.Configure(ctx, provider.ConfigureRequest{
+		Config: tfsdk.Config{Schema: schemaResponse.Schema, Raw: tftypes.NewValue(objectType, attributes)},
+	}, &response)
+	return response
+}
+
+func standInProviderConfig(apiUrl string, identityUrl string, accessToken string) map[string]tftypes.Value {
+	return map[string]tftypes.Value{
+		"api_url":         tftypes.NewValue(tftypes.String, apiUrl),
+		"identity_url":    tftypes.NewValue(tftypes.String, identityUrl),
+		"access_token":    tftypes.NewValue(tftypes.String, accessToken),
+		"organization_id": tftypes.NewValue(tftypes.String, standInOrganizationID),
+	}
+}
+
+func TestStandInSDKLifecycle(t *testing.T) {
+	standIn := newSecretsManagerStandIn(t)
+	client := standIn.sdkClient(t)
+
+	project, err := client.Projects().Create(standInOrganizationID, "project")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	secret, err := client.Secrets().Create("API_KEY", "value", "note", standInOrganizationID, []string{project.ID})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// The SDK encrypts all names, keys, values and notes with the organization key before they are sent.
+	stored := standIn.secrets[secret.ID]
+	if stored.Value == "value" || standIn.decrypt(t, stored.Key) != "API_KEY" || standIn.decrypt(t, stored.Value) != "value" ||
+		standIn.decrypt(t, stored.Note) != "note" || standIn.decrypt(t, standIn.projects[project.ID].Name) != "project" {
+		t.Fatalf("expected the secret to be stored encrypted, got: %+v", stored)
+	}
+
+	read, err := client.Secrets().Get(secret.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if read.Key != "API_KEY" || read.Value != "value" || read.Note != "note" || read.ProjectID == nil || *read.ProjectID != project.ID {
+		t.Fatalf("unexpected secret: %+v", read)
+	}
+
+	updated, err := client.Secrets().Update(secret.ID, "API_KEY", "changed", "", standInOrganizationID, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if updated.Value != "changed" || updated.ProjectID != nil {
+		t.Fatalf("unexpected secret: %+v", updated)
+	}
+
+	identifiers, err := client.Secrets().List(standInOrganizationID)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(identifiers.Data) != 1 || identifiers.Data[0].Key != "API_KEY" {
+		t.Fatalf("unexpected secrets: %+v", identifiers.Data)
+	}
+
+	lastSynced := time.Now().UTC().Add(time.Minute)
+	synced, err := client.Secrets().Sync(standInOrganizationID, &lastSynced)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if synced.HasChanges {
+		t.Fatalf("expected no changes, got: %+v", synced)
+	}
+
+	deleted, err := client.Secrets().Delete([]string{secret.ID, secret.ID})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if deleted.Data[0].Error != nil || deleted.Data[1].Error == nil {
+		t.Fatalf("unexpected delete response: %+v", deleted.Data)
+	}
+
+	if _, err := client.Projects().Delete([]string{project.ID}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	projects, err := client.Projects().List(standInOrganizationID)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(projects.Data) != 0 {
+		t.Fatalf("expected no projects, got: %+v", projects.Data)
+	}
+}
+
+func TestStandInSDKErrorResponses(t *testing.T) {
+	standIn := newSecretsManagerStandIn(t)
+	client := standIn.sdkClient(t)
+
+	secret, err := client.Secrets().Create("API_KEY", "value", "", standInOrganizationID, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// The errors of the real SDK pin the classification of classifyRequestError.
+	tests := map[int]struct {
+		expected string
+		kind     requestErrorKind
+	}{
+		http.StatusUnauthorized:        {expected: "[401 Unauthorized]", kind: requestRejected},
+		http.StatusNotFound:            {expected: "[404 Not Found] {\"message\":\"Resource not found.\"", kind: requestNotFound},
+		http.StatusTooManyRequests:     {expected: "[429 Too Many Requests] Slow down! Too many requests.", kind: requestRateLimited},
+		http.StatusInternalServerError: {expected: "[500 Internal Server Error] {\"message\":\"An error has occurred.\"", kind: requestAmbiguous},
+		http.StatusBadGateway:          {expected: "[502 Bad Gateway]", kind: requestAmbiguous},
+	}
+
+	for statusCode, test := range tests {
+		t.Run(http.StatusText(statusCode), func(t *testing.T) {
+			standIn.failNext("GET /secrets/"+secret.ID, statusCode)
+
+			_, err := client.Secrets().Get(secret.ID)
+			if err == nil || !strings.Contains(err.Error(), test.expected) {
+				t.Fatalf("expected an error containing %q, got: %v", test.expected, err)
+			}
+			if kind := classifyRequestError(err); kind != test.kind {
+				t.Fatalf("expected %q to be classified as %d, got %d", err, test.kind, kind)
+			}
+
+			// Only the next request fails.
+			if _, err := client.Secrets().Get(secret.ID); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+		})
+	}
+
+	if _, err := client.Secrets().Get(uuid.NewString()); err == nil || !isNotFound(err) {
+		t.Fatalf("expected a not found error, got: %v", err)
+	}
+}
+
+func TestStandInProviderConfigure(t *testing.T) {
+	standIn := newSecretsManagerStandIn(t)
+	apiUrl, identityUrl := standIn.urls()
+
+	// Trailing slashes of the URLs are accepted by the SDK and the API client of the provider.
+	response := configureProvider(t, standInProviderConfig(apiUrl+"/", identityUrl+"/", testAPIAccessToken))
+	if response.Diagnostics.HasError() {
+		t.Fatalf("unexpected error: %v", response.Diagnostics)
+	}
+
+	data, ok := response.ResourceData.(BitwardenSecretsManagerProviderDataStruct)
+	if !ok {
+		t.Fatalf("unexpected resource data: %T", response.ResourceData)
+	}
+	if data.organizationId != standInOrganizationID {
+		t.Fatalf("unexpected organization: %s", data.organizationId)
+	}
+
+	if _, err := data.bitwardenClient.Projects().Create(standInOrganizationID, "project"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(standIn.projects) != 1 {
+		t.Fatalf("expected the project to be created at the stand-in, got %d projects", len(standIn.projects))
+	}
+
+	apiClient, err := data.apiClient()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	identity, err := apiClient.Identity(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if identity.OrganizationID != standInOrganizationID {
+		t.Fatalf("unexpected identity: %+v", identity)
+	}
+}
+
+func TestStandInProviderConfigureErrors(t *testing.T) {
+	tests := map[string]struct {
+		accessToken string
+		fault       int
+		expected    string
+	}{
+		"invalid client": {
+			accessToken: "0.ec2c1d46-6a4b-4751-a310-af9601317f2d.wrong-secret:X8vbvA0bduihIDe/qrzIQQ==",
+			expected:    "invalid_client",
+		},
+		"identity server error": {
+			accessToken: testAPIAccessToken,
+			fault:       http.StatusInternalServerError,
+			expected:    "[500 Internal Server Error]",
+		},
+		"identity rate limit": {
+			accessToken: testAPIAccessToken,
+			fault:       http.StatusTooManyRequests,
+			expected:    "[429 Too Many Requests]",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			standIn := newSecretsManagerStandIn(t)
+			if test.fault != 0 {
+				standIn.failNext("POST /identity/connect/token", test.fault)
+			}
+			apiUrl, identityUrl := standIn.urls()
+
+			response := configureProvider(t, standInProviderConfig(apiUrl, identityUrl, test.accessToken))
+			if !response.Diagnostics.HasError() {
+				t.Fatal("expected an error")
+			}
+			diagnostic := response.Diagnostics.Errors()[0]
+			if diagnostic.Summary() != "Unable to Authenticate Bitwarden Secrets Manager Client" || !strings.Contains(diagnostic.Detail(), test.expected) {
+				t.Fatalf("expected an authentication error containing %q, got: %v", test.expected, response.Diagnostics)
+			}
+		})
+	}
+}
@@ -0,0 +1,992 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/bitwarden/sdk-go"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"sort"
+	"strings"
+)
+
+var (
+	// Ensure provider defined types fully satisfy framework interfaces.
+	_ resource.Resource                = &secretsResource{}
+	_ resource.ResourceWithConfigure   = &secretsResource{}
+	_ resource.ResourceWithImportState = &secretsResource{}
+	_ resource.ResourceWithModifyPlan  = &secretsResource{}
+)
+
+const (
+	// secretsBatchSize is the maximum number of secret IDs passed to a single GetByIDS or Delete call.
+	secretsBatchSize = 100
+
+	// unmanagedActionDelete plans the deletion of secrets added to an exclusive project outside of terraform.
+	unmanagedActionDelete = "delete"
+	// unmanagedActionError fails the plan if secrets were added to an exclusive project outside of terraform.
+	unmanagedActionError = "error"
+)
+
+// NewSecretsResource is a helper function to simplify the provider implementation.
+func NewSecretsResource() resource.Resource {
+	return &secretsResource{}
+}
+
+// secretsResource defines the resource implementation.
+type secretsResource struct {
+	bitwardenClient sdk.BitwardenClientInterface
+	organizationId  string
+	// keyNamingPolicy is nil if no key naming policy is configured.
+	keyNamingPolicy *keyNamingPolicy
+	// secretBackup is nil if deleted secrets are not backed up.
+	secretBackup *secretBackup
+}
+
+type secretsResourceModel struct {
+	ID             types.String `tfsdk:"id"`
+	ProjectID      types.String `tfsdk:"project_id"`
+	OrganizationID types.String `tfsdk:"organization_id"`
+	Secrets        types.Map    `tfsdk:"secrets"`
+	Notes          types.Map    `tfsdk:"notes"`
+	SecretIDs      types.Map    `tfsdk:"secret_ids"`
+
+	Exclusive        types.Bool   `tfsdk:"exclusive"`
+	UnmanagedAction  types.String `tfsdk:"unmanaged_action"`
+	UnmanagedSecrets types.Map    `tfsdk:"unmanaged_secrets"`
+
+	KeyNamingPolicy    types.Object `tfsdk:"key_naming_policy"`
+	DeletionProtection types.Bool   `tfsdk:"deletion_protection"`
+
+	TrimTrailingNewline  types.Bool `tfsdk:"trim_trailing_newline"`
+	NormalizeLineEndings types.Bool `tfsdk:"normalize_line_endings"`
+
+	Timeouts timeouts.Value `tfsdk:"timeouts"`
+}
+
+// managedSecret is a single secret managed by the secrets resource.
+type managedSecret struct {
+	ID    string
+	Value string
+	Note  string
+}
+
+// secretsChanges lists the keys which have to be created, updated or deleted to reconcile a project.
+type secretsChanges struct {
+	Create []string
+	Update []string
+	Delete []string
+}
+
+func (s *secretsResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_secrets"
+}
+
+func (s *secretsResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description:         "The secrets resource manages a map of secrets inside a single project of Bitwarden Secrets Manager. Compared to one secret resource per secret, it keeps the state small and refreshes all secrets with a few batched requests.",
+		MarkdownDescription: "The `secrets` resource manages a map of secrets inside a single project of Bitwarden Secrets Manager. Compared to one `secret` resource per secret, it keeps the state small and refreshes all secrets with a few batched requests.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description:         "String representation of the ID of the resource, which is the ID of the project.",
+				MarkdownDescription: "String representation of the `ID` of the resource, which is the `ID` of the project.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"project_id": schema.StringAttribute{
+				Description:         "String representation of the ID of the project which contains the secrets. Changing the project recreates all secrets.",
+				MarkdownDescription: "String representation of the `ID` of the project which contains the secrets. Changing the project recreates all secrets.",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringUUIDValidate(),
+				},
+			},
+			"organization_id": schema.StringAttribute{
+				Description:         "String representation of the ID of the organization to which the secrets belong.",
+				MarkdownDescription: "String representation of the `ID` of the organization to which the secrets belong.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"secrets": schema.MapAttribute{
+				Description:         "Map of secret keys to secret values. Secrets added to the map are created, changed values are updated and removed keys are deleted in Bitwarden Secrets Manager. Every key must satisfy the key_naming_policy of the resource or, if unset, of the provider, if configured. This attribute is sensitive. Differences in a trailing newline and CRLF line endings are not considered a change, see trim_trailing_newline and normalize_line_endings.",
+				MarkdownDescription: "Map of secret keys to secret values. Secrets added to the map are created, changed values are updated and removed keys are deleted in Bitwarden Secrets Manager. Every key must satisfy the `key_naming_policy` of the resource or, if unset, of the provider, if configured. This attribute is sensitive. Differences in a trailing newline and CRLF line endings are not considered a change, see `trim_trailing_newline` and `normalize_line_endings`.",
+				ElementType:         types.StringType,
+				Required:            true,
+				Sensitive:           true,
+				Validators: []validator.Map{
+					resourceKeyNamingPolicyValidate(),
+				},
+			},
+			"notes": schema.MapAttribute{
+				Description:         "Map of secret keys to the notes of the secrets. Every key must also be a key of secrets. Secrets without an entry have an empty note.",
+				MarkdownDescription: "Map of secret keys to the notes of the secrets. Every key must also be a key of `secrets`. Secrets without an entry have an empty note.",
+				ElementType:         types.StringType,
+				Optional:            true,
+				Validators: []validator.Map{
+					mapKeysSubsetOfValidate(path.Root("secrets")),
+				},
+			},
+			"secret_ids": schema.MapAttribute{
+				Description:         "Map of secret keys to the IDs of the secrets inside Bitwarden Secrets Manager.",
+				MarkdownDescription: "Map of secret keys to the `ID`s of the secrets inside Bitwarden Secrets Manager.",
+				ElementType:         types.StringType,
+				Computed:            true,
+			},
+			"exclusive": schema.BoolAttribute{
+				Description:         "When set to true, the resource owns the whole content of the project. Secrets added to the project outside of terraform are detected during refresh and handled according to unmanaged_action. Detection starts with the first refresh after exclusive was enabled. Since the project of a secret is only known after reading it, detection reads all secrets of the organization accessible by the machine account, except the managed ones, during every refresh. The provided default is false.",
+				MarkdownDescription: "When set to true, the resource owns the whole content of the project. Secrets added to the project outside of terraform are detected during refresh and handled according to `unmanaged_action`. Detection starts with the first refresh after `exclusive` was enabled. Since the project of a secret is only known after reading it, detection reads all secrets of the organization accessible by the machine account, except the managed ones, during every refresh. The provided default is false.",
+				Computed:            true,
+				Optional:            true,
+				Default:             booldefault.StaticBool(false),
+			},
+			"unmanaged_action": schema.StringAttribute{
+				Description:         "Configures how secrets added to an exclusive project outside of terraform are handled. With delete, their deletion is planned. With error, the plan fails listing their keys. The provided default is delete.",
+				MarkdownDescription: "Configures how secrets added to an `exclusive` project outside of terraform are handled. With `delete`, their deletion is planned. With `error`, the plan fails listing their keys. The provided default is `delete`.",
+				Computed:            true,
+				Optional:            true,
+				Default:             stringdefault.StaticString(unmanagedActionDelete),
+				Validators: []validator.String{
+					stringvalidator.OneOf(unmanagedActionDelete, unmanagedActionError),
+				},
+			},
+			"key_naming_policy": resourceKeyNamingPolicyAttribute("the keys of secrets"),
+			"deletion_protection": schema.BoolAttribute{
+				Description:         "When set to true, the secrets of the project cannot be deleted by destroying or replacing the resource. Secrets removed from secrets are still deleted. To destroy a protected resource, set deletion_protection to false and apply the configuration first. Removing deletion_protection from the configuration keeps its current value. The provided default is false.",
+				MarkdownDescription: "When set to true, the secrets of the project cannot be deleted by destroying or replacing the resource. Secrets removed from `secrets` are still deleted. To destroy a protected resource, set `deletion_protection` to false and apply the configuration first. Removing `deletion_protection` from the configuration keeps its current value. The provided default is false.",
+				Computed:            true,
+				Optional:            true,
+				Default:             booldefault.StaticBool(false),
+				PlanModifiers: []planmodifier.Bool{
+					keepStateWhenUnset(),
+				},
+			},
+			"trim_trailing_newline": schema.BoolAttribute{
+				Description:         "Whether a single trailing newline, e.g. added by file(), is ignored when comparing the configured values and notes with Bitwarden Secrets Manager. The provided default is true.",
+				MarkdownDescription: "Whether a single trailing newline, e.g. added by `file()`, is ignored when comparing the configured `secrets` and `notes` with Bitwarden Secrets Manager. The provided default is true.",
+				Computed:            true,
+				Optional:            true,
+				Default:             booldefault.StaticBool(true),
+			},
+			"normalize_line_endings": schema.BoolAttribute{
+				Description:         "Whether CRLF line endings are treated as LF when comparing the configured values and notes with Bitwarden Secrets Manager. The provided default is true.",
+				MarkdownDescription: "Whether CRLF line endings are treated as LF when comparing the configured `secrets` and `notes` with Bitwarden Secrets Manager. The provided default is true.",
+				Computed:            true,
+				Optional:            true,
+				Default:             booldefault.StaticBool(true),
+			},
+			"unmanaged_secrets": schema.MapAttribute{
+				Description:         "Map of the IDs to the keys of the secrets inside an exclusive project which are not managed by this resource. Null if exclusive is false.",
+				MarkdownDescription: "Map of the `ID`s to the keys of the secrets inside an `exclusive` project which are not managed by this resource. Null if `exclusive` is false.",
+				ElementType:         types.StringType,
+				Computed:            true,
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Update: true,
+				Delete: true,
+			}),
+		},
+	}
+}
+
+func (s *secretsResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Add a nil check when handling BitwardenSecretsManagerProviderDataStruct because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	tflog.Info(ctx, "Configuring Secrets Resource")
+	if req.ProviderData == nil {
+		tflog.Debug(ctx, "Skipping Resource Configuration because Provider has not been configured yet.")
+		return
+	}
+
+	providerDataStruct, ok := req.ProviderData.(BitwardenSecretsManagerProviderDataStruct)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected sdk.BitwardenClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	client := providerDataStruct.bitwardenClient
+	organizationId := providerDataStruct.organizationId
+
+	if client == nil {
+		resp.Diagnostics.AddError(
+			"Client Not Initialized",
+			"The Bitwarden client was not properly initialized due to a missing Bitwarden API Client.",
+		)
+		return
+	}
+
+	if organizationId == "" {
+		resp.Diagnostics.AddError(
+			"Client Not Initialized",
+			"The Bitwarden client was not properly initialized due to an empty Organization ID.",
+		)
+		return
+	}
+
+	s.bitwardenClient = client
+	s.organizationId = organizationId
+	s.keyNamingPolicy = providerDataStruct.keyNamingPolicy
+	s.secretBackup = providerDataStruct.secretBackup
+
+	tflog.Info(ctx, "Resource Configured")
+}
+
+func (s *secretsResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	// Retrieve values from plan
+	var plan secretsResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if s.bitwardenClient == nil {
+		resp.Diagnostics.AddError(
+			"Client Not Initialized",
+			"The Bitwarden client was not properly initialized.",
+		)
+		return
+	}
+
+	ctx, client, done := withClientTimeout(ctx, s.bitwardenClient, plan.Timeouts.Create, "create", "the secrets", &resp.Diagnostics)
+	defer done()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	planned, diags := managedSecretsFromModel(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	state := plan
+	state.ID = plan.ProjectID
+	state.OrganizationID = types.StringValue(s.organizationId)
+
+	managed := map[string]managedSecret{}
+	changes := diffSecrets(planned, managed, secretNormalization(plan.TrimTrailingNewline, plan.NormalizeLineEndings))
+	s.applySecretsChanges(ctx, client, &plan, changes, planned, managed, &resp.Diagnostics)
+
+	// Secrets created before an error are stored as well, so they are not orphaned.
+	diags = setSecretsState(&state, managed, plan.Secrets, plan.Notes)
+	resp.Diagnostics.Append(diags...)
+
+	if !resp.Diagnostics.HasError() {
+		state.UnmanagedSecrets, diags = s.unmanagedSecrets(client, &state, managed)
+		resp.Diagnostics.Append(diags...)
+	} else {
+		state.UnmanagedSecrets = types.MapNull(types.StringType)
+	}
+
+	diags = resp.State.Set(ctx, state)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (s *secretsResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	tflog.Info(ctx, "Reading Secrets Resource")
+
+	var state secretsResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if s.bitwardenClient == nil {
+		resp.Diagnostics.AddError(
+			"Client Not Initialized",
+			"The Bitwarden client was not properly initialized.",
+		)
+		return
+	}
+
+	ctx, client, done := withClientTimeout(ctx, s.bitwardenClient, state.Timeouts.Read, "read", "the secrets", &resp.Diagnostics)
+	defer done()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var secrets []sdk.SecretResponse
+	var err error
+	if state.SecretIDs.IsNull() {
+		// Imported resources adopt all secrets of the project.
+		secrets, err = listProjectSecrets(client, s.organizationId, state.ProjectID.ValueString(), nil)
+	} else {
+		var ids map[string]string
+		diags = state.SecretIDs.ElementsAs(ctx, &ids, false)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		secrets, err = getExistingSecrets(client, s.organizationId, mapValues(ids))
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read Secrets of project with id: "+state.ProjectID.ValueString(),
+			err.Error(),
+		)
+		return
+	}
+
+	managed := map[string]managedSecret{}
+	for _, secret := range secrets {
+		if existing, ok := managed[secret.Key]; ok {
+			tflog.Warn(ctx, "Project contains multiple secrets with the same key, only the first one is managed", map[string]any{
+				"key":        secret.Key,
+				"managed_id": existing.ID,
+				"ignored_id": secret.ID,
+			})
+			continue
+		}
+		managed[secret.Key] = managedSecret{ID: secret.ID, Value: secret.Value, Note: secret.Note}
+	}
+
+	state.ID = state.ProjectID
+	state.OrganizationID = types.StringValue(s.organizationId)
+	// Imported resources start with the schema defaults.
+	if state.Exclusive.IsNull() {
+		state.Exclusive = types.BoolValue(false)
+	}
+	if state.UnmanagedAction.IsNull() {
+		state.UnmanagedAction = types.StringValue(unmanagedActionDelete)
+	}
+	if state.DeletionProtection.IsNull() {
+		state.DeletionProtection = types.BoolValue(false)
+	}
+	if state.TrimTrailingNewline.IsNull() {
+		state.TrimTrailingNewline = types.BoolValue(true)
+	}
+	if state.NormalizeLineEndings.IsNull() {
+		state.NormalizeLineEndings = types.BoolValue(true)
+	}
+	diags = setSecretsState(&state, managed, state.Secrets, state.Notes)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	state.UnmanagedSecrets, diags = s.unmanagedSecrets(client, &state, managed)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if len(state.UnmanagedSecrets.Elements()) > 0 {
+		tflog.Warn(ctx, "Exclusive project contains secrets which are not managed by terraform", map[string]any{
+			"project_id": state.ProjectID.ValueString(),
+			"count":      len(state.UnmanagedSecrets.Elements()),
+		})
+	}
+
+	// Set state
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (s *secretsResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// Retrieve values from plan
+	var plan secretsResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var state secretsResourceModel
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if s.bitwardenClient == nil {
+		resp.Diagnostics.AddError(
+			"Client Not Initialized",
+			"The Bitwarden client was not properly initialized.",
+		)
+		return
+	}
+
+	ctx, client, done := withClientTimeout(ctx, s.bitwardenClient, plan.Timeouts.Update, "update", "the secrets", &resp.Diagnostics)
+	defer done()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	planned, diags := managedSecretsFromModel(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	managed, diags := managedSecretsFromModel(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Unmanaged secrets of exclusive projects are deleted first, so their keys can be reused.
+	if !plan.UnmanagedSecrets.IsUnknown() && !plan.UnmanagedSecrets.IsNull() && len(plan.UnmanagedSecrets.Elements()) == 0 &&
+		!state.UnmanagedSecrets.IsNull() && len(state.UnmanagedSecrets.Elements()) > 0 {
+		var unmanaged map[string]string
+		diags = state.UnmanagedSecrets.ElementsAs(ctx, &unmanaged, false)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		if err := deleteSecrets(client, s.secretBackup, sortedKeys(unmanaged)); err != nil {
+			addDeleteDiagnostics(&resp.Diagnostics, "Unable to Delete Unmanaged Secrets", err)
+			return
+		}
+		tflog.Info(ctx, "Deleted Unmanaged Secrets", map[string]any{"count": len(unmanaged)})
+	}
+
+	changes := diffSecrets(planned, managed, secretNormalization(plan.TrimTrailingNewline, plan.NormalizeLineEndings))
+	s.applySecretsChanges(ctx, client, &plan, changes, planned, managed, &resp.Diagnostics)
+
+	// The state reflects all changes applied before an error.
+	state.OrganizationID = types.StringValue(s.organizationId)
+	state.Exclusive = plan.Exclusive
+	state.UnmanagedAction = plan.UnmanagedAction
+	state.KeyNamingPolicy = plan.KeyNamingPolicy
+	state.DeletionProtection = plan.DeletionProtection
+	state.TrimTrailingNewline = plan.TrimTrailingNewline
+	state.NormalizeLineEndings = plan.NormalizeLineEndings
+	state.Timeouts = plan.Timeouts
+	diags = setSecretsState(&state, managed, plan.Secrets, plan.Notes)
+	resp.Diagnostics.Append(diags...)
+
+	// Known unmanaged secrets were planned from the refreshed state, only newly exclusive projects are listed here.
+	state.UnmanagedSecrets = plan.UnmanagedSecrets
+	if plan.UnmanagedSecrets.IsUnknown() {
+		if resp.Diagnostics.HasError() {
+			state.UnmanagedSecrets = types.MapNull(types.StringType)
+		} else {
+			state.UnmanagedSecrets, diags = s.unmanagedSecrets(client, &state, managed)
+			resp.Diagnostics.Append(diags...)
+		}
+	}
+
+	diags = resp.State.Set(ctx, state)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (s *secretsResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state secretsResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if s.bitwardenClient == nil {
+		resp.Diagnostics.AddError(
+			"Client Not Initialized",
+			"The Bitwarden client was not properly initialized.",
+		)
+		return
+	}
+
+	ctx, client, done := withClientTimeout(ctx, s.bitwardenClient, state.Timeouts.Delete, "delete", "the secrets", &resp.Diagnostics)
+	defer done()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var ids map[string]string
+	diags = state.SecretIDs.ElementsAs(ctx, &ids, false)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if state.DeletionProtection.ValueBool() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("deletion_protection"),
+			"Deletion Protection Enabled",
+			fmt.Sprintf("The secrets of the project %s are protected against deletion. ", state.ProjectID.ValueString())+
+				"Set deletion_protection to false and apply the configuration before destroying or replacing the resource.",
+		)
+		return
+	}
+
+	if err := deleteSecrets(client, s.secretBackup, mapValues(ids)); err != nil {
+		addDeleteDiagnostics(&resp.Diagnostics, "Unable to Delete Secrets", err)
+	}
+}
+
+func (s *secretsResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Destroyed resources have no plan.
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan secretsResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	policy, diags := effectiveKeyNamingPolicy(ctx, plan.KeyNamingPolicy, s.keyNamingPolicy)
+	resp.Diagnostics.Append(diags...)
+	for _, key := range sortedKeys(plan.Secrets.Elements()) {
+		resp.Diagnostics.Append(checkKeyNamingPolicy(ctx, policy, path.Root("secrets").AtMapKey(key), types.StringValue(key))...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The IDs and unmanaged secrets of new resources are unknown until they are created.
+	if req.State.Raw.IsNull() {
+		if !plan.Exclusive.IsUnknown() && !plan.Exclusive.ValueBool() {
+			diags = resp.Plan.SetAttribute(ctx, path.Root("unmanaged_secrets"), types.MapNull(types.StringType))
+			resp.Diagnostics.Append(diags...)
+		}
+		return
+	}
+
+	var state secretsResourceModel
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	secretIDs, diags := plannedSecretIDs(ctx, plan.Secrets, state.SecretIDs)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.Plan.SetAttribute(ctx, path.Root("secret_ids"), secretIDs)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	unmanagedSecrets, diags := plannedUnmanagedSecrets(ctx, &plan, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.Plan.SetAttribute(ctx, path.Root("unmanaged_secrets"), unmanagedSecrets)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (s *secretsResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// The import ID is the ID of the project, all of its secrets are managed afterward.
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("project_id"), req.ID)...)
+}
+
+// applySecretsChanges deletes, updates and creates secrets and records every successful change in managed.
+// It stops at the first error, so managed always reflects the content of Bitwarden Secrets Manager.
+func (s *secretsResource) applySecretsChanges(ctx context.Context, bitwardenClient sdk.BitwardenClientInterface, plan *secretsResourceModel, changes secretsChanges, planned map[string]managedSecret, managed map[string]managedSecret, diags *diag.Diagnostics) {
+	projectIDs := []string{plan.ProjectID.ValueString()}
+
+	if len(changes.Delete) > 0 {
+		ids := make([]string, 0, len(changes.Delete))
+		for _, key := range changes.Delete {
+			ids = append(ids, managed[key].ID)
+		}
+		err := deleteSecrets(bitwardenClient, s.secretBackup, ids)
+		failed, known := failedSecretIDs(err)
+		if known {
+			for _, key := range changes.Delete {
+				if !failed[managed[key].ID] {
+					delete(managed, key)
+				}
+			}
+		}
+		if err != nil {
+			addDeleteDiagnostics(diags, "Unable to Delete Secrets", err)
+			return
+		}
+	}
+
+	for _, key := range changes.Update {
+		secret, err := bitwardenClient.Secrets().Update(
+			managed[key].ID,
+			key,
+			planned[key].Value,
+			planned[key].Note,
+			s.organizationId,
+			projectIDs,
+		)
+		if err != nil {
+			diags.AddError(
+				"Unable to Update Secret with key: "+key,
+				err.Error(),
+			)
+			return
+		}
+		managed[key] = managedSecret{ID: secret.ID, Value: secret.Value, Note: secret.Note}
+	}
+
+	for _, key := range changes.Create {
+		secret, err := bitwardenClient.Secrets().Create(
+			key,
+			planned[key].Value,
+			planned[key].Note,
+			s.organizationId,
+			projectIDs,
+		)
+		if err != nil {
+			diags.AddError(
+				"Unable to Create Secret with key: "+key,
+				err.Error(),
+			)
+			return
+		}
+		managed[key] = managedSecret{ID: secret.ID, Value: secret.Value, Note: secret.Note}
+	}
+
+	tflog.Info(ctx, "Applied Secrets Changes", map[string]any{
+		"created": len(changes.Create),
+		"updated": len(changes.Update),
+		"deleted": len(changes.Delete),
+	})
+}
+
+// unmanagedSecrets returns the IDs and keys of all secrets inside an exclusive project which are not managed by the
+// resource. Non-exclusive resources return null without requesting the project content.
+func (s *secretsResource) unmanagedSecrets(bitwardenClient sdk.BitwardenClientInterface, model *secretsResourceModel, managed map[string]managedSecret) (types.Map, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	if !model.Exclusive.ValueBool() {
+		return types.MapNull(types.StringType), diags
+	}
+
+	// The managed secrets were just read, only the other secrets of the organization are fetched.
+	managedIDs := make(map[string]bool, len(managed))
+	for _, secret := range managed {
+		managedIDs[secret.ID] = true
+	}
+	projectSecrets, err := listProjectSecrets(bitwardenClient, s.organizationId, model.ProjectID.ValueString(), managedIDs)
+	if err != nil {
+		diags.AddError(
+			"Unable to Read Secrets of project with id: "+model.ProjectID.ValueString(),
+			err.Error(),
+		)
+		return types.MapNull(types.StringType), diags
+	}
+
+	return findUnmanagedSecrets(projectSecrets, managed), diags
+}
+
+// findUnmanagedSecrets returns the IDs and keys of all project secrets which are not part of managed.
+func findUnmanagedSecrets(projectSecrets []sdk.SecretResponse, managed map[string]managedSecret) types.Map {
+	managedIDs := make(map[string]bool, len(managed))
+	for _, secret := range managed {
+		managedIDs[secret.ID] = true
+	}
+
+	unmanaged := map[string]attr.Value{}
+	for _, secret := range projectSecrets {
+		if !managedIDs[secret.ID] {
+			unmanaged[secret.ID] = types.StringValue(secret.Key)
+		}
+	}
+
+	return types.MapValueMust(types.StringType, unmanaged)
+}
+
+// plannedUnmanagedSecrets plans the unmanaged secrets of an exclusive project. Depending on unmanaged_action, their
+// deletion is planned or the plan fails. Resources which just became exclusive detect unmanaged secrets during apply.
+func plannedUnmanagedSecrets(ctx context.Context, plan *secretsResourceModel, state *secretsResourceModel) (types.Map, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	if plan.Exclusive.IsUnknown() || plan.UnmanagedAction.IsUnknown() {
+		return types.MapUnknown(types.StringType), diags
+	}
+	if !plan.Exclusive.ValueBool() {
+		return types.MapNull(types.StringType), diags
+	}
+	if !state.Exclusive.ValueBool() || state.UnmanagedSecrets.IsNull() {
+		return types.MapUnknown(types.StringType), diags
+	}
+
+	var unmanaged map[string]string
+	diags.Append(state.UnmanagedSecrets.ElementsAs(ctx, &unmanaged, false)...)
+	if diags.HasError() || len(unmanaged) == 0 {
+		return state.UnmanagedSecrets, diags
+	}
+
+	if plan.UnmanagedAction.ValueString() == unmanagedActionError {
+		keys := make([]string, 0, len(unmanaged))
+		for _, id := range sortedKeys(unmanaged) {
+			keys = append(keys, fmt.Sprintf("%s (id: %s)", unmanaged[id], id))
+		}
+		diags.AddAttributeError(
+			path.Root("unmanaged_secrets"),
+			"Unmanaged Secrets in Exclusive Project",
+			fmt.Sprintf("The project with id %s contains secrets which are not managed by terraform:\n%s\n\n"+
+				"Add them to secrets, remove them from the project or set unmanaged_action to \"delete\".",
+				plan.ProjectID.ValueString(), strings.Join(keys, "\n")),
+		)
+		return state.UnmanagedSecrets, diags
+	}
+
+	return types.MapValueMust(types.StringType, map[string]attr.Value{}), diags
+}
+
+// managedSecretsFromModel returns the secrets of a plan or state keyed by their secret key.
+func managedSecretsFromModel(ctx context.Context, model *secretsResourceModel) (map[string]managedSecret, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	values := map[string]string{}
+	if !model.Secrets.IsNull() {
+		diags.Append(model.Secrets.ElementsAs(ctx, &values, false)...)
+	}
+	notes := map[string]string{}
+	if !model.Notes.IsNull() {
+		diags.Append(model.Notes.ElementsAs(ctx, &notes, false)...)
+	}
+	ids := map[string]string{}
+	if !model.SecretIDs.IsNull() && !model.SecretIDs.IsUnknown() {
+		diags.Append(model.SecretIDs.ElementsAs(ctx, &ids, false)...)
+	}
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	secrets := make(map[string]managedSecret, len(values))
+	for key, value := range values {
+		secrets[key] = managedSecret{ID: ids[key], Value: value, Note: notes[key]}
+	}
+
+	return secrets, diags
+}
+
+// diffSecrets compares the planned secrets with the managed secrets, ignoring the differences of normalization. The
+// returned keys are sorted to apply changes in a stable order.
+func diffSecrets(planned map[string]managedSecret, managed map[string]managedSecret, normalization stringNormalization) secretsChanges {
+	var changes secretsChanges
+
+	for _, key := range sortedKeys(planned) {
+		current, ok := managed[key]
+		if !ok {
+			changes.Create = append(changes.Create, key)
+			continue
+		}
+		if normalization.apply(current.Value) != normalization.apply(planned[key].Value) ||
+			normalization.apply(current.Note) != normalization.apply(planned[key].Note) {
+			changes.Update = append(changes.Update, key)
+		}
+	}
+
+	for _, key := range sortedKeys(managed) {
+		if _, ok := planned[key]; !ok {
+			changes.Delete = append(changes.Delete, key)
+		}
+	}
+
+	return changes
+}
+
+// setSecretsState stores the managed secrets in the model. Notes are stored for secrets with a note and for keys
+// which had a note entry before, so that unset notes stay unset. Prior values and notes which only differ in the
+// differences ignored by the normalisation configured by the model are kept.
+func setSecretsState(model *secretsResourceModel, managed map[string]managedSecret, priorSecrets types.Map, priorNotes types.Map) diag.Diagnostics {
+	var diags diag.Diagnostics
+	normalization := secretNormalization(model.TrimTrailingNewline, model.NormalizeLineEndings)
+
+	values := make(map[string]attr.Value, len(managed))
+	notes := map[string]attr.Value{}
+	ids := make(map[string]attr.Value, len(managed))
+
+	priorSecretElements := priorSecrets.Elements()
+	priorNoteElements := priorNotes.Elements()
+	for key, secret := range managed {
+		values[key] = normalization.normalizedValue(mapStringElement(priorSecretElements, key), secret.Value)
+		ids[key] = types.StringValue(secret.ID)
+		if _, ok := priorNoteElements[key]; ok || secret.Note != "" {
+			notes[key] = normalization.normalizedValue(mapStringElement(priorNoteElements, key), secret.Note)
+		}
+	}
+
+	secrets, valueDiags := types.MapValue(types.StringType, values)
+	diags.Append(valueDiags...)
+	secretIDs, valueDiags := types.MapValue(types.StringType, ids)
+	diags.Append(valueDiags...)
+
+	model.Secrets = secrets
+	model.SecretIDs = secretIDs
+	if len(notes) == 0 && priorNotes.IsNull() {
+		model.Notes = types.MapNull(types.StringType)
+	} else {
+		noteValues, valueDiags := types.MapValue(types.StringType, notes)
+		diags.Append(valueDiags...)
+		model.Notes = noteValues
+	}
+
+	return diags
+}
+
+// mapStringElement returns the string element of a map with the given key, or null if the map has no such element.
+func mapStringElement(elements map[string]attr.Value, key string) types.String {
+	if element, ok := elements[key].(types.String); ok {
+		return element
+	}
+	return types.StringNull()
+}
+
+// plannedSecretIDs keeps the IDs of existing secrets and plans unknown IDs for secrets which will be created.
+func plannedSecretIDs(ctx context.Context, secrets types.Map, stateIDs types.Map) (types.Map, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	if secrets.IsUnknown() || stateIDs.IsNull() {
+		return types.MapUnknown(types.StringType), diags
+	}
+
+	var ids map[string]string
+	diags.Append(stateIDs.ElementsAs(ctx, &ids, false)...)
+	if diags.HasError() {
+		return types.MapUnknown(types.StringType), diags
+	}
+
+	planned := make(map[string]attr.Value, len(secrets.Elements()))
+	for key := range secrets.Elements() {
+		if id, ok := ids[key]; ok {
+			planned[key] = types.StringValue(id)
+		} else {
+			planned[key] = types.StringUnknown()
+		}
+	}
+
+	plannedIDs, valueDiags := types.MapValue(types.StringType, planned)
+	diags.Append(valueDiags...)
+	return plannedIDs, diags
+}
+
+// getExistingSecrets fetches the secrets with the given IDs. Secrets which were deleted outside of terraform are
+// skipped, since GetByIDS fails if any of the requested secrets does not exist.
+func getExistingSecrets(bitwardenClient sdk.BitwardenClientInterface, organizationId string, ids []string) ([]sdk.SecretResponse, error) {
+	if len(ids) == 0 {
+		return []sdk.SecretResponse{}, nil
+	}
+
+	identifiers, err := bitwardenClient.Secrets().List(organizationId)
+	if err != nil {
+		return nil, err
+	}
+
+	existing := make(map[string]bool, len(identifiers.Data))
+	for _, identifier := range identifiers.Data {
+		existing[identifier.ID] = true
+	}
+
+	var existingIDs []string
+	for _, id := range ids {
+		if existing[id] {
+			existingIDs = append(existingIDs, id)
+		}
+	}
+
+	return getSecretsByIDs(bitwardenClient, existingIDs)
+}
+
+// listProjectSecrets fetches all secrets of the given project accessible by the used machine account, except the
+// secrets in skipIDs. The identifiers returned by List do not contain the project of a secret and the SDK has no
+// project filter, so the project of every secret of the organization can only be determined by fetching the secret
+// itself. This costs one List call and one GetByIDS call per secretsBatchSize secrets of the organization. Secrets
+// whose project is already known, like the secrets managed by the resource, should be skipped to save requests.
+func listProjectSecrets(bitwardenClient sdk.BitwardenClientInterface, organizationId string, projectId string, skipIDs map[string]bool) ([]sdk.SecretResponse, error) {
+	identifiers, err := bitwardenClient.Secrets().List(organizationId)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(identifiers.Data))
+	for _, identifier := range identifiers.Data {
+		if !skipIDs[identifier.ID] {
+			ids = append(ids, identifier.ID)
+		}
+	}
+
+	secrets, err := getSecretsByIDs(bitwardenClient, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	projectSecrets := []sdk.SecretResponse{}
+	for _, secret := range secrets {
+		if secret.ProjectID != nil && *secret.ProjectID == projectId {
+			projectSecrets = append(projectSecrets, secret)
+		}
+	}
+
+	return projectSecrets, nil
+}
+
+// getSecretsByIDs fetches the given secrets in batches of secretsBatchSize.
+func getSecretsByIDs(bitwardenClient sdk.BitwardenClientInterface, ids []string) ([]sdk.SecretResponse, error) {
+	secrets := make([]sdk.SecretResponse, 0, len(ids))
+	for _, batch := range batchIDs(ids, secretsBatchSize) {
+		response, err := bitwardenClient.Secrets().GetByIDS(batch)
+		if err != nil {
+			return nil, err
+		}
+		secrets = append(secrets, response.Data...)
+	}
+	return secrets, nil
+}
+
+// batchIDs splits ids into consecutive batches of at most size IDs.
+func batchIDs(ids []string, size int) [][]string {
+	var batches [][]string
+	for start := 0; start < len(ids); start += size {
+		end := start + size
+		if end > len(ids) {
+			end = len(ids)
+		}
+		batches = append(batches, ids[start:end])
+	}
+	return batches
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func mapValues(m map[string]string) []string {
+	values := make([]string, 0, len(m))
+	for _, key := range sortedKeys(m) {
+		values = append(values, m[key])
+	}
+	return values
+}
@@ -0,0 +1,511 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/bitwarden/sdk-go"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+func buildSecretsResourceConfig(projectId string, secrets map[string]string, notes map[string]string) string {
+	return buildExclusiveSecretsResourceConfig(projectId, secrets, notes, "")
+}
+
+// buildExclusiveSecretsResourceConfig renders an exclusive secrets resource if unmanagedAction is not empty.
+func buildExclusiveSecretsResourceConfig(projectId string, secrets map[string]string, notes map[string]string, unmanagedAction string) string {
+	configString := fmt.Sprintf(`
+
+	resource "bitwarden-sm_secrets" "test" {
+		project_id = "%s"
+		secrets = {`, projectId)
+	for _, key := range sortedKeys(secrets) {
+		configString += fmt.Sprintf(`
+			%q = %q`, key, secrets[key])
+	}
+	configString += `
+		}`
+	if len(notes) > 0 {
+		configString += `
+		notes = {`
+		for _, key := range sortedKeys(notes) {
+			configString += fmt.Sprintf(`
+			%q = %q`, key, notes[key])
+		}
+		configString += `
+		}`
+	}
+	if unmanagedAction != "" {
+		configString += fmt.Sprintf(`
+		exclusive        = true
+		unmanaged_action = "%s"`, unmanagedAction)
+	}
+	configString += `
+	}`
+	return configString
+}
+
+// This acceptance test validates that the secrets resource creates, updates and deletes the secrets of a project
+// when the map of secrets changes.
+func TestAccResourceSecretsCreateUpdateDelete(t *testing.T) {
+	projectName := "Test-Project-" + generateRandomString()
+
+	bitwardenClient, organizationId, err := newBitwardenClient()
+	if err != nil {
+		t.Fatalf("Error creating bitwardenClient: %s", err)
+	}
+
+	project, preCheckError := bitwardenClient.Projects().Create(organizationId, projectName)
+	if preCheckError != nil {
+		t.Fatal("Error creating test project for provider validation.")
+	}
+
+	secrets := map[string]string{
+		"DATABASE_URL": generateRandomString(),
+		"API_TOKEN":    generateRandomString(),
+		"LOG_LEVEL":    "debug",
+	}
+	notes := map[string]string{
+		"API_TOKEN": "Rotated quarterly",
+	}
+
+	updatedSecrets := map[string]string{
+		"DATABASE_URL": generateRandomString(),
+		"LOG_LEVEL":    "debug",
+		"NEW_FEATURE":  "enabled",
+	}
+
+	runProviderTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: buildProviderConfigFromEnvFile(t) +
+					buildSecretsResourceConfig(project.ID, secrets, notes),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("bitwarden-sm_secrets.test", "id", project.ID),
+					resource.TestCheckResourceAttr("bitwarden-sm_secrets.test", "organization_id", organizationId),
+					resource.TestCheckResourceAttr("bitwarden-sm_secrets.test", "secrets.%", "3"),
+					resource.TestCheckResourceAttr("bitwarden-sm_secrets.test", "secrets.API_TOKEN", secrets["API_TOKEN"]),
+					resource.TestCheckResourceAttr("bitwarden-sm_secrets.test", "notes.API_TOKEN", "Rotated quarterly"),
+					resource.TestCheckResourceAttr("bitwarden-sm_secrets.test", "secret_ids.%", "3"),
+					resource.TestCheckResourceAttrSet("bitwarden-sm_secrets.test", "secret_ids.DATABASE_URL"),
+				),
+			},
+			{
+				Config: buildProviderConfigFromEnvFile(t) +
+					buildSecretsResourceConfig(project.ID, updatedSecrets, nil),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("bitwarden-sm_secrets.test", "secrets.%", "3"),
+					resource.TestCheckResourceAttr("bitwarden-sm_secrets.test", "secrets.DATABASE_URL", updatedSecrets["DATABASE_URL"]),
+					resource.TestCheckResourceAttr("bitwarden-sm_secrets.test", "secrets.NEW_FEATURE", "enabled"),
+					resource.TestCheckNoResourceAttr("bitwarden-sm_secrets.test", "secrets.API_TOKEN"),
+					resource.TestCheckNoResourceAttr("bitwarden-sm_secrets.test", "secret_ids.API_TOKEN"),
+					func(s *terraform.State) error {
+						rs := s.RootModule().Resources["bitwarden-sm_secrets.test"]
+						secret, getErr := bitwardenClient.Secrets().Get(rs.Primary.Attributes["secret_ids.NEW_FEATURE"])
+						if getErr != nil {
+							return fmt.Errorf("unable to Read Secret: %s", getErr.Error())
+						}
+						if secret.ProjectID == nil || *secret.ProjectID != project.ID {
+							return fmt.Errorf("expected secret to belong to project %s", project.ID)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				ResourceName:            "bitwarden-sm_secrets.test",
+				ImportState:             true,
+				ImportStateId:           project.ID,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"notes"},
+			},
+		},
+		CheckDestroy: func(state *terraform.State) error {
+			_, cleanUpErr := bitwardenClient.Projects().Delete([]string{project.ID})
+			if cleanUpErr != nil {
+				t.Fatalf("Error cleaning up test project: %s", cleanUpErr.Error())
+			}
+			return nil
+		},
+	})
+}
+
+func TestAccResourceSecretsNoteForUnknownKeyExpectError(t *testing.T) {
+	runProviderTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: buildProviderConfigFromEnvFile(t) +
+					buildSecretsResourceConfig(validProjectUUID, map[string]string{"KEY": "value"}, map[string]string{"OTHER": "note"}),
+				ExpectError: regexp.MustCompile("map key not found"),
+			},
+		},
+	})
+}
+
+// This acceptance test validates that secrets added to an exclusive project outside terraform fail the plan or are
+// deleted depending on unmanaged_action.
+func TestAccResourceSecretsExclusive(t *testing.T) {
+	projectName := "Test-Project-" + generateRandomString()
+
+	bitwardenClient, organizationId, err := newBitwardenClient()
+	if err != nil {
+		t.Fatalf("Error creating bitwardenClient: %s", err)
+	}
+
+	project, preCheckError := bitwardenClient.Projects().Create(organizationId, projectName)
+	if preCheckError != nil {
+		t.Fatal("Error creating test project for provider validation.")
+	}
+
+	secrets := map[string]string{
+		"DATABASE_URL": generateRandomString(),
+	}
+	var unmanagedSecretID string
+
+	runProviderTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: buildProviderConfigFromEnvFile(t) +
+					buildExclusiveSecretsResourceConfig(project.ID, secrets, nil, unmanagedActionError),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("bitwarden-sm_secrets.test", "exclusive", "true"),
+					resource.TestCheckResourceAttr("bitwarden-sm_secrets.test", "unmanaged_secrets.%", "0"),
+					// Add a secret to the project outside terraform.
+					func(s *terraform.State) error {
+						secret, createErr := bitwardenClient.Secrets().Create("Test-Secret-"+generateRandomString(), "value", "", organizationId, []string{project.ID})
+						if createErr != nil {
+							return fmt.Errorf("unable to Create Secret: %s", createErr.Error())
+						}
+						unmanagedSecretID = secret.ID
+						return nil
+					},
+				),
+			},
+			{
+				Config: buildProviderConfigFromEnvFile(t) +
+					buildExclusiveSecretsResourceConfig(project.ID, secrets, nil, unmanagedActionError),
+				ExpectError: regexp.MustCompile("Unmanaged Secrets in Exclusive Project"),
+			},
+			{
+				Config: buildProviderConfigFromEnvFile(t) +
+					buildExclusiveSecretsResourceConfig(project.ID, secrets, nil, unmanagedActionDelete),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("bitwarden-sm_secrets.test", "unmanaged_secrets.%", "0"),
+					func(s *terraform.State) error {
+						if _, getErr := bitwardenClient.Secrets().Get(unmanagedSecretID); getErr == nil {
+							return fmt.Errorf("expected unmanaged secret %s to be deleted", unmanagedSecretID)
+						}
+						return nil
+					},
+				),
+			},
+		},
+		CheckDestroy: func(state *terraform.State) error {
+			_, cleanUpErr := bitwardenClient.Projects().Delete([]string{project.ID})
+			if cleanUpErr != nil {
+				t.Fatalf("Error cleaning up test project: %s", cleanUpErr.Error())
+			}
+			return nil
+		},
+	})
+}
+
+func TestDiffSecrets(t *testing.T) {
+	managed := map[string]managedSecret{
+		"KEEP":     {ID: "1", Value: "value\n", Note: "note"},
+		"UPDATE":   {ID: "2", Value: "old", Note: ""},
+		"NOTE":     {ID: "3", Value: "value", Note: "old"},
+		"DELETE_A": {ID: "4", Value: "value"},
+		"DELETE_B": {ID: "5", Value: "value"},
+	}
+	planned := map[string]managedSecret{
+		"KEEP":   {Value: "value", Note: "note\r\n"},
+		"UPDATE": {Value: "new"},
+		"NOTE":   {Value: "value", Note: "new"},
+		"CREATE": {Value: "value"},
+	}
+
+	changes := diffSecrets(planned, managed, secretStringNormalization)
+
+	expected := secretsChanges{
+		Create: []string{"CREATE"},
+		Update: []string{"NOTE", "UPDATE"},
+		Delete: []string{"DELETE_A", "DELETE_B"},
+	}
+	if !reflect.DeepEqual(changes, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, changes)
+	}
+
+	// Without normalisation, the trailing newline and the CRLF line ending of KEEP are changes.
+	changes = diffSecrets(planned, managed, stringNormalization{})
+	expected.Update = []string{"KEEP", "NOTE", "UPDATE"}
+	if !reflect.DeepEqual(changes, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, changes)
+	}
+}
+
+func TestListProjectSecrets(t *testing.T) {
+	store := newFakeBitwardenStore(fakeOrganizationID)
+	client := newFaultInjectingClient(store.newClient())
+	if err := client.AccessTokenLogin(fakeAccessToken, nil); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	project, err := client.Projects().Create(fakeOrganizationID, "project")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	other, err := client.Projects().Create(fakeOrganizationID, "other")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	managedIDs := map[string]bool{}
+	for i := 0; i < secretsBatchSize; i++ {
+		secret, err := client.Secrets().Create(fmt.Sprintf("MANAGED_%d", i), "value", "", fakeOrganizationID, []string{project.ID})
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		managedIDs[secret.ID] = true
+	}
+	unmanaged, err := client.Secrets().Create("UNMANAGED", "value", "", fakeOrganizationID, []string{project.ID})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, err := client.Secrets().Create("OTHER", "value", "", fakeOrganizationID, []string{other.ID}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	secrets, err := listProjectSecrets(client, fakeOrganizationID, project.ID, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(secrets) != secretsBatchSize+1 {
+		t.Fatalf("expected %d secrets, got %d", secretsBatchSize+1, len(secrets))
+	}
+	if calls := client.callCount("Secrets.GetByIDS"); calls != 2 {
+		t.Fatalf("expected the secrets to be fetched in 2 batches, got %d calls", calls)
+	}
+
+	// Skipped secrets are not fetched.
+	secrets, err = listProjectSecrets(client, fakeOrganizationID, project.ID, managedIDs)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(secrets) != 1 || secrets[0].ID != unmanaged.ID {
+		t.Fatalf("expected only the unmanaged secret, got %v", secrets)
+	}
+	if calls := client.callCount("Secrets.GetByIDS"); calls != 3 {
+		t.Fatalf("expected a single batch for the other secrets, got %d calls", calls-2)
+	}
+}
+
+func TestBatchIDs(t *testing.T) {
+	ids := []string{"1", "2", "3", "4", "5"}
+
+	batches := batchIDs(ids, 2)
+
+	expected := [][]string{{"1", "2"}, {"3", "4"}, {"5"}}
+	if !reflect.DeepEqual(batches, expected) {
+		t.Fatalf("expected %v, got %v", expected, batches)
+	}
+	if batches := batchIDs(nil, 2); len(batches) != 0 {
+		t.Fatalf("expected no batches for no IDs, got %v", batches)
+	}
+}
+
+func TestPlannedSecretIDs(t *testing.T) {
+	ctx := context.Background()
+	secrets := types.MapValueMust(types.StringType, map[string]attr.Value{
+		"EXISTING": types.StringValue("value"),
+		"NEW":      types.StringValue("value"),
+	})
+	stateIDs := types.MapValueMust(types.StringType, map[string]attr.Value{
+		"EXISTING": types.StringValue("1"),
+		"REMOVED":  types.StringValue("2"),
+	})
+
+	planned, diags := plannedSecretIDs(ctx, secrets, stateIDs)
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+
+	expected := types.MapValueMust(types.StringType, map[string]attr.Value{
+		"EXISTING": types.StringValue("1"),
+		"NEW":      types.StringUnknown(),
+	})
+	if !planned.Equal(expected) {
+		t.Fatalf("expected %s, got %s", expected, planned)
+	}
+
+	if planned, _ := plannedSecretIDs(ctx, types.MapUnknown(secrets.ElementType(ctx)), stateIDs); !planned.IsUnknown() {
+		t.Fatalf("expected unknown IDs for unknown secrets, got %s", planned)
+	}
+}
+
+func TestSetSecretsStateNotes(t *testing.T) {
+	managed := map[string]managedSecret{
+		"WITH_NOTE":    {ID: "1", Value: "value", Note: "note"},
+		"WITHOUT_NOTE": {ID: "2", Value: "value"},
+		"EMPTY_NOTE":   {ID: "3", Value: "value"},
+	}
+	priorNotes := types.MapValueMust(types.StringType, map[string]attr.Value{
+		"EMPTY_NOTE": types.StringValue(""),
+	})
+
+	var model secretsResourceModel
+	if diags := setSecretsState(&model, managed, types.MapNull(types.StringType), priorNotes); diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+
+	expectedNotes := types.MapValueMust(types.StringType, map[string]attr.Value{
+		"WITH_NOTE":  types.StringValue("note"),
+		"EMPTY_NOTE": types.StringValue(""),
+	})
+	if !model.Notes.Equal(expectedNotes) {
+		t.Fatalf("expected notes %s, got %s", expectedNotes, model.Notes)
+	}
+	if len(model.Secrets.Elements()) != 3 || len(model.SecretIDs.Elements()) != 3 {
+		t.Fatalf("expected 3 secrets and IDs, got %s and %s", model.Secrets, model.SecretIDs)
+	}
+
+	// Unset notes stay unset if no secret has a note.
+	delete(managed, "WITH_NOTE")
+	if diags := setSecretsState(&model, managed, types.MapNull(types.StringType), types.MapNull(types.StringType)); diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if !model.Notes.IsNull() {
+		t.Fatalf("expected null notes, got %s", model.Notes)
+	}
+}
+
+func TestFindUnmanagedSecrets(t *testing.T) {
+	managed := map[string]managedSecret{
+		"MANAGED": {ID: "1"},
+	}
+	projectSecrets := []sdk.SecretResponse{
+		{ID: "1", Key: "MANAGED"},
+		{ID: "2", Key: "MANAGED"},
+		{ID: "3", Key: "ADDED_BY_HAND"},
+	}
+
+	unmanaged := findUnmanagedSecrets(projectSecrets, managed)
+
+	expected := types.MapValueMust(types.StringType, map[string]attr.Value{
+		"2": types.StringValue("MANAGED"),
+		"3": types.StringValue("ADDED_BY_HAND"),
+	})
+	if !unmanaged.Equal(expected) {
+		t.Fatalf("expected %s, got %s", expected, unmanaged)
+	}
+}
+
+func TestPlannedUnmanagedSecrets(t *testing.T) {
+	ctx := context.Background()
+	unmanaged := types.MapValueMust(types.StringType, map[string]attr.Value{
+		"2": types.StringValue("ADDED_BY_HAND"),
+	})
+	empty := types.MapValueMust(types.StringType, map[string]attr.Value{})
+
+	testCases := map[string]struct {
+		plan        secretsResourceModel
+		state       secretsResourceModel
+		expected    types.Map
+		expectError bool
+	}{
+		"not exclusive": {
+			plan:     secretsResourceModel{Exclusive: types.BoolValue(false), UnmanagedAction: types.StringValue(unmanagedActionDelete)},
+			state:    secretsResourceModel{Exclusive: types.BoolValue(true), UnmanagedSecrets: unmanaged},
+			expected: types.MapNull(types.StringType),
+		},
+		"newly exclusive": {
+			plan:     secretsResourceModel{Exclusive: types.BoolValue(true), UnmanagedAction: types.StringValue(unmanagedActionDelete)},
+			state:    secretsResourceModel{Exclusive: types.BoolValue(false), UnmanagedSecrets: types.MapNull(types.StringType)},
+			expected: types.MapUnknown(types.StringType),
+		},
+		"no unmanaged secrets": {
+			plan:     secretsResourceModel{Exclusive: types.BoolValue(true), UnmanagedAction: types.StringValue(unmanagedActionError)},
+			state:    secretsResourceModel{Exclusive: types.BoolValue(true), UnmanagedSecrets: empty},
+			expected: empty,
+		},
+		"delete unmanaged secrets": {
+			plan:     secretsResourceModel{Exclusive: types.BoolValue(true), UnmanagedAction: types.StringValue(unmanagedActionDelete)},
+			state:    secretsResourceModel{Exclusive: types.BoolValue(true), UnmanagedSecrets: unmanaged},
+			expected: empty,
+		},
+		"error on unmanaged secrets": {
+			plan:        secretsResourceModel{ProjectID: types.StringValue(validProjectUUID), Exclusive: types.BoolValue(true), UnmanagedAction: types.StringValue(unmanagedActionError)},
+			state:       secretsResourceModel{Exclusive: types.BoolValue(true), UnmanagedSecrets: unmanaged},
+			expected:    unmanaged,
+			expectError: true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			planned, diags := plannedUnmanagedSecrets(ctx, &testCase.plan, &testCase.state)
+			if diags.HasError() != testCase.expectError {
+				t.Fatalf("expected error %t, got diagnostics: %v", testCase.expectError, diags)
+			}
+			if !planned.Equal(testCase.expected) {
+				t.Fatalf("expected %s, got %s", testCase.expected, planned)
+			}
+		})
+	}
+}
+
+func TestSecretsResourceDeletionProtection(t *testing.T) {
+	store := newFakeBitwardenStore(fakeOrganizationID)
+	client := store.newClient()
+	if err := client.AccessTokenLogin(fakeAccessToken, nil); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	project, err := client.Projects().Create(fakeOrganizationID, "project")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	harness := newResourceHarness(t, "bitwarden-sm_secrets", client)
+
+	config := func(deletionProtection bool) map[string]tftypes.Value {
+		return map[string]tftypes.Value{
+			"project_id": tftypes.NewValue(tftypes.String, project.ID),
+			"secrets": tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, map[string]tftypes.Value{
+				"API_KEY": tftypes.NewValue(tftypes.String, "value"),
+			}),
+			"deletion_protection": tftypes.NewValue(tftypes.Bool, deletionProtection),
+		}
+	}
+
+	state, diagnostics := harness.apply(config(true), harness.nullState())
+	expectDiagnosticError(t, diagnostics, "")
+
+	state, diagnostics = harness.destroy(state)
+	expectDiagnosticError(t, diagnostics, "Deletion Protection Enabled")
+	if stored := len(storedValues(store)); stored != 1 {
+		t.Fatalf("expected the protected secrets to be kept, got %d stored secrets", stored)
+	}
+
+	// Removing deletion_protection from the configuration keeps the protection instead of planning the default.
+	unset := config(false)
+	delete(unset, "deletion_protection")
+	state, diagnostics = harness.apply(unset, state)
+	expectDiagnosticError(t, diagnostics, "")
+	state, diagnostics = harness.destroy(state)
+	expectDiagnosticError(t, diagnostics, "Deletion Protection Enabled")
+
+	state, diagnostics = harness.apply(config(false), state)
+	expectDiagnosticError(t, diagnostics, "")
+	_, diagnostics = harness.destroy(state)
+	expectDiagnosticError(t, diagnostics, "")
+	if stored := len(storedValues(store)); stored != 0 {
+		t.Fatalf("expected no stored secrets, got %d", stored)
+	}
+}
@@ -0,0 +1,253 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/bitwarden/sdk-go"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/datasource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"time"
+)
+
+// syncSafetyMargin is subtracted from the start of the sync for synced_at. The API compares the revision dates of the
+// secrets with its own clock and synced_at is truncated to seconds, so without the margin a change made in the same
+// second as the sync, or on a server whose clock is behind the local one, would not be reported by the next sync.
+const syncSafetyMargin = time.Minute
+
+var (
+	// Ensure provider defined types fully satisfy framework interfaces.
+	_ datasource.DataSource              = &syncDataSource{}
+	_ datasource.DataSourceWithConfigure = &syncDataSource{}
+)
+
+func NewSyncDataSource() datasource.DataSource {
+	return &syncDataSource{}
+}
+
+// syncDataSource defines the data source implementation.
+type syncDataSource struct {
+	bitwardenClient sdk.BitwardenClientInterface
+	organizationId  string
+}
+
+// syncDataSourceModel describes the data source data model.
+type syncDataSourceModel struct {
+	LastSyncedDate types.String      `tfsdk:"last_synced_date"`
+	SyncedAt       types.String      `tfsdk:"synced_at"`
+	HasChanges     types.Bool        `tfsdk:"has_changes"`
+	Secrets        []syncSecretModel `tfsdk:"secrets"`
+
+	Timeouts timeouts.Value `tfsdk:"timeouts"`
+}
+
+type syncSecretModel struct {
+	ID             types.String `tfsdk:"id"`
+	Key            types.String `tfsdk:"key"`
+	Value          types.String `tfsdk:"value"`
+	Note           types.String `tfsdk:"note"`
+	OrganizationID types.String `tfsdk:"organization_id"`
+	ProjectID      types.String `tfsdk:"project_id"`
+	CreationDate   types.String `tfsdk:"creation_date"`
+	RevisionDate   types.String `tfsdk:"revision_date"`
+}
+
+func (d *syncDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_sync"
+}
+
+func (d *syncDataSource) Schema(ctx context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description:         "The sync data source checks whether secrets accessible by the used machine account changed since a given date and fetches the changed secrets. Pass synced_at of a previous run as last_synced_date to detect changes incrementally.",
+		MarkdownDescription: "The `sync` data source checks whether secrets accessible by the used machine account changed since a given date and fetches the changed secrets. Pass `synced_at` of a previous run as `last_synced_date` to detect changes incrementally.",
+		Attributes: map[string]schema.Attribute{
+			"last_synced_date": schema.StringAttribute{
+				Description:         "Date in RFC3339 format of the last sync, e.g. 2025-01-31T00:00:00Z. All accessible secrets are returned if unset.",
+				MarkdownDescription: "Date in RFC3339 format of the last sync, e.g. `2025-01-31T00:00:00Z`. All accessible secrets are returned if unset.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringRFC3339Validate(),
+				},
+			},
+			"synced_at": schema.StringAttribute{
+				Description:         "Date in RFC3339 format one minute before the sync was started. Use it as last_synced_date of the next sync. The margin covers differences between the local clock and the clock of Bitwarden Secrets Manager, so changes made shortly before a sync may be reported again by the next sync, but no change is missed.",
+				MarkdownDescription: "Date in RFC3339 format one minute before the sync was started. Use it as `last_synced_date` of the next sync. The margin covers differences between the local clock and the clock of Bitwarden Secrets Manager, so changes made shortly before a sync may be reported again by the next sync, but no change is missed.",
+				Computed:            true,
+			},
+			"has_changes": schema.BoolAttribute{
+				Description:         "Whether any accessible secret changed since last_synced_date.",
+				MarkdownDescription: "Whether any accessible secret changed since `last_synced_date`.",
+				Computed:            true,
+			},
+			"secrets": schema.ListNestedAttribute{
+				Description: "Nested list of all accessible secrets if any of them changed, otherwise the list is empty.",
+				Computed:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							Description:         "String representation of the ID of the secret inside Bitwarden Secrets Manager.",
+							MarkdownDescription: "String representation of the `ID` of the secret inside Bitwarden Secrets Manager.",
+							Computed:            true,
+						},
+						"key": schema.StringAttribute{
+							Description:         "String representation of the key of the secret. Inside Bitwarden Secrets Manager this is called \"name\".",
+							MarkdownDescription: "String representation of the `key` of the secret. Inside Bitwarden Secrets Manager this is called \"name\".",
+							Computed:            true,
+						},
+						"value": schema.StringAttribute{
+							Description:         "String representation of the value of the secret inside Bitwarden Secrets Manager. This attribute is sensitive.",
+							MarkdownDescription: "String representation of the `value` of the secret inside Bitwarden Secrets Manager. This attribute is sensitive.",
+							Computed:            true,
+							Sensitive:           true,
+						},
+						"note": schema.StringAttribute{
+							Description:         "String representation of the note of the secret inside Bitwarden Secrets Manager. This attribute is sensitive.",
+							MarkdownDescription: "String representation of the `note` of the secret inside Bitwarden Secrets Manager. This attribute is sensitive.",
+							Computed:            true,
+							Sensitive:           true,
+						},
+						"organization_id": schema.StringAttribute{
+							Description:         "String representation of the ID of the organization to which the secret belongs.",
+							MarkdownDescription: "String representation of the `ID` of the organization to which the secret belongs.",
+							Computed:            true,
+						},
+						"project_id": schema.StringAttribute{
+							Description:         "String representation of the ID of the project to which the secret belongs.",
+							MarkdownDescription: "String representation of the `ID` of the project to which the secret belongs.",
+							Computed:            true,
+						},
+						"creation_date": schema.StringAttribute{
+							Description: "String representation of the creation date of the secret.",
+							Computed:    true,
+						},
+						"revision_date": schema.StringAttribute{
+							Description: "String representation of the revision date of the secret.",
+							Computed:    true,
+						},
+					},
+				},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx),
+		},
+	}
+}
+
+func (d *syncDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Add a nil check when handling BitwardenSecretsManagerProviderDataStruct because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	tflog.Info(ctx, "Configuring Sync Datasource")
+	if req.ProviderData == nil {
+		tflog.Debug(ctx, "Skipping Datasource Configuration because Provider has not been configured yet.")
+		return
+	}
+
+	providerDataStruct, ok := req.ProviderData.(BitwardenSecretsManagerProviderDataStruct)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *sdk.BitwardenClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	client := providerDataStruct.bitwardenClient
+	organizationId := providerDataStruct.organizationId
+
+	if client == nil {
+		resp.Diagnostics.AddError(
+			"Client Not Initialized",
+			"The Bitwarden client was not properly initialized due to a missing Bitwarden API Client.",
+		)
+		return
+	}
+
+	if organizationId == "" {
+		resp.Diagnostics.AddError(
+			"Client Not Initialized",
+			"The Bitwarden client was not properly initialized due to an empty Organization ID.",
+		)
+		return
+	}
+
+	d.bitwardenClient = client
+	d.organizationId = organizationId
+
+	tflog.Info(ctx, "Datasource Configured")
+}
+
+func (d *syncDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	tflog.Info(ctx, "Reading Sync Datasource")
+
+	var state syncDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if d.bitwardenClient == nil {
+		resp.Diagnostics.AddError(
+			"Client Not Initialized",
+			"The Bitwarden client was not properly initialized.",
+		)
+		return
+	}
+
+	ctx, client, done := withClientTimeout(ctx, d.bitwardenClient, state.Timeouts.Read, "read", "the sync data source", &resp.Diagnostics)
+	defer done()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var lastSyncedDate *time.Time
+	if !state.LastSyncedDate.IsNull() {
+		// The format was checked by the validator of the attribute.
+		date, _ := time.Parse(time.RFC3339, state.LastSyncedDate.ValueString())
+		lastSyncedDate = &date
+	}
+
+	// The start of the sync is taken before the request, so changes made during the sync are reported by the next one.
+	syncedAt := time.Now().Add(-syncSafetyMargin).UTC()
+
+	response, err := client.Secrets().Sync(d.organizationId, lastSyncedDate)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Sync Secrets",
+			err.Error(),
+		)
+		return
+	}
+
+	state.SyncedAt = types.StringValue(syncedAt.Format(time.RFC3339))
+	state.HasChanges = types.BoolValue(response.HasChanges)
+	state.Secrets = syncSecretModels(response.Secrets)
+
+	// Set state
+	diags := resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func syncSecretModels(secrets []sdk.SecretResponse) []syncSecretModel {
+	models := make([]syncSecretModel, 0, len(secrets))
+	for _, secret := range secrets {
+		models = append(models, syncSecretModel{
+			ID:             types.StringValue(secret.ID),
+			Key:            types.StringValue(secret.Key),
+			Value:          types.StringValue(secret.Value),
+			Note:           types.StringValue(secret.Note),
+			OrganizationID: types.StringValue(secret.OrganizationID),
+			ProjectID:      types.StringPointerValue(secret.ProjectID),
+			CreationDate:   types.StringValue(secret.CreationDate.String()),
+			RevisionDate:   types.StringValue(secret.RevisionDate.String()),
+		})
+	}
+	return models
+}
@@ -0,0 +1,171 @@
+package provider
+
+import (
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestAccDatasourceSyncExpectErrorOnInvalidLastSyncedDate(t *testing.T) {
+	runProviderTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: buildProviderConfigFromEnvFile(t) + `
+                        data "bitwarden-sm_sync" "test" {
+                            last_synced_date = "2025-01-31"
+                        }`,
+				ExpectError: regexp.MustCompile("string attribute not a valid RFC3339 timestamp"),
+			},
+		},
+	})
+}
+
+func TestAccDatasourceSyncDetectsChanges(t *testing.T) {
+	var secretId, projectId string
+	secretKey := "Test-Secret-" + generateRandomString()
+	secretValue := generateRandomString()
+	projectName := "Test-Project-" + generateRandomString()
+	bitwardenClient, organizationId, err := newBitwardenClient()
+
+	if err != nil {
+		t.Fatalf("Error creating bitwardenClient: %s", err)
+	}
+
+	project, preCheckError := bitwardenClient.Projects().Create(organizationId, projectName)
+	if preCheckError != nil {
+		t.Fatal("Error creating test project for provider validation.")
+	}
+	projectId = project.ID
+
+	secret, preCheckError := bitwardenClient.Secrets().Create(secretKey, secretValue, "", organizationId, []string{projectId})
+	if preCheckError != nil {
+		t.Fatal("Error creating test secret for provider validation.")
+	}
+	secretId = secret.ID
+
+	runProviderTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: buildProviderConfigFromEnvFile(t) + `
+                            data "bitwarden-sm_sync" "all" {}`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.bitwarden-sm_sync.all", "has_changes", "true"),
+					resource.TestCheckResourceAttrSet("data.bitwarden-sm_sync.all", "synced_at"),
+					resource.TestCheckTypeSetElemNestedAttrs("data.bitwarden-sm_sync.all", "secrets.*", map[string]string{
+						"id":         secretId,
+						"key":        secretKey,
+						"value":      secretValue,
+						"project_id": projectId,
+					}),
+				),
+			},
+			{
+				Config: buildProviderConfigFromEnvFile(t) + `
+                            data "bitwarden-sm_sync" "future" {
+                                last_synced_date = "2999-01-01T00:00:00Z"
+                            }`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.bitwarden-sm_sync.future", "has_changes", "false"),
+					resource.TestCheckResourceAttr("data.bitwarden-sm_sync.future", "secrets.#", "0"),
+				),
+			},
+		},
+		CheckDestroy: func(state *terraform.State) error {
+			_, cleanUpErr := bitwardenClient.Secrets().Delete([]string{secretId})
+			if cleanUpErr != nil {
+				t.Fatalf("Error cleaning up test secret: %s", cleanUpErr)
+			}
+			_, cleanUpErr = bitwardenClient.Projects().Delete([]string{projectId})
+			if cleanUpErr != nil {
+				t.Fatalf("Error cleaning up test project: %s", cleanUpErr)
+			}
+			return nil
+		},
+	})
+}
+
+// readSync reads the sync data source through the plugin protocol and returns synced_at and has_changes.
+func readSync(t *testing.T, harness *resourceHarness, lastSyncedDate string) (string, bool) {
+	t.Helper()
+
+	schemaResponse, err := harness.server.GetProviderSchema(harness.ctx, &tfprotov6.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	objectType := schemaResponse.DataSourceSchemas["bitwarden-sm_sync"].ValueType().(tftypes.Object)
+	attributes := map[string]tftypes.Value{}
+	if lastSyncedDate != "" {
+		attributes["last_synced_date"] = tftypes.NewValue(tftypes.String, lastSyncedDate)
+	}
+
+	response, err := harness.server.ReadDataSource(harness.ctx, &tfprotov6.ReadDataSourceRequest{
+		TypeName: "bitwarden-sm_sync",
+		Config:   newDynamicValue(t, objectType, newObjectValue(objectType, attributes)),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	expectDiagnosticError(t, response.Diagnostics, "")
+	state, err := response.State.Unmarshal(objectType)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	var values map[string]tftypes.Value
+	var syncedAt string
+	var hasChanges bool
+	if err := state.As(&values); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := values["synced_at"].As(&syncedAt); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := values["has_changes"].As(&hasChanges); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	return syncedAt, hasChanges
+}
+
+func TestSyncDataSourceReportsChangesInTheSameSecond(t *testing.T) {
+	store := newFakeBitwardenStore(fakeOrganizationID)
+	client := store.newClient()
+	harness := newResourceHarness(t, "bitwarden-sm_secret", client)
+	project, err := client.Projects().Create(fakeOrganizationID, "project")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	secret, err := client.Secrets().Create("key", "value", "", fakeOrganizationID, []string{project.ID})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	started := time.Now()
+	syncedAt, hasChanges := readSync(t, harness, "")
+	if !hasChanges {
+		t.Fatal("expected the first sync to report changes")
+	}
+	synced, err := time.Parse(time.RFC3339, syncedAt)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if synced.After(started.Add(-syncSafetyMargin)) {
+		t.Fatalf("expected synced_at %s to be at least %s before the start of the sync", syncedAt, syncSafetyMargin)
+	}
+
+	// The server stores the revision date of a change made right after the sync in the same second as the sync.
+	store.mu.Lock()
+	changed := store.secrets[secret.ID]
+	changed.RevisionDate = started.UTC().Truncate(time.Second)
+	store.secrets[secret.ID] = changed
+	store.mu.Unlock()
+
+	if _, hasChanges := readSync(t, harness, syncedAt); !hasChanges {
+		t.Fatalf("expected the change at %s to be reported by the sync after %s", changed.RevisionDate.Format(time.RFC3339), syncedAt)
+	}
+}
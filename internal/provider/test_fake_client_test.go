@@ -0,0 +1,207 @@
+package provider
+
+import (
+	"github.com/bitwarden/sdk-go"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newLoggedInFakeClient(t *testing.T, store *fakeBitwardenStore, accessToken string) *fakeBitwardenClient {
+	t.Helper()
+
+	client := store.newClient()
+	if err := client.AccessTokenLogin(accessToken, nil); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	return client
+}
+
+func TestFakeClientLogin(t *testing.T) {
+	store := newFakeBitwardenStore(fakeOrganizationID)
+
+	client := store.newClient()
+	if _, err := client.Secrets().List(fakeOrganizationID); err == nil {
+		t.Fatal("expected requests without login to fail")
+	}
+
+	err := client.AccessTokenLogin("0.00000000-0000-0000-0000-000000000000.unknown:AAECAwQFBgcICQoLDA0ODw==", nil)
+	if err == nil || !strings.Contains(err.Error(), "invalid_client") {
+		t.Fatalf("expected an invalid_client error, got: %v", err)
+	}
+
+	client = newLoggedInFakeClient(t, store, fakeAccessToken)
+	if _, err := client.Secrets().List("00000000-0000-0000-0000-000000000000"); err == nil {
+		t.Fatal("expected requests for another organization to fail")
+	}
+}
+
+func TestFakeClientSecrets(t *testing.T) {
+	store := newFakeBitwardenStore(fakeOrganizationID)
+	client := newLoggedInFakeClient(t, store, fakeAccessToken)
+
+	project, err := client.Projects().Create(fakeOrganizationID, "project")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	created, err := client.Secrets().Create("API_KEY", "value", "note", fakeOrganizationID, []string{project.ID})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, err := client.Secrets().Create("API_KEY", "value", "", fakeOrganizationID, []string{project.ID, project.ID}); err == nil {
+		t.Fatal("expected secrets with several projects to be rejected")
+	}
+
+	// Clients of the same store share its data.
+	other := newLoggedInFakeClient(t, store, fakeAccessToken)
+	read, err := other.Secrets().Get(created.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if read.Key != "API_KEY" || read.Value != "value" || read.ProjectID == nil || *read.ProjectID != project.ID {
+		t.Fatalf("unexpected secret: %+v", read)
+	}
+
+	updated, err := client.Secrets().Update(created.ID, "API_KEY", "changed", "", fakeOrganizationID, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if updated.Value != "changed" || updated.ProjectID != nil {
+		t.Fatalf("unexpected secret: %+v", updated)
+	}
+
+	if _, err := client.Secrets().GetByIDS([]string{created.ID, "00000000-0000-0000-0000-000000000000"}); err == nil {
+		t.Fatal("expected GetByIDS to fail for missing secrets")
+	}
+
+	deleted, err := client.Secrets().Delete([]string{created.ID, created.ID})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if deleted.Data[0].Error != nil || deleted.Data[1].Error == nil || *deleted.Data[1].Error != "Secret not found." {
+		t.Fatalf("unexpected delete response: %+v", deleted.Data)
+	}
+
+	_, err = client.Secrets().Get(created.ID)
+	if err == nil || !strings.Contains(err.Error(), "404 Not Found") {
+		t.Fatalf("expected a not found error, got: %v", err)
+	}
+}
+
+func TestFakeClientProjectDeletionKeepsSecrets(t *testing.T) {
+	client := newLoggedInFakeClient(t, newFakeBitwardenStore(fakeOrganizationID), fakeAccessToken)
+
+	project, err := client.Projects().Create(fakeOrganizationID, "project")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	secret, err := client.Secrets().Create("API_KEY", "value", "", fakeOrganizationID, []string{project.ID})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if _, err := client.Projects().Delete([]string{project.ID}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	read, err := client.Secrets().Get(secret.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if read.ProjectID != nil {
+		t.Fatalf("expected the secret to be unassigned, got project %s", *read.ProjectID)
+	}
+}
+
+func TestFakeClientNoAccess(t *testing.T) {
+	store := newFakeBitwardenStore(fakeOrganizationID)
+	secret, err := newLoggedInFakeClient(t, store, fakeAccessToken).Secrets().Create("API_KEY", "value", "", fakeOrganizationID, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	client := newLoggedInFakeClient(t, store, fakeNoAccessToken)
+	if _, err := client.Secrets().Get(secret.ID); err == nil {
+		t.Fatal("expected the secret to be inaccessible")
+	}
+	identifiers, err := client.Secrets().List(fakeOrganizationID)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(identifiers.Data) != 0 {
+		t.Fatalf("expected no secrets, got %d", len(identifiers.Data))
+	}
+}
+
+func TestFakeClientSync(t *testing.T) {
+	client := newLoggedInFakeClient(t, newFakeBitwardenStore(fakeOrganizationID), fakeAccessToken)
+	if _, err := client.Secrets().Create("API_KEY", "value", "", fakeOrganizationID, nil); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	response, err := client.Secrets().Sync(fakeOrganizationID, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !response.HasChanges || len(response.Secrets) != 1 {
+		t.Fatalf("expected one changed secret, got: %+v", response)
+	}
+
+	lastSynced := time.Now().UTC().Add(time.Minute)
+	response, err = client.Secrets().Sync(fakeOrganizationID, &lastSynced)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if response.HasChanges {
+		t.Fatalf("expected no changes, got: %+v", response)
+	}
+}
+
+func TestFakeClientGeneratePassword(t *testing.T) {
+	client := newLoggedInFakeClient(t, newFakeBitwardenStore(fakeOrganizationID), fakeAccessToken)
+
+	password, err := client.Generators().GeneratePassword(sdk.PasswordGeneratorRequest{Length: 24, Lowercase: true, Numbers: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(*password) != 24 || strings.ToLower(*password) != *password {
+		t.Fatalf("unexpected password %q", *password)
+	}
+}
+
+func TestFindSecretsWithKey(t *testing.T) {
+	client := newLoggedInFakeClient(t, newFakeBitwardenStore(fakeOrganizationID), fakeAccessToken)
+
+	project, err := client.Projects().Create(fakeOrganizationID, "project")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	inProject, err := client.Secrets().Create("API_KEY", "value", "", fakeOrganizationID, []string{project.ID})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	withoutProject, err := client.Secrets().Create("API_KEY", "value", "", fakeOrganizationID, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, err := client.Secrets().Create("api_key", "value", "", fakeOrganizationID, []string{project.ID}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	secrets, err := findSecretsWithKey(client, fakeOrganizationID, "API_KEY", &project.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(secrets) != 1 || secrets[0].ID != inProject.ID {
+		t.Fatalf("expected only the secret %s, got: %+v", inProject.ID, secrets)
+	}
+
+	secrets, err = findSecretsWithKey(client, fakeOrganizationID, "API_KEY", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(secrets) != 1 || secrets[0].ID != withoutProject.ID {
+		t.Fatalf("expected only the secret %s, got: %+v", withoutProject.ID, secrets)
+	}
+}
@@ -1,16 +1,23 @@
 package provider
 
 import (
+	"errors"
 	"fmt"
 	"github.com/bitwarden/sdk-go"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 	"github.com/hashicorp/terraform-plugin-testing/terraform"
 	"github.com/joho/godotenv"
 	"log"
 	"math/rand"
 	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 )
@@ -21,9 +28,13 @@ var (
 	// CLI command executed to create a provider server to which the CLI can
 	// reattach.
 	testAccProtoV6ProviderFactories = map[string]func() (tfprotov6.ProviderServer, error){
-		"bitwarden-sm": providerserver.NewProtocol6WithError(New("test")()),
+		"bitwarden-sm": providerserver.NewProtocol6WithError(newTestProvider()),
 	}
 
+	// testFakeStore returns the in-memory organization used instead of a real server if the tests run offline,
+	// see useFakeClient. It is evaluated lazily, so the log message is only written if a test uses the client.
+	testFakeStore = sync.OnceValue(newTestFakeStore)
+
 	seededRand = rand.New(rand.NewSource(time.Now().UnixNano()))
 )
 
@@ -35,8 +46,92 @@ const (
 	accessTokenKey    = "BW_ACCESS_TOKEN"
 	organizationIDKey = "BW_ORGANIZATION_ID"
 	stateFileKey      = "BW_STATE_FILE"
+
+	// fakeClientKey forces the tests to run against the in-memory fake Bitwarden client if set to true.
+	fakeClientKey = "BW_FAKE_CLIENT"
 )
 
+// useFakeClient reports whether the tests run offline against the in-memory fake Bitwarden client. This is the
+// case if the .env file for the acceptance tests does not exist or BW_FAKE_CLIENT is set to true.
+func useFakeClient() bool {
+	if os.Getenv(fakeClientKey) == "true" {
+		return true
+	}
+	_, err := os.Stat(envFileAccTests)
+	return errors.Is(err, os.ErrNotExist)
+}
+
+func newTestFakeStore() *fakeBitwardenStore {
+	if !useFakeClient() {
+		return nil
+	}
+	log.Printf("Running the tests against the in-memory fake Bitwarden client, since %s does not exist or %s is set.", envFileAccTests, fakeClientKey)
+	return newFakeBitwardenStore(fakeOrganizationID)
+}
+
+// newTestProvider returns the provider under test, which uses the in-memory fake Bitwarden client if the tests
+// run offline.
+func newTestProvider() provider.Provider {
+	return &BitwardenSecretsManagerProvider{
+		version:            "test",
+		newBitwardenClient: newTestBitwardenClient,
+	}
+}
+
+func newTestBitwardenClient(apiURL *string, identityURL *string) (sdk.BitwardenClientInterface, error) {
+	if store := testFakeStore(); store != nil {
+		return store.newClient(), nil
+	}
+	return sdk.NewBitwardenClient(apiURL, identityURL)
+}
+
+// runProviderTest runs a test case of the provider. Against the in-memory fake Bitwarden client no real resources are
+// created, so the test case runs without TF_ACC if a Terraform CLI is available.
+func runProviderTest(t *testing.T, testCase resource.TestCase) {
+	t.Helper()
+
+	if testFakeStore() != nil && terraformCLIAvailable() {
+		resource.UnitTest(t, testCase)
+		return
+	}
+	resource.Test(t, testCase)
+}
+
+// terraformCLIAvailable reports whether the test framework can use a local Terraform CLI instead of downloading one.
+func terraformCLIAvailable() bool {
+	if os.Getenv("TF_ACC_TERRAFORM_PATH") != "" {
+		return true
+	}
+	_, err := exec.LookPath("terraform")
+	return err == nil
+}
+
+// skipWithFakeClient skips tests which need parts of the Bitwarden Secrets Manager API the fake client does not cover.
+func skipWithFakeClient(t *testing.T) {
+	t.Helper()
+
+	if testFakeStore() != nil {
+		t.Skip("The in-memory fake Bitwarden client does not cover the API used by this test.")
+	}
+}
+
+// fakeEnvMap returns the configuration of the fake Bitwarden client in the format of the .env files. The
+// .env.local.no.access file used for a machine account without access is mapped to an access token without access.
+func fakeEnvMap(envFile string) map[string]string {
+	accessToken := fakeAccessToken
+	if strings.Contains(filepath.Base(envFile), "no.access") {
+		accessToken = fakeNoAccessToken
+	}
+
+	return map[string]string{
+		apiUrlKey:         "https://api.bitwarden.fake",
+		identityUrlKey:    "https://identity.bitwarden.fake",
+		accessTokenKey:    accessToken,
+		organizationIDKey: fakeOrganizationID,
+		stateFileKey:      ".bw-fake-state",
+	}
+}
+
 func generateRandomString() string {
 	b := make([]byte, 8)
 	for i := range b {
@@ -57,6 +152,10 @@ func resolveFilePath(filePath []string) string {
 }
 
 func readEnvFile(envFile string) (map[string]string, error) {
+	if testFakeStore() != nil {
+		return fakeEnvMap(envFile), nil
+	}
+
 	envMap, err := godotenv.Read(envFile)
 	if err != nil {
 		return nil, fmt.Errorf("error loading %s file: %w", envFile, err)
@@ -92,7 +191,7 @@ func newBitwardenClient() (sdk.BitwardenClientInterface, string, error) {
 	organizationId := envMap[organizationIDKey]
 	stateFile := envMap[stateFileKey]
 
-	bitwardenClient, err := sdk.NewBitwardenClient(&apiUrl, &identityUrl)
+	bitwardenClient, err := newTestBitwardenClient(&apiUrl, &identityUrl)
 	if err != nil {
 		return nil, "", err
 	}
@@ -134,6 +233,11 @@ func unsetAllEnvVars() error {
 }
 
 func buildProviderConfigFromEnvFile(t *testing.T, filePath ...string) string {
+	return buildProviderConfigFromEnvFileWithAttributes(t, "", filePath...)
+}
+
+// buildProviderConfigFromEnvFileWithAttributes adds further attributes, e.g. a key_naming_policy, to the provider configuration.
+func buildProviderConfigFromEnvFileWithAttributes(t *testing.T, attributes string, filePath ...string) string {
 	envFilePath := resolveFilePath(filePath)
 	envMap, err := readEnvFile(envFilePath)
 	if err != nil {
@@ -151,7 +255,8 @@ func buildProviderConfigFromEnvFile(t *testing.T, filePath ...string) string {
             identity_url = "%s"
             access_token = "%s"
             organization_id = "%s"
-        }`, apiUrl, identityUrl, accessToken, organizationId)
+            %s
+        }`, apiUrl, identityUrl, accessToken, organizationId, attributes)
 
 	return providerConfig
 }
@@ -165,6 +270,17 @@ type SecretResourceConfig struct {
 	minLowercase types.Int64
 	minNumber    types.Int64
 	minUppercase types.Int64
+	minSpecial   types.Int64
+	special      types.Bool
+
+	specialCharacters types.String
+	excludeCharacters types.String
+
+	// valueJSON is rendered as raw HCL expression, e.g. an object literal or a jsonencode() call.
+	valueJSON types.String
+
+	driftPolicy      types.String
+	enforceUniqueKey types.String
 }
 
 func buildSecretResourceConfig(config SecretResourceConfig) string {
@@ -179,6 +295,10 @@ func buildSecretResourceConfig(config SecretResourceConfig) string {
 		configString += fmt.Sprintf(`
 			value = "%s"`, config.value.ValueString())
 	}
+	if config.valueJSON.ValueString() != "" {
+		configString += fmt.Sprintf(`
+			value_json = %s`, config.valueJSON.ValueString())
+	}
 	if config.note.ValueString() != "" {
 		configString += fmt.Sprintf(`
 			note = "%s"`, config.note.ValueString())
@@ -203,6 +323,30 @@ func buildSecretResourceConfig(config SecretResourceConfig) string {
 		configString += fmt.Sprintf(`
 			min_uppercase = %d`, config.minUppercase.ValueInt64())
 	}
+	if config.minSpecial.ValueInt64() > 0 {
+		configString += fmt.Sprintf(`
+			min_special = %d`, config.minSpecial.ValueInt64())
+	}
+	if config.special.ValueBool() {
+		configString += `
+			special = true`
+	}
+	if config.specialCharacters.ValueString() != "" {
+		configString += fmt.Sprintf(`
+			special_characters = "%s"`, config.specialCharacters.ValueString())
+	}
+	if config.excludeCharacters.ValueString() != "" {
+		configString += fmt.Sprintf(`
+			exclude_characters = "%s"`, config.excludeCharacters.ValueString())
+	}
+	if config.driftPolicy.ValueString() != "" {
+		configString += fmt.Sprintf(`
+			drift_policy = "%s"`, config.driftPolicy.ValueString())
+	}
+	if config.enforceUniqueKey.ValueString() != "" {
+		configString += fmt.Sprintf(`
+			enforce_unique_key = "%s"`, config.enforceUniqueKey.ValueString())
+	}
 
 	configString += `
 	}`
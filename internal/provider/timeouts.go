@@ -0,0 +1,69 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/bitwarden/sdk-go"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"strings"
+	"time"
+)
+
+// defaultOperationTimeout is the timeout of an operation of a resource or data source whose timeouts block does not
+// configure one.
+const defaultOperationTimeout = 5 * time.Minute
+
+// operationTimeout reads the timeout of an operation from a timeouts block, e.g. state.Timeouts.Read.
+type operationTimeout func(ctx context.Context, defaultTimeout time.Duration) (time.Duration, diag.Diagnostics)
+
+// withOperationTimeout sets the deadline of an operation of a resource or data source, e.g. the "read" of "the
+// secret", on ctx. The returned function ends the operation and is meant to be deferred: it replaces the errors caused
+// by the timeout or a cancellation with a single diagnostic, see addTimeoutDiagnostic and addCancellationDiagnostic.
+// If the timeout cannot be read, the errors are added to diags and ctx is returned as it is.
+func withOperationTimeout(ctx context.Context, timeout operationTimeout, timeoutName string, subject string, diags *diag.Diagnostics) (context.Context, func()) {
+	duration, timeoutDiags := timeout(ctx, defaultOperationTimeout)
+	diags.Append(timeoutDiags...)
+	if timeoutDiags.HasError() {
+		return ctx, func() {}
+	}
+
+	operation := timeoutName + " of " + subject
+	ctx, cancel := context.WithTimeout(ctx, duration)
+	return ctx, func() {
+		addCancellationDiagnostic(ctx, diags, operation)
+		addTimeoutDiagnostic(ctx, diags, operation, timeoutName, duration)
+		cancel()
+	}
+}
+
+// withClientTimeout is withOperationTimeout for operations which use the SDK. It also returns the Bitwarden client
+// bound to the context of the operation, see clientWithContext.
+func withClientTimeout(ctx context.Context, bitwardenClient sdk.BitwardenClientInterface, timeout operationTimeout, timeoutName string, subject string, diags *diag.Diagnostics) (context.Context, sdk.BitwardenClientInterface, func()) {
+	ctx, done := withOperationTimeout(ctx, timeout, timeoutName, subject, diags)
+	return ctx, clientWithContext(ctx, bitwardenClient), done
+}
+
+// addTimeoutDiagnostic replaces the errors of an operation which ran into its timeout with a single diagnostic naming
+// the operation and the timeout. It is called when an operation started by withOperationTimeout ends. Operations
+// which succeeded despite the deadline are not changed.
+func addTimeoutDiagnostic(ctx context.Context, diags *diag.Diagnostics, operation string, timeoutName string, timeout time.Duration) {
+	if !errors.Is(ctx.Err(), context.DeadlineExceeded) || !diags.HasError() {
+		return
+	}
+
+	kept := diag.Diagnostics{}
+	for _, diagnostic := range *diags {
+		if diagnostic.Severity() == diag.SeverityError && strings.Contains(diagnostic.Detail(), context.DeadlineExceeded.Error()) {
+			continue
+		}
+		kept = append(kept, diagnostic)
+	}
+	kept.AddError(
+		"Operation Timed Out",
+		fmt.Sprintf("The %s did not finish within the timeout of %s. "+
+			"Bitwarden Secrets Manager may still complete the request, refresh the state before retrying. "+
+			"The timeout can be increased with %s in the timeouts block.", operation, timeout, timeoutName),
+	)
+	*diags = kept
+}
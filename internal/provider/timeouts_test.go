@@ -0,0 +1,349 @@
+package provider
+
+import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAddTimeoutDiagnostic(t *testing.T) {
+	expired, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+
+	var diags diag.Diagnostics
+	diags.AddWarning("Warning", "kept")
+	diags.AddError("Unable to Read Secret", "API error: context deadline exceeded")
+	diags.AddError("Other Error", "kept")
+	addTimeoutDiagnostic(expired, &diags, "read of the secret", "read", time.Minute)
+
+	if len(diags) != 3 || diags.ErrorsCount() != 2 {
+		t.Fatalf("expected the deadline error to be replaced, got %v", diags)
+	}
+	last := diags[len(diags)-1]
+	if last.Summary() != "Operation Timed Out" || !strings.Contains(last.Detail(), "read of the secret did not finish within the timeout of 1m0s") {
+		t.Fatalf("unexpected timeout diagnostic: %s: %s", last.Summary(), last.Detail())
+	}
+
+	// Successful operations and operations which did not run into their deadline are not changed.
+	var succeeded diag.Diagnostics
+	addTimeoutDiagnostic(expired, &succeeded, "read of the secret", "read", time.Minute)
+	if len(succeeded) != 0 {
+		t.Fatalf("expected no diagnostics, got %v", succeeded)
+	}
+	var failed diag.Diagnostics
+	failed.AddError("Unable to Read Secret", "not found")
+	addTimeoutDiagnostic(context.Background(), &failed, "read of the secret", "read", time.Minute)
+	if len(failed) != 1 || failed[0].Summary() != "Unable to Read Secret" {
+		t.Fatalf("expected the error to be kept, got %v", failed)
+	}
+}
+
+func TestWithOperationTimeout(t *testing.T) {
+	timeout := func(_ context.Context, _ time.Duration) (time.Duration, diag.Diagnostics) {
+		return time.Millisecond, nil
+	}
+
+	var diags diag.Diagnostics
+	ctx, done := withOperationTimeout(context.Background(), timeout, "read", "the secret", &diags)
+	<-ctx.Done()
+	diags.AddError("Unable to Read Secret", "API error: "+ctx.Err().Error())
+	done()
+	if len(diags) != 1 || !strings.Contains(diags[0].Detail(), "read of the secret did not finish within the timeout of 1ms") ||
+		!strings.Contains(diags[0].Detail(), "read in the timeouts block") {
+		t.Fatalf("expected a timeout diagnostic, got %v", diags)
+	}
+
+	// The context of the operation is cancelled when it ends.
+	ctx, done = withOperationTimeout(context.Background(), func(_ context.Context, defaultTimeout time.Duration) (time.Duration, diag.Diagnostics) {
+		return defaultTimeout, nil
+	}, "read", "the secret", &diags)
+	done()
+	if ctx.Err() == nil {
+		t.Fatal("expected the context to be cancelled")
+	}
+
+	// A timeout which cannot be read is reported and the operation is not started.
+	var invalid diag.Diagnostics
+	_, done = withOperationTimeout(context.Background(), func(_ context.Context, _ time.Duration) (time.Duration, diag.Diagnostics) {
+		var diags diag.Diagnostics
+		diags.AddError("Timeout Cannot Be Parsed", "invalid duration")
+		return 0, diags
+	}, "read", "the secret", &invalid)
+	done()
+	if len(invalid) != 1 || invalid[0].Summary() != "Timeout Cannot Be Parsed" {
+		t.Fatalf("expected the error of the timeout, got %v", invalid)
+	}
+}
+
+// timeoutsValue returns the value of the timeouts block of the harness resource with the given timeouts.
+func (h *resourceHarness) timeoutsValue(timeouts map[string]string) tftypes.Value {
+	values := map[string]tftypes.Value{}
+	for name, timeout := range timeouts {
+		values[name] = tftypes.NewValue(tftypes.String, timeout)
+	}
+	return newObjectValue(h.objectType.AttributeTypes["timeouts"].(tftypes.Object), values)
+}
+
+func TestSecretResourceTimeouts(t *testing.T) {
+	harness, client, store := newFaultInjectionHarness(t)
+	slow := fault{latency: 200 * time.Millisecond}
+
+	config := secretConfig("value")
+	config["timeouts"] = harness.timeoutsValue(map[string]string{"create": "20ms", "read": "20ms", "update": "20ms", "delete": "20ms"})
+
+	client.inject("Secrets.Create", slow)
+	_, diagnostics := harness.apply(config, harness.nullState())
+	expectDiagnosticError(t, diagnostics, "The create of the secret did not finish within the timeout of 20ms")
+	if strings.Contains(diagnosticsString(diagnostics), context.DeadlineExceeded.Error()) {
+		t.Fatalf("expected the timeout to replace the error of the client, got: %s", diagnosticsString(diagnostics))
+	}
+
+	// The timed out request completes in the background.
+	for deadline := time.Now().Add(5 * time.Second); len(storedValues(store)) == 0 && time.Now().Before(deadline); {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if stored := len(storedValues(store)); stored != 1 {
+		t.Fatalf("expected the abandoned create to complete, got %d stored secrets", stored)
+	}
+
+	state, diagnostics := harness.apply(config, harness.nullState())
+	expectDiagnosticError(t, diagnostics, "")
+
+	client.inject("Secrets.Get", slow)
+	_, diagnostics = harness.read(state)
+	expectDiagnosticError(t, diagnostics, "The read of the secret did not finish")
+
+	client.inject("Secrets.Update", slow)
+	config["value"] = tftypes.NewValue(tftypes.String, "changed")
+	_, diagnostics = harness.apply(config, state)
+	expectDiagnosticError(t, diagnostics, "The update of the secret did not finish")
+
+	client.inject("Secrets.Delete", slow)
+	_, diagnostics = harness.destroy(state)
+	expectDiagnosticError(t, diagnostics, "The delete of the secret did not finish")
+}
+
+func TestSecretResourceTimeoutRecoversCreatedSecret(t *testing.T) {
+	harness, client, store := newFaultInjectionHarness(t)
+
+	config := secretConfig("value")
+	config["timeouts"] = harness.timeoutsValue(map[string]string{"create": "20ms"})
+
+	// The secret is created, but the response arrives after the timeout.
+	client.inject("Secrets.Create", fault{hang: 200 * time.Millisecond})
+	state, diagnostics := harness.apply(config, harness.nullState())
+	expectDiagnosticError(t, diagnostics, "")
+
+	stored := storedValues(store)
+	if len(stored) != 1 {
+		t.Fatalf("expected 1 stored secret, got %d", len(stored))
+	}
+	if id := harness.stringAttribute(state, "id"); stored[id] != "value" {
+		t.Fatalf("expected the created secret %s in the state", id)
+	}
+}
+
+func TestSecretResourceTimeoutsDefault(t *testing.T) {
+	harness, client, _ := newFaultInjectionHarness(t)
+
+	// Without timeouts block, short delays are well within the default timeout.
+	client.inject("Secrets.Create", fault{latency: 20 * time.Millisecond})
+	state, diagnostics := harness.apply(secretConfig("value"), harness.nullState())
+	expectDiagnosticError(t, diagnostics, "")
+	if !harness.attribute(state, "timeouts").IsNull() {
+		t.Fatal("expected no timeouts in the state")
+	}
+}
+
+func TestSecretDataSourceTimeout(t *testing.T) {
+	harness, client, _ := newFaultInjectionHarness(t)
+	state, diagnostics := harness.apply(secretConfig("value"), harness.nullState())
+	expectDiagnosticError(t, diagnostics, "")
+
+	ctx := context.Background()
+	schemaResponse, err := harness.server.GetProviderSchema(ctx, &tfprotov6.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	objectType := schemaResponse.DataSourceSchemas["bitwarden-sm_secret"].ValueType().(tftypes.Object)
+	config := newObjectValue(objectType, map[string]tftypes.Value{
+		"id": tftypes.NewValue(tftypes.String, harness.stringAttribute(state, "id")),
+		"timeouts": newObjectValue(objectType.AttributeTypes["timeouts"].(tftypes.Object), map[string]tftypes.Value{
+			"read": tftypes.NewValue(tftypes.String, "20ms"),
+		}),
+	})
+
+	client.inject("Secrets.Get", fault{latency: 200 * time.Millisecond})
+	response, err := harness.server.ReadDataSource(ctx, &tfprotov6.ReadDataSourceRequest{
+		TypeName: "bitwarden-sm_secret",
+		Config:   newDynamicValue(t, objectType, config),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	expectDiagnosticError(t, response.Diagnostics, "The read of the secret data source did not finish within the timeout of 20ms")
+}
+
+func TestSecretsResourceTimeouts(t *testing.T) {
+	store := newFakeBitwardenStore(fakeOrganizationID)
+	client := newFaultInjectingClient(store.newClient())
+	if err := client.AccessTokenLogin(fakeAccessToken, nil); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	project, err := client.Projects().Create(fakeOrganizationID, "project")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	harness := newResourceHarness(t, "bitwarden-sm_secrets", client)
+	slow := fault{latency: 200 * time.Millisecond}
+
+	config := func(value string) map[string]tftypes.Value {
+		return map[string]tftypes.Value{
+			"project_id": tftypes.NewValue(tftypes.String, project.ID),
+			"secrets": tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, map[string]tftypes.Value{
+				"API_KEY": tftypes.NewValue(tftypes.String, value),
+			}),
+			"timeouts": harness.timeoutsValue(map[string]string{"create": "20ms", "read": "20ms", "update": "20ms", "delete": "20ms"}),
+		}
+	}
+
+	client.inject("Secrets.Create", slow)
+	_, diagnostics := harness.apply(config("value"), harness.nullState())
+	expectDiagnosticError(t, diagnostics, "The create of the secrets did not finish within the timeout of 20ms")
+
+	state, diagnostics := harness.apply(config("value"), harness.nullState())
+	expectDiagnosticError(t, diagnostics, "")
+
+	client.inject("Secrets.GetByIDS", slow)
+	_, diagnostics = harness.read(state)
+	expectDiagnosticError(t, diagnostics, "The read of the secrets did not finish")
+
+	client.inject("Secrets.Update", slow)
+	_, diagnostics = harness.apply(config("changed"), state)
+	expectDiagnosticError(t, diagnostics, "The update of the secrets did not finish")
+
+	client.inject("Secrets.Delete", slow)
+	_, diagnostics = harness.destroy(state)
+	expectDiagnosticError(t, diagnostics, "The delete of the secrets did not finish")
+}
+
+// newAPIResourceHarness is like newResourceHarness for resources which use the API client, the provider is
+// configured with the endpoints of the stand-in.
+func newAPIResourceHarness(t *testing.T, typeName string, standIn *apiStandIn) *resourceHarness {
+	t.Helper()
+
+	store := newFakeBitwardenStore(fakeOrganizationID)
+	store.accessTokens[testAPIAccessToken] = true
+	return newResourceHarnessWithConfig(t, typeName, store.newClient(), map[string]tftypes.Value{
+		"api_url":      tftypes.NewValue(tftypes.String, standIn.server.URL+"/api"),
+		"identity_url": tftypes.NewValue(tftypes.String, standIn.server.URL+"/identity"),
+		"access_token": tftypes.NewValue(tftypes.String, testAPIAccessToken),
+	})
+}
+
+func TestMachineAccountResourceTimeouts(t *testing.T) {
+	standIn := newMachineAccountStandIn(t)
+	harness := newAPIResourceHarness(t, "bitwarden-sm_machine_account", standIn.apiStandIn)
+
+	config := map[string]tftypes.Value{
+		"name":     tftypes.NewValue(tftypes.String, "ci-pipeline"),
+		"timeouts": harness.timeoutsValue(map[string]string{"create": "20ms", "read": "20ms", "update": "20ms", "delete": "20ms"}),
+	}
+
+	standIn.delay("POST /organizations/"+fakeOrganizationID+"/service-accounts", 200*time.Millisecond)
+	_, diagnostics := harness.apply(config, harness.nullState())
+	expectDiagnosticError(t, diagnostics, "The create of the machine account did not finish within the timeout of 20ms")
+	standIn.delay("POST /organizations/"+fakeOrganizationID+"/service-accounts", 0)
+
+	state, diagnostics := harness.apply(config, harness.nullState())
+	expectDiagnosticError(t, diagnostics, "")
+	id := harness.stringAttribute(state, "id")
+
+	standIn.delay("GET /service-accounts/"+id, 200*time.Millisecond)
+	_, diagnostics = harness.read(state)
+	expectDiagnosticError(t, diagnostics, "The read of the machine account did not finish")
+
+	standIn.delay("PUT /service-accounts/"+id, 200*time.Millisecond)
+	config["name"] = tftypes.NewValue(tftypes.String, "renamed")
+	_, diagnostics = harness.apply(config, state)
+	expectDiagnosticError(t, diagnostics, "The update of the machine account did not finish")
+
+	standIn.delay("POST /service-accounts/delete", 200*time.Millisecond)
+	_, diagnostics = harness.destroy(state)
+	expectDiagnosticError(t, diagnostics, "The delete of the machine account did not finish")
+}
+
+func TestMachineAccountAccessTokenResourceTimeouts(t *testing.T) {
+	standIn := newMachineAccountStandIn(t)
+	account, err := standIn.client().MachineAccounts().Create(context.Background(), fakeOrganizationID, "ci-pipeline")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	harness := newAPIResourceHarness(t, "bitwarden-sm_machine_account_access_token", standIn.apiStandIn)
+
+	config := map[string]tftypes.Value{
+		"machine_account_id": tftypes.NewValue(tftypes.String, account.ID),
+		"name":               tftypes.NewValue(tftypes.String, "deploy"),
+		"timeouts":           harness.timeoutsValue(map[string]string{"create": "20ms", "read": "20ms", "delete": "20ms"}),
+	}
+	accessTokens := "/service-accounts/" + account.ID + "/access-tokens"
+
+	standIn.delay("POST "+accessTokens, 200*time.Millisecond)
+	_, diagnostics := harness.apply(config, harness.nullState())
+	expectDiagnosticError(t, diagnostics, "The create of the machine account access token did not finish within the timeout of 20ms")
+	standIn.delay("POST "+accessTokens, 0)
+
+	state, diagnostics := harness.apply(config, harness.nullState())
+	expectDiagnosticError(t, diagnostics, "")
+
+	// Changing only the timeouts keeps the access token.
+	config["timeouts"] = harness.timeoutsValue(map[string]string{"create": "20ms", "read": "20ms", "delete": "30ms"})
+	updated, diagnostics := harness.apply(config, state)
+	expectDiagnosticError(t, diagnostics, "")
+	if harness.stringAttribute(updated, "id") != harness.stringAttribute(state, "id") {
+		t.Fatal("expected the access token to be kept")
+	}
+
+	standIn.delay("GET "+accessTokens, 200*time.Millisecond)
+	_, diagnostics = harness.read(updated)
+	expectDiagnosticError(t, diagnostics, "The read of the machine account access token did not finish")
+
+	standIn.delay("POST "+accessTokens+"/revoke", 200*time.Millisecond)
+	_, diagnostics = harness.destroy(updated)
+	expectDiagnosticError(t, diagnostics, "The delete of the machine account access token did not finish within the timeout of 30ms")
+}
+
+func TestProjectAccessResourceTimeouts(t *testing.T) {
+	standIn := newAccessPolicyStandIn(t)
+	harness := newAPIResourceHarness(t, "bitwarden-sm_project_machine_account_access", standIn.apiStandIn)
+
+	config := map[string]tftypes.Value{
+		"project_id":         tftypes.NewValue(tftypes.String, validProjectUUID),
+		"machine_account_id": tftypes.NewValue(tftypes.String, "5d5a8a06-2d3b-4f0e-9a39-b2a600f0c1f4"),
+		"timeouts":           harness.timeoutsValue(map[string]string{"create": "20ms", "read": "20ms", "update": "20ms", "delete": "20ms"}),
+	}
+	policies := "/projects/" + validProjectUUID + "/access-policies/service-accounts"
+
+	standIn.delay("GET "+policies, 200*time.Millisecond)
+	_, diagnostics := harness.apply(config, harness.nullState())
+	expectDiagnosticError(t, diagnostics, "The create of the project access did not finish within the timeout of 20ms")
+	standIn.delay("GET "+policies, 0)
+
+	state, diagnostics := harness.apply(config, harness.nullState())
+	expectDiagnosticError(t, diagnostics, "")
+
+	standIn.delay("GET "+policies, 200*time.Millisecond)
+	_, diagnostics = harness.read(state)
+	expectDiagnosticError(t, diagnostics, "The read of the project access did not finish")
+
+	config["write"] = tftypes.NewValue(tftypes.Bool, true)
+	_, diagnostics = harness.apply(config, state)
+	expectDiagnosticError(t, diagnostics, "The update of the project access did not finish")
+
+	_, diagnostics = harness.destroy(state)
+	expectDiagnosticError(t, diagnostics, "The delete of the project access did not finish")
+}
@@ -0,0 +1,60 @@
+package provider
+
+import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"strings"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ function.Function = &toDotenvFunction{}
+
+// NewToDotenvFunction is a helper function to simplify the provider implementation.
+func NewToDotenvFunction() function.Function {
+	return &toDotenvFunction{}
+}
+
+// toDotenvFunction defines the function implementation.
+type toDotenvFunction struct{}
+
+func (f *toDotenvFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "to_dotenv"
+}
+
+func (f *toDotenvFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:             "Renders secrets as .env file",
+		Description:         "Renders a map of secret keys to values as .env file with one KEY='value' line per secret, sorted by key. Keys are sanitised to environment variable names: letters are uppercased, all other characters than A-Z, 0-9 and _ are replaced by _, and keys starting with a digit are prefixed with _. Values are single-quoted, so dotenv parsers and shells do not expand $VAR or backticks in them. Values with single quotes, backslashes or line breaks are double-quoted instead, with backslashes, double quotes, dollar signs, backticks and line breaks escaped with a backslash. Some parsers, e.g. godotenv, misread double-quoted values which end with a backslash or a double quote.",
+		MarkdownDescription: "Renders a map of secret keys to values as `.env` file with one `KEY='value'` line per secret, sorted by key. Keys are sanitised to environment variable names: letters are uppercased, all other characters than `A-Z`, `0-9` and `_` are replaced by `_`, and keys starting with a digit are prefixed with `_`. Values are single-quoted, so dotenv parsers and shells do not expand `$VAR` or backticks in them. Values with single quotes, backslashes or line breaks are double-quoted instead, with backslashes, double quotes, dollar signs, backticks and line breaks escaped with a backslash. Some parsers, e.g. godotenv, misread double-quoted values which end with a backslash or a double quote.",
+		Parameters: []function.Parameter{
+			function.MapParameter{
+				Name:        "secrets",
+				Description: "Map of secret keys to secret values. Keys which are equal after sanitisation are rejected.",
+				ElementType: types.StringType,
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *toDotenvFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var secrets map[string]types.String
+	resp.Error = req.Arguments.Get(ctx, &secrets)
+	if resp.Error != nil {
+		return
+	}
+
+	sanitized, err := sanitizeEnvSecrets(secrets)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+
+	var builder strings.Builder
+	for _, secret := range sanitized {
+		builder.WriteString(secret.Key + "=" + dotenvQuote(secret.Value) + "\n")
+	}
+
+	resp.Error = resp.Result.Set(ctx, builder.String())
+}
@@ -0,0 +1,72 @@
+package provider
+
+import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"strings"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ function.Function = &toEnvJSONFunction{}
+
+// NewToEnvJSONFunction is a helper function to simplify the provider implementation.
+func NewToEnvJSONFunction() function.Function {
+	return &toEnvJSONFunction{}
+}
+
+// toEnvJSONFunction defines the function implementation.
+type toEnvJSONFunction struct{}
+
+func (f *toEnvJSONFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "to_env_json"
+}
+
+func (f *toEnvJSONFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:             "Renders secrets as JSON object of environment variables",
+		Description:         "Renders a map of secret keys to values as indented JSON object, sorted by key. Keys are sanitised to environment variable names like in to_dotenv. Unlike jsonencode, the characters <, > and & are not escaped.",
+		MarkdownDescription: "Renders a map of secret keys to values as indented JSON object, sorted by key. Keys are sanitised to environment variable names like in `to_dotenv`. Unlike `jsonencode`, the characters `<`, `>` and `&` are not escaped.",
+		Parameters: []function.Parameter{
+			function.MapParameter{
+				Name:        "secrets",
+				Description: "Map of secret keys to secret values. Keys which are equal after sanitisation are rejected.",
+				ElementType: types.StringType,
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *toEnvJSONFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var secrets map[string]types.String
+	resp.Error = req.Arguments.Get(ctx, &secrets)
+	if resp.Error != nil {
+		return
+	}
+
+	sanitized, err := sanitizeEnvSecrets(secrets)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+
+	if len(sanitized) == 0 {
+		resp.Error = resp.Result.Set(ctx, "{}\n")
+		return
+	}
+
+	// The object is written by hand to keep the sanitised order and to avoid escaping HTML characters.
+	var builder strings.Builder
+	builder.WriteString("{\n")
+	for i, secret := range sanitized {
+		builder.WriteString("  " + jsonQuote(secret.Key) + ": " + jsonQuote(secret.Value))
+		if i < len(sanitized)-1 {
+			builder.WriteString(",")
+		}
+		builder.WriteString("\n")
+	}
+	builder.WriteString("}\n")
+
+	resp.Error = resp.Result.Set(ctx, builder.String())
+}
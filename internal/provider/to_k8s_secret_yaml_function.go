@@ -0,0 +1,80 @@
+package provider
+
+import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"strings"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ function.Function = &toK8sSecretYAMLFunction{}
+
+// NewToK8sSecretYAMLFunction is a helper function to simplify the provider implementation.
+func NewToK8sSecretYAMLFunction() function.Function {
+	return &toK8sSecretYAMLFunction{}
+}
+
+// toK8sSecretYAMLFunction defines the function implementation.
+type toK8sSecretYAMLFunction struct{}
+
+func (f *toK8sSecretYAMLFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "to_k8s_secret_yaml"
+}
+
+func (f *toK8sSecretYAMLFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:             "Renders secrets as Kubernetes Secret manifest",
+		Description:         "Renders a map of secret keys to values as YAML manifest of an Opaque Kubernetes Secret with the secrets in stringData, sorted by key. Keys are sanitised to environment variable names like in to_dotenv, so the Secret can be used with envFrom. All strings are written as double-quoted scalars.",
+		MarkdownDescription: "Renders a map of secret keys to values as YAML manifest of an `Opaque` Kubernetes Secret with the secrets in `stringData`, sorted by key. Keys are sanitised to environment variable names like in `to_dotenv`, so the Secret can be used with `envFrom`. All strings are written as double-quoted scalars.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "name",
+				Description: "Name of the Kubernetes Secret. It must be a valid Kubernetes object name.",
+			},
+			function.MapParameter{
+				Name:        "secrets",
+				Description: "Map of secret keys to secret values. Keys which are equal after sanitisation are rejected.",
+				ElementType: types.StringType,
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *toK8sSecretYAMLFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var name string
+	var secrets map[string]types.String
+	resp.Error = req.Arguments.Get(ctx, &name, &secrets)
+	if resp.Error != nil {
+		return
+	}
+
+	if err := validateKubernetesName(name); err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+
+	sanitized, err := sanitizeEnvSecrets(secrets)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(1, err.Error())
+		return
+	}
+
+	var builder strings.Builder
+	builder.WriteString("apiVersion: v1\n")
+	builder.WriteString("kind: Secret\n")
+	builder.WriteString("metadata:\n")
+	builder.WriteString("  name: " + jsonQuote(name) + "\n")
+	builder.WriteString("type: Opaque\n")
+	if len(sanitized) == 0 {
+		builder.WriteString("stringData: {}\n")
+	} else {
+		builder.WriteString("stringData:\n")
+		for _, secret := range sanitized {
+			builder.WriteString("  " + jsonQuote(secret.Key) + ": " + jsonQuote(secret.Value) + "\n")
+		}
+	}
+
+	resp.Error = resp.Result.Set(ctx, builder.String())
+}
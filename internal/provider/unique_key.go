@@ -0,0 +1,92 @@
+package provider
+
+import (
+	"fmt"
+	"github.com/bitwarden/sdk-go"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"strings"
+)
+
+const (
+	// uniqueKeyOff creates secrets regardless of existing secrets with the same key.
+	uniqueKeyOff = "off"
+	// uniqueKeyError fails if a secret with the same key already exists in the project.
+	uniqueKeyError = "error"
+	// uniqueKeyAdoptExisting takes over an existing secret with the same key instead of creating a duplicate.
+	uniqueKeyAdoptExisting = "adopt_existing"
+)
+
+var uniqueKeyModes = []string{uniqueKeyOff, uniqueKeyError, uniqueKeyAdoptExisting}
+
+// findSecretsWithKey fetches all secrets with the given key which belong to the given project, or to no project if
+// projectId is nil. The key is matched exactly, since Bitwarden Secrets Manager treats keys as case-sensitive.
+func findSecretsWithKey(bitwardenClient sdk.BitwardenClientInterface, organizationId string, key string, projectId *string) ([]sdk.SecretResponse, error) {
+	identifiers, err := bitwardenClient.Secrets().List(organizationId)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []string
+	for _, identifier := range identifiers.Data {
+		if identifier.Key == key {
+			ids = append(ids, identifier.ID)
+		}
+	}
+
+	// The identifiers do not contain the project, so only the candidates are fetched to compare it.
+	candidates, err := getSecretsByIDs(bitwardenClient, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	secrets := []sdk.SecretResponse{}
+	for _, secret := range candidates {
+		sameProject := (projectId == nil && secret.ProjectID == nil) ||
+			(projectId != nil && secret.ProjectID != nil && *projectId == *secret.ProjectID)
+		if sameProject {
+			secrets = append(secrets, secret)
+		}
+	}
+
+	return secrets, nil
+}
+
+// checkUniqueKey reports existing secrets with the key of a secret to be created according to mode. It returns the
+// secret to adopt if mode is adopt_existing and exactly one such secret exists.
+func checkUniqueKey(mode string, key string, existing []sdk.SecretResponse) (*sdk.SecretResponse, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	if mode == uniqueKeyOff || len(existing) == 0 {
+		return nil, diags
+	}
+
+	ids := make([]string, 0, len(existing))
+	for _, secret := range existing {
+		ids = append(ids, secret.ID)
+	}
+
+	if mode == uniqueKeyError {
+		diags.AddAttributeError(
+			path.Root("key"),
+			"Secret Key Already Exists",
+			fmt.Sprintf("The key %q is already used by the secret with id %s in the same project. "+
+				"Import the existing secret with terraform import, choose another key or set enforce_unique_key to \"adopt_existing\" to take over the existing secret.",
+				key, strings.Join(ids, ", ")),
+		)
+		return nil, diags
+	}
+
+	if len(existing) > 1 {
+		diags.AddAttributeError(
+			path.Root("key"),
+			"Secret Key Is Ambiguous",
+			fmt.Sprintf("The key %q is used by the secrets with ids %s in the same project, so no secret can be adopted. "+
+				"Remove the duplicates or import one of them with terraform import.",
+				key, strings.Join(ids, ", ")),
+		)
+		return nil, diags
+	}
+
+	return &existing[0], diags
+}
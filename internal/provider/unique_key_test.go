@@ -0,0 +1,69 @@
+package provider
+
+import (
+	"github.com/bitwarden/sdk-go"
+	"strings"
+	"testing"
+)
+
+func TestCheckUniqueKey(t *testing.T) {
+	one := []sdk.SecretResponse{{ID: "3b2c3d6e-0000-0000-0000-000000000001", Key: "API_KEY"}}
+	two := append([]sdk.SecretResponse{{ID: "3b2c3d6e-0000-0000-0000-000000000002", Key: "API_KEY"}}, one...)
+
+	tests := map[string]struct {
+		mode     string
+		existing []sdk.SecretResponse
+		adopted  string
+		expected string
+	}{
+		"off ignores duplicates": {
+			mode:     uniqueKeyOff,
+			existing: two,
+		},
+		"error without duplicates": {
+			mode: uniqueKeyError,
+		},
+		"error names the existing secret": {
+			mode:     uniqueKeyError,
+			existing: one,
+			expected: `The key "API_KEY" is already used by the secret with id 3b2c3d6e-0000-0000-0000-000000000001 in the same project.`,
+		},
+		"adopt without duplicates": {
+			mode: uniqueKeyAdoptExisting,
+		},
+		"adopt the existing secret": {
+			mode:     uniqueKeyAdoptExisting,
+			existing: one,
+			adopted:  "3b2c3d6e-0000-0000-0000-000000000001",
+		},
+		"adopt fails for several secrets": {
+			mode:     uniqueKeyAdoptExisting,
+			existing: two,
+			expected: "3b2c3d6e-0000-0000-0000-000000000002, 3b2c3d6e-0000-0000-0000-000000000001 in the same project, so no secret can be adopted",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			adopted, diags := checkUniqueKey(test.mode, "API_KEY", test.existing)
+
+			if test.expected != "" {
+				if !diags.HasError() || !strings.Contains(diags.Errors()[0].Detail(), test.expected) {
+					t.Fatalf("expected an error containing %q, got: %v", test.expected, diags)
+				}
+				return
+			}
+			if diags.HasError() {
+				t.Fatalf("unexpected error: %v", diags)
+			}
+
+			adoptedID := ""
+			if adopted != nil {
+				adoptedID = adopted.ID
+			}
+			if adoptedID != test.adopted {
+				t.Fatalf("expected to adopt %q, got %q", test.adopted, adoptedID)
+			}
+		})
+	}
+}
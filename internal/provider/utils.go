@@ -3,8 +3,12 @@ package provider
 import (
 	"fmt"
 	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"golang.org/x/net/context"
+	"strings"
+	"time"
 )
 
 var _ validator.String = &stringUUIDValidator{}
@@ -25,7 +29,7 @@ func (v stringUUIDValidator) ValidateString(_ context.Context, req validator.Str
 		return
 	}
 
-	if err := uuid.Validate(req.ConfigValue.ValueString()); err != nil {
+	if !isUUID(req.ConfigValue.ValueString()) {
 		resp.Diagnostics.AddAttributeError(
 			req.Path,
 			"string attribute not a valid UUID",
@@ -37,3 +41,210 @@ func (v stringUUIDValidator) ValidateString(_ context.Context, req validator.Str
 func stringUUIDValidate() stringUUIDValidator {
 	return stringUUIDValidator{}
 }
+
+// isUUID reports whether value is a valid UUID, it is shared by the UUID validator and the is_uuid function.
+func isUUID(value string) bool {
+	return uuid.Validate(value) == nil
+}
+
+var _ validator.Int64 = &generatorOptionsValidator{}
+
+// generatorOptionsValidator validates that the combination of all secret generator attributes can be satisfied.
+type generatorOptionsValidator struct{}
+
+func (v generatorOptionsValidator) Description(_ context.Context) string {
+	return "the secret generator configuration must be satisfiable: every enabled character class needs at least one usable character and the length must cover all minimums"
+}
+
+func (v generatorOptionsValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v generatorOptionsValidator) ValidateInt64(ctx context.Context, req validator.Int64Request, resp *validator.Int64Response) {
+	var config secretResourceModel
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The generator is not used if the value is provided explicitly.
+	if !config.Value.IsNull() || !config.ValueJSON.IsNull() {
+		return
+	}
+
+	// If any generator attribute is unknown, there is nothing to validate yet.
+	if hasUnknownGeneratorConfig(&config) {
+		return
+	}
+
+	applyGeneratorDefaults(&config)
+
+	if err := newPasswordGeneratorOptions(&config).validate(); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Unsatisfiable secret generator configuration",
+			fmt.Sprintf("The secret generator cannot create a value for the given configuration: %s", err.Error()),
+		)
+	}
+}
+
+func generatorOptionsValidate() generatorOptionsValidator {
+	return generatorOptionsValidator{}
+}
+
+var _ validator.Dynamic = &jsonValueValidator{}
+
+// jsonValueValidator validates that a value_json attribute provided as string contains a valid JSON document.
+type jsonValueValidator struct{}
+
+func (v jsonValueValidator) Description(_ context.Context) string {
+	return "the value must be a structured value or a string containing a valid JSON document"
+}
+
+func (v jsonValueValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v jsonValueValidator) ValidateDynamic(_ context.Context, req validator.DynamicRequest, resp *validator.DynamicResponse) {
+	// If the value is unknown or null, there is nothing to validate.
+	if req.ConfigValue.IsUnknown() || req.ConfigValue.IsNull() || req.ConfigValue.IsUnderlyingValueUnknown() {
+		return
+	}
+
+	stringValue, ok := req.ConfigValue.UnderlyingValue().(types.String)
+	if !ok || stringValue.IsUnknown() {
+		return
+	}
+
+	if _, err := normaliseJSON(stringValue.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"string attribute not a valid JSON document",
+			err.Error(),
+		)
+	}
+}
+
+func jsonValueValidate() jsonValueValidator {
+	return jsonValueValidator{}
+}
+
+var _ validator.Map = &mapKeysSubsetOfValidator{}
+
+// mapKeysSubsetOfValidator validates that every key of a map attribute is also a key of another map attribute,
+// e.g. that a note is only configured for secrets that exist.
+type mapKeysSubsetOfValidator struct {
+	other path.Path
+}
+
+func (v mapKeysSubsetOfValidator) Description(_ context.Context) string {
+	return fmt.Sprintf("every key must also be a key of %s", v.other)
+}
+
+func (v mapKeysSubsetOfValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v mapKeysSubsetOfValidator) ValidateMap(ctx context.Context, req validator.MapRequest, resp *validator.MapResponse) {
+	// If the value is unknown or null, there is nothing to validate.
+	if req.ConfigValue.IsUnknown() || req.ConfigValue.IsNull() {
+		return
+	}
+
+	var other types.Map
+	diags := req.Config.GetAttribute(ctx, v.other, &other)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() || other.IsUnknown() {
+		return
+	}
+
+	otherElements := other.Elements()
+	for key := range req.ConfigValue.Elements() {
+		if _, ok := otherElements[key]; !ok {
+			resp.Diagnostics.AddAttributeError(
+				req.Path.AtMapKey(key),
+				"map key not found",
+				fmt.Sprintf("the key %q is not a key of %s", key, v.other),
+			)
+		}
+	}
+}
+
+func mapKeysSubsetOfValidate(other path.Path) mapKeysSubsetOfValidator {
+	return mapKeysSubsetOfValidator{other: other}
+}
+
+var _ validator.String = &stringRFC3339Validator{}
+
+type stringRFC3339Validator struct{}
+
+func (v stringRFC3339Validator) Description(_ context.Context) string {
+	return "the string parameter must be a timestamp in RFC3339 format"
+}
+
+func (v stringRFC3339Validator) MarkdownDescription(_ context.Context) string {
+	return "the string parameter must be a timestamp in [RFC3339](https://datatracker.ietf.org/doc/html/rfc3339) format, e.g. `2030-01-31T00:00:00Z`"
+}
+
+func (v stringRFC3339Validator) ValidateString(_ context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	// If the value is unknown or null, there is nothing to validate.
+	if req.ConfigValue.IsUnknown() || req.ConfigValue.IsNull() {
+		return
+	}
+
+	if _, err := time.Parse(time.RFC3339, req.ConfigValue.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"string attribute not a valid RFC3339 timestamp",
+			fmt.Sprintf("the provided string: %s is not a valid RFC3339 timestamp", req.ConfigValue.ValueString()),
+		)
+	}
+}
+
+func stringRFC3339Validate() stringRFC3339Validator {
+	return stringRFC3339Validator{}
+}
+
+var _ validator.String = &keyNamingPolicyValidator{}
+
+// keyNamingPolicyValidator validates that a secret key satisfies a key naming policy of the provider or a resource.
+type keyNamingPolicyValidator struct {
+	policy *keyNamingPolicy
+}
+
+func (v keyNamingPolicyValidator) Description(_ context.Context) string {
+	return "the key must satisfy the key_naming_policy"
+}
+
+func (v keyNamingPolicyValidator) MarkdownDescription(_ context.Context) string {
+	return "the key must satisfy the `key_naming_policy`"
+}
+
+func (v keyNamingPolicyValidator) ValidateString(_ context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	// If the value is unknown or null, there is nothing to validate.
+	if req.ConfigValue.IsUnknown() || req.ConfigValue.IsNull() || v.policy == nil {
+		return
+	}
+
+	key := req.ConfigValue.ValueString()
+	violations := v.policy.violations(key)
+	if len(violations) == 0 {
+		return
+	}
+
+	detail := fmt.Sprintf("The key %q violates the key_naming_policy of the %s: %s.", key, v.policy.owner, strings.Join(violations, ", "))
+	if normalised, ok := v.policy.normalise(key); ok {
+		detail += fmt.Sprintf(" Use %q instead.", normalised)
+	}
+
+	resp.Diagnostics.AddAttributeError(
+		req.Path,
+		"Secret Key Violates Naming Policy",
+		detail,
+	)
+}
+
+func keyNamingPolicyValidate(policy *keyNamingPolicy) keyNamingPolicyValidator {
+	return keyNamingPolicyValidator{policy: policy}
+}
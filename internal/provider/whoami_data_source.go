@@ -0,0 +1,297 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/bitwarden/sdk-go"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/datasource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"sync"
+	"time"
+)
+
+// maxConcurrentPermissionReads limits the requests which read the permissions of the machine account on its projects,
+// the API has no endpoint which returns the permissions of all projects at once.
+const maxConcurrentPermissionReads = 8
+
+var (
+	// Ensure provider defined types fully satisfy framework interfaces.
+	_ datasource.DataSource              = &whoamiDataSource{}
+	_ datasource.DataSourceWithConfigure = &whoamiDataSource{}
+)
+
+func NewWhoamiDataSource() datasource.DataSource {
+	return &whoamiDataSource{}
+}
+
+// whoamiDataSource defines the data source implementation.
+type whoamiDataSource struct {
+	bitwardenClient sdk.BitwardenClientInterface
+	apiClient       func() (bitwardenAPIClient, error)
+	organizationId  string
+}
+
+// whoamiDataSourceModel describes the data source data model.
+type whoamiDataSourceModel struct {
+	MachineAccountID types.String         `tfsdk:"machine_account_id"`
+	AccessTokenID    types.String         `tfsdk:"access_token_id"`
+	OrganizationID   types.String         `tfsdk:"organization_id"`
+	SessionExpiresAt types.String         `tfsdk:"session_expires_at"`
+	Projects         []whoamiProjectModel `tfsdk:"projects"`
+
+	Timeouts timeouts.Value `tfsdk:"timeouts"`
+}
+
+type whoamiProjectModel struct {
+	ID    types.String `tfsdk:"id"`
+	Name  types.String `tfsdk:"name"`
+	Read  types.Bool   `tfsdk:"read"`
+	Write types.Bool   `tfsdk:"write"`
+}
+
+func (d *whoamiDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_whoami"
+}
+
+func (d *whoamiDataSource) Schema(ctx context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description:         "The whoami data source describes the machine account the provider is authenticated as, including the projects it can access and its permissions on them. Use it in precondition blocks to assert that a configuration runs with the expected identity.",
+		MarkdownDescription: "The `whoami` data source describes the machine account the provider is authenticated as, including the projects it can access and its permissions on them. Use it in `precondition` blocks to assert that a configuration runs with the expected identity.",
+		Attributes: map[string]schema.Attribute{
+			"machine_account_id": schema.StringAttribute{
+				Description:         "String representation of the ID of the authenticated machine account.",
+				MarkdownDescription: "String representation of the `ID` of the authenticated machine account.",
+				Computed:            true,
+			},
+			"access_token_id": schema.StringAttribute{
+				Description:         "String representation of the ID of the access token used by the provider.",
+				MarkdownDescription: "String representation of the `ID` of the access token used by the provider.",
+				Computed:            true,
+			},
+			"organization_id": schema.StringAttribute{
+				Description:         "String representation of the ID of the organization to which the machine account belongs.",
+				MarkdownDescription: "String representation of the `ID` of the organization to which the machine account belongs.",
+				Computed:            true,
+			},
+			"session_expires_at": schema.StringAttribute{
+				Description:         "Expiration date in RFC3339 format of the session token which the identity service issued when the provider logged in with the access token. The provider renews the session token automatically. This is not the expiration date of the access token itself, which machine accounts cannot read, see the expires_at attribute of the machine_account_access_token resource.",
+				MarkdownDescription: "Expiration date in RFC3339 format of the session token which the identity service issued when the provider logged in with the access token. The provider renews the session token automatically. This is not the expiration date of the access token itself, which machine accounts cannot read, see the `expires_at` attribute of the `machine_account_access_token` resource.",
+				Computed:            true,
+			},
+			"projects": schema.ListNestedAttribute{
+				Description: "Nested list of all projects accessible by the machine account.",
+				Computed:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							Description:         "String representation of the ID of the project inside Bitwarden Secrets Manager.",
+							MarkdownDescription: "String representation of the `ID` of the project inside Bitwarden Secrets Manager.",
+							Computed:            true,
+						},
+						"name": schema.StringAttribute{
+							Description:         "String representation of the name of the project inside Bitwarden Secrets Manager.",
+							MarkdownDescription: "String representation of the `name` of the project inside Bitwarden Secrets Manager.",
+							Computed:            true,
+						},
+						"read": schema.BoolAttribute{
+							Description: "Whether the machine account can read the secrets of the project.",
+							Computed:    true,
+						},
+						"write": schema.BoolAttribute{
+							Description: "Whether the machine account can write the secrets of the project.",
+							Computed:    true,
+						},
+					},
+				},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx),
+		},
+	}
+}
+
+func (d *whoamiDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Add a nil check when handling BitwardenSecretsManagerProviderDataStruct because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	tflog.Info(ctx, "Configuring Datasource")
+	if req.ProviderData == nil {
+		tflog.Debug(ctx, "Skipping Datasource Configuration because Provider has not been configured yet.")
+		return
+	}
+
+	providerDataStruct, ok := req.ProviderData.(BitwardenSecretsManagerProviderDataStruct)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected sdk.BitwardenClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	if providerDataStruct.bitwardenClient == nil || providerDataStruct.apiClient == nil {
+		resp.Diagnostics.AddError(
+			"Client Not Initialized",
+			"The Bitwarden client was not properly initialized due to a missing Bitwarden API Client.",
+		)
+		return
+	}
+
+	if providerDataStruct.organizationId == "" {
+		resp.Diagnostics.AddError(
+			"Client Not Initialized",
+			"The Bitwarden client was not properly initialized due to an empty Organization ID.",
+		)
+		return
+	}
+
+	d.bitwardenClient = providerDataStruct.bitwardenClient
+	d.apiClient = providerDataStruct.apiClient
+	d.organizationId = providerDataStruct.organizationId
+
+	tflog.Info(ctx, "Datasource Configured")
+}
+
+func (d *whoamiDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	tflog.Info(ctx, "Reading Whoami Datasource")
+
+	if d.bitwardenClient == nil {
+		resp.Diagnostics.AddError(
+			"Client Not Initialized",
+			"The Bitwarden client was not properly initialized.",
+		)
+		return
+	}
+	apiClient := configuredAPIClient(d.apiClient, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var readTimeouts timeouts.Value
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("timeouts"), &readTimeouts)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, client, done := withClientTimeout(ctx, d.bitwardenClient, readTimeouts.Read, "read", "the whoami data source", &resp.Diagnostics)
+	defer done()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	identity, err := apiClient.Identity(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read Identity",
+			err.Error(),
+		)
+		return
+	}
+
+	organizationId := identity.OrganizationID
+	if organizationId == "" {
+		organizationId = d.organizationId
+	} else if organizationId != d.organizationId {
+		resp.Diagnostics.AddWarning(
+			"Organization Mismatch",
+			fmt.Sprintf("The machine account belongs to the organization %s, but the provider is configured for the organization %s.", organizationId, d.organizationId),
+		)
+	}
+
+	state := whoamiDataSourceModel{
+		MachineAccountID: types.StringValue(identity.MachineAccountID),
+		AccessTokenID:    types.StringValue(identity.AccessTokenID),
+		OrganizationID:   types.StringValue(organizationId),
+		SessionExpiresAt: types.StringValue(identity.ExpiresAt.Format(time.RFC3339)),
+		Projects:         []whoamiProjectModel{},
+		Timeouts:         readTimeouts,
+	}
+
+	projects, err := client.Projects().List(d.organizationId)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to List Projects",
+			err.Error(),
+		)
+		return
+	}
+
+	projectIDs := make([]string, 0, len(projects.Data))
+	for _, project := range projects.Data {
+		projectIDs = append(projectIDs, project.ID)
+	}
+	permissions, err := readProjectPermissions(ctx, apiClient, projectIDs)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read Project Permissions",
+			err.Error(),
+		)
+		return
+	}
+
+	for i, project := range projects.Data {
+		permission := permissions[i]
+		state.Projects = append(state.Projects, whoamiProjectModel{
+			ID:    types.StringValue(project.ID),
+			Name:  types.StringValue(project.Name),
+			Read:  types.BoolValue(permission.Read),
+			Write: types.BoolValue(permission.Write),
+		})
+	}
+
+	// Set state
+	diags := resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// readProjectPermissions reads the permissions of the authenticated machine account on the projects, with at most
+// maxConcurrentPermissionReads requests at a time. The permissions are returned in the order of the projects. After
+// the first error, the remaining requests are cancelled.
+func readProjectPermissions(ctx context.Context, apiClient bitwardenAPIClient, projectIDs []string) ([]*projectPermission, error) {
+	tflog.Debug(ctx, "Reading project permissions", map[string]any{"projects": len(projectIDs)})
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	permissions := make([]*projectPermission, len(projectIDs))
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+	)
+	semaphore := make(chan struct{}, maxConcurrentPermissionReads)
+	for i, projectID := range projectIDs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			permission, err := apiClient.ProjectPermission(ctx, projectID)
+			if err != nil {
+				mu.Lock()
+				defer mu.Unlock()
+				if firstErr == nil {
+					firstErr = fmt.Errorf("unable to read the permission on project %s: %w", projectID, err)
+					cancel()
+				}
+				return
+			}
+			permissions[i] = permission
+		}()
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return permissions, nil
+}
@@ -0,0 +1,113 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestAccDatasourceWhoami(t *testing.T) {
+	skipWithFakeClient(t)
+
+	var projectId string
+	projectName := "Test-Project-" + generateRandomString()
+	bitwardenClient, organizationId, err := newBitwardenClient()
+	if err != nil {
+		t.Fatalf("Error creating bitwardenClient: %s", err)
+	}
+	runProviderTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		PreCheck: func() {
+			project, preCheckErr := bitwardenClient.Projects().Create(organizationId, projectName)
+			if preCheckErr != nil {
+				t.Fatal("Error creating test project for provider validation.")
+			}
+			projectId = project.ID
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: buildProviderConfigFromEnvFile(t) + `
+                       data "bitwarden-sm_whoami" "test" {}`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchResourceAttr("data.bitwarden-sm_whoami.test", "machine_account_id", regexp.MustCompile(`^[0-9a-f-]{36}$`)),
+					resource.TestMatchResourceAttr("data.bitwarden-sm_whoami.test", "access_token_id", regexp.MustCompile(`^[0-9a-f-]{36}$`)),
+					resource.TestCheckResourceAttr("data.bitwarden-sm_whoami.test", "organization_id", organizationId),
+					resource.TestCheckResourceAttrSet("data.bitwarden-sm_whoami.test", "session_expires_at"),
+					// The machine account created the project, so it has read and write access.
+					func(s *terraform.State) error {
+						return resource.TestCheckTypeSetElemNestedAttrs("data.bitwarden-sm_whoami.test", "projects.*", map[string]string{
+							"id":    projectId,
+							"name":  projectName,
+							"read":  "true",
+							"write": "true",
+						})(s)
+					},
+				),
+			},
+		},
+		CheckDestroy: func(state *terraform.State) error {
+			_, cleanUpErr := bitwardenClient.Projects().Delete([]string{projectId})
+			if cleanUpErr != nil {
+				t.Fatalf("Error cleaning up test project: %s", cleanUpErr)
+			}
+			return nil
+		},
+	})
+}
+
+func TestReadProjectPermissions(t *testing.T) {
+	standIn := newAPIStandIn(t)
+	var (
+		mu          sync.Mutex
+		inFlight    int
+		maxInFlight int
+	)
+	standIn.handle("GET /projects/{id}", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		inFlight++
+		maxInFlight = max(maxInFlight, inFlight)
+		mu.Unlock()
+		defer func() {
+			mu.Lock()
+			inFlight--
+			mu.Unlock()
+		}()
+
+		time.Sleep(10 * time.Millisecond)
+		if r.PathValue("id") == invalidProjectUUID1 {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"id": r.PathValue("id"), "read": true, "write": strings.HasSuffix(r.PathValue("id"), "0")})
+	})
+	client := standIn.client()
+
+	projectIDs := []string{}
+	for i := 0; i < 3*maxConcurrentPermissionReads; i++ {
+		projectIDs = append(projectIDs, fmt.Sprintf("00000000-0000-0000-0000-%012d", i))
+	}
+	permissions, err := readProjectPermissions(context.Background(), client, projectIDs)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	for i, permission := range permissions {
+		if *permission != (projectPermission{Read: true, Write: i%10 == 0}) {
+			t.Fatalf("unexpected permission on project %s: %+v", projectIDs[i], permission)
+		}
+	}
+	if maxInFlight < 2 || maxInFlight > maxConcurrentPermissionReads {
+		t.Fatalf("expected between 2 and %d concurrent requests, got %d", maxConcurrentPermissionReads, maxInFlight)
+	}
+
+	_, err = readProjectPermissions(context.Background(), client, append(projectIDs, invalidProjectUUID1))
+	if err == nil || !strings.Contains(err.Error(), invalidProjectUUID1) || !isNotFound(err) {
+		t.Fatalf("expected a not found error for project %s, got: %v", invalidProjectUUID1, err)
+	}
+}